@@ -0,0 +1,120 @@
+// Command mulint-fixgen runs the analyzer on fixture packages and updates
+// their "// want" comments to match the reported diagnostics.
+//
+// Usage:
+//
+//	mulint-fixgen [-w] [-codes MU001,...] [analyzer flags] <dir>...
+//
+// With -codes, only the diagnostics of the given checks are considered, as corpus suites do.
+// Without -w, the outdated files are listed and the command exits with status 1,
+// so it can be used to check that fixtures are up to date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/palkan/mulint/mulint"
+	"github.com/palkan/mulint/mulinttest"
+)
+
+var (
+	write = flag.Bool("w", false, "write the updated want comments to the files")
+	codes = flag.String("codes", "", "comma-separated codes of the checks to generate expectations for (e.g. \"MU001,MU002\"); all checks by default")
+)
+
+func main() {
+	mulint.Mulint.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: mulint-fixgen [-w] [-codes MU001,...] [analyzer flags] <dir>...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	outdated := false
+	for _, dir := range flag.Args() {
+		files, err := fixDir(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mulint-fixgen: %v\n", err)
+			os.Exit(1)
+		}
+		for _, file := range files {
+			outdated = true
+			if *write {
+				fmt.Printf("updated %s\n", file)
+			} else {
+				fmt.Printf("outdated %s\n", file)
+			}
+		}
+	}
+
+	if outdated && !*write {
+		os.Exit(1)
+	}
+}
+
+// fixDir updates the want comments of the Go files in the directory (writing them with -w)
+// and returns the names of the changed files.
+func fixDir(dir string) ([]string, error) {
+	var only []string
+	if *codes != "" {
+		only = strings.Split(*codes, ",")
+	}
+
+	findings, err := mulinttest.Analyze(dir, only, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		path, err := filepath.Abs(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := mulinttest.UpdateWants(path, src, findings[path])
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(src, updated) {
+			continue
+		}
+
+		if *write {
+			if err := os.WriteFile(path, updated, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		changed = append(changed, filepath.Join(dir, name))
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
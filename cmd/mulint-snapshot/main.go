@@ -0,0 +1,234 @@
+// Command mulint-snapshot runs the analyzer on real-world projects and compares the findings
+// with the stored snapshots, so that heuristic changes (e.g., wrapper inference or conditional
+// locks) can be validated against real code.
+//
+// Usage:
+//
+//	mulint-snapshot [-projects snapshots/projects.json] [-cache dir] [-update] [analyzer flags] [project...]
+//
+// Projects are cloned once per ref into the cache directory. Snapshots are stored next to
+// the projects file as <name>.txt. Without -update, the differences with the snapshots
+// are printed ("+" for new findings, "-" for gone ones) and the command exits with status 1
+// if there are any. A missing snapshot is recorded on the first run, to be reviewed and
+// committed.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/palkan/mulint/mulint"
+	"github.com/palkan/mulint/mulinttest"
+)
+
+var (
+	projectsFile = flag.String("projects", filepath.Join("snapshots", "projects.json"), "JSON file listing the projects to analyze")
+	cacheDir     = flag.String("cache", "", "directory to clone the projects into (defaults to the user cache directory)")
+	update       = flag.Bool("update", false, "write the current findings to the snapshots")
+)
+
+// project is a real-world project to analyze, pinned to a ref.
+type project struct {
+	Name     string   `json:"name"`
+	Repo     string   `json:"repo"`
+	Ref      string   `json:"ref"`
+	Packages []string `json:"packages"`
+}
+
+func main() {
+	mulint.Mulint.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+	flag.Parse()
+
+	projects, err := loadProjects(*projectsFile, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mulint-snapshot: %v\n", err)
+		os.Exit(1)
+	}
+
+	cache := *cacheDir
+	if cache == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mulint-snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		cache = filepath.Join(userCache, "mulint-snapshots")
+	}
+
+	changed := false
+	for _, p := range projects {
+		snapshot := filepath.Join(filepath.Dir(*projectsFile), p.Name+".txt")
+		diff, err := run(p, cache, snapshot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mulint-snapshot: %s: %v\n", p.Name, err)
+			os.Exit(1)
+		}
+		if len(diff) > 0 {
+			changed = true
+		}
+	}
+
+	if changed && !*update {
+		os.Exit(1)
+	}
+}
+
+// loadProjects reads the projects file and selects the projects with the given names (all by default).
+func loadProjects(path string, names []string) ([]project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var projects []project
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(names) == 0 {
+		return projects, nil
+	}
+
+	var selected []project
+	for _, name := range names {
+		idx := slices.IndexFunc(projects, func(p project) bool { return p.Name == name })
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown project: %s", name)
+		}
+		selected = append(selected, projects[idx])
+	}
+	return selected, nil
+}
+
+// run analyzes the project and compares (or updates) its snapshot, returning the differences.
+func run(p project, cache, snapshot string) ([]string, error) {
+	dir, err := checkout(p, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := p.Packages
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	findings, err := mulinttest.Analyze(dir, nil, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	current := renderFindings(dir, findings)
+
+	if *update {
+		if err := writeSnapshot(snapshot, current); err != nil {
+			return nil, err
+		}
+		fmt.Printf("%s: %d findings written to %s\n", p.Name, len(current), snapshot)
+		return nil, nil
+	}
+
+	diff, recorded, err := compareSnapshot(snapshot, current)
+	if err != nil {
+		return nil, err
+	}
+	if recorded {
+		fmt.Printf("%s: no snapshot found, %d findings recorded to %s (review and commit it)\n", p.Name, len(current), snapshot)
+		return nil, nil
+	}
+
+	fmt.Printf("%s: %d findings, %d changed\n", p.Name, len(current), len(diff))
+	for _, line := range diff {
+		fmt.Println(line)
+	}
+	return diff, nil
+}
+
+// compareSnapshot returns the differences between the snapshot and the current findings.
+// A missing snapshot is recorded from the current findings (recorded is true then).
+func compareSnapshot(snapshot string, current []string) (diff []string, recorded bool, err error) {
+	data, err := os.ReadFile(snapshot)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, true, writeSnapshot(snapshot, current)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return diffFindings(strings.Split(strings.TrimSpace(string(data)), "\n"), current), false, nil
+}
+
+// writeSnapshot writes the findings to the snapshot, one per line.
+func writeSnapshot(snapshot string, findings []string) error {
+	contents := strings.Join(findings, "\n")
+	if len(findings) > 0 {
+		contents += "\n"
+	}
+	return os.WriteFile(snapshot, []byte(contents), 0o644)
+}
+
+// checkout clones the project at its ref into the cache directory, unless it's already there.
+func checkout(p project, cache string) (string, error) {
+	dir := filepath.Join(cache, p.Name+"@"+strings.ReplaceAll(p.Ref, "/", "_"))
+	if _, err := os.Stat(dir); err == nil {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(cache, 0o755); err != nil {
+		return "", err
+	}
+	cmd := exec.Command("git", "clone", "--quiet", "--depth", "1", "--branch", p.Ref, p.Repo, dir)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to clone %s@%s: %w", p.Repo, p.Ref, err)
+	}
+	return dir, nil
+}
+
+// renderFindings formats the findings as sorted "file:line: [code] headline" lines
+// with file names relative to the project directory.
+func renderFindings(dir string, findings map[string][]mulinttest.Finding) []string {
+	lines := []string{}
+	for filename, fileFindings := range findings {
+		if rel, err := filepath.Rel(dir, filename); err == nil {
+			filename = filepath.ToSlash(rel)
+		}
+		for _, f := range fileFindings {
+			lines = append(lines, fmt.Sprintf("%s:%d: [%s] %s", filename, f.Line, f.Code, f.Headline()))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// diffFindings returns the findings gone from ("-") and added to ("+") the snapshot.
+func diffFindings(snapshot, current []string) []string {
+	counts := make(map[string]int)
+	for _, line := range snapshot {
+		if line != "" {
+			counts[line]++
+		}
+	}
+	for _, line := range current {
+		counts[line]--
+	}
+
+	var diff []string
+	for line, count := range counts {
+		for ; count > 0; count-- {
+			diff = append(diff, "- "+line)
+		}
+		for ; count < 0; count++ {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i][2:] < diff[j][2:] || (diff[i][2:] == diff[j][2:] && diff[i] < diff[j])
+	})
+	return diff
+}
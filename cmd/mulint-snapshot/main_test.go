@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/palkan/mulint/mulinttest"
+)
+
+func TestRenderFindings(t *testing.T) {
+	findings := map[string][]mulinttest.Finding{
+		"/src/project/server.go": {
+			{Line: 12, Code: "MU001", Message: "Mutex lock is acquired on this line: s.flush()\n\tserver.go:10: But the same lock was acquired here"},
+		},
+		"/src/project/internal/hub.go": {
+			{Line: 5, Code: "MU002", Message: "Mutex lock must be released before this line: return err"},
+		},
+	}
+
+	expected := []string{
+		"internal/hub.go:5: [MU002] Mutex lock must be released before this line",
+		"server.go:12: [MU001] Mutex lock is acquired on this line",
+	}
+	if lines := renderFindings("/src/project", findings); !slices.Equal(lines, expected) {
+		t.Errorf("unexpected findings:\n%v\nexpected:\n%v", lines, expected)
+	}
+}
+
+func TestDiffFindings(t *testing.T) {
+	snapshot := []string{
+		"a.go:1: [MU001] Mutex lock is acquired on this line",
+		"b.go:2: [MU002] Mutex lock must be released before this line",
+		"",
+	}
+	current := []string{
+		"a.go:1: [MU001] Mutex lock is acquired on this line",
+		"c.go:3: [MU001] Mutex lock is acquired on this line",
+	}
+
+	expected := []string{
+		"- b.go:2: [MU002] Mutex lock must be released before this line",
+		"+ c.go:3: [MU001] Mutex lock is acquired on this line",
+	}
+	if diff := diffFindings(snapshot, current); !slices.Equal(diff, expected) {
+		t.Errorf("unexpected diff:\n%v\nexpected:\n%v", diff, expected)
+	}
+
+	if diff := diffFindings(current, current); len(diff) != 0 {
+		t.Errorf("expected no diff, got %v", diff)
+	}
+}
+
+func TestCompareSnapshot(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "project.txt")
+	current := []string{
+		"a.go:1: [MU001] Mutex lock is acquired on this line",
+	}
+
+	diff, recorded, err := compareSnapshot(snapshot, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recorded || len(diff) != 0 {
+		t.Errorf("expected the missing snapshot to be recorded, got recorded=%v, diff=%v", recorded, diff)
+	}
+
+	current = append(current, "b.go:2: [MU002] Mutex lock must be released before this line")
+	diff, recorded, err = compareSnapshot(snapshot, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"+ b.go:2: [MU002] Mutex lock must be released before this line"}
+	if recorded || !slices.Equal(diff, expected) {
+		t.Errorf("unexpected diff (recorded=%v):\n%v\nexpected:\n%v", recorded, diff, expected)
+	}
+}
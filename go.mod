@@ -2,9 +2,9 @@ module github.com/palkan/mulint
 
 go 1.25.4
 
-require golang.org/x/tools v0.34.0
-
 require (
-	golang.org/x/mod v0.25.0 // indirect
-	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/mod v0.25.0
+	golang.org/x/tools v0.34.0
 )
+
+require golang.org/x/sync v0.15.0 // indirect
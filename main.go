@@ -1,10 +1,81 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/palkan/mulint/mulint"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
+var (
+	watchMode     = flag.Bool("watch", false, "re-run the analysis on file changes and print only new and fixed findings")
+	watchInterval = flag.Duration("watch-interval", 500*time.Millisecond, "how often to check for file changes in the watch mode")
+	_             = flag.Bool("workspace", false, "analyze all the modules of the current go.work workspace")
+	matrix        = flag.String("matrix", "", "semicolon-separated build configurations to analyze and compare (e.g. \"linux/amd64;windows/amd64,integration\")")
+)
+
 func main() {
+	args, err := expandWorkspace(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mulint: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+	mulint.SetOutput(hasFlag(args, "json"), os.Getenv("NO_COLOR") == "" && isTerminal(os.Stderr))
+
+	if hasFlag(os.Args[1:], "watch") || hasFlag(os.Args[1:], "matrix") {
+		mulint.Mulint.Flags.VisitAll(func(f *flag.Flag) {
+			flag.Var(f.Value, f.Name, f.Usage)
+		})
+		cpuProfile, memProfile := registerProfileFlags()
+		flag.Parse()
+
+		stopProfiling, err := startProfiling(*cpuProfile, *memProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mulint: %v\n", err)
+			os.Exit(1)
+		}
+
+		var code int
+		if *watchMode {
+			code = watch(flag.Args(), *watchInterval)
+		} else {
+			code = analyzeMatrix(flag.Args(), *matrix)
+		}
+		stopProfiling()
+		os.Exit(code)
+	}
+
 	singlechecker.Main(mulint.Mulint)
 }
+
+// hasFlag checks if the flag is passed (and not disabled) on the command line.
+// The CLI modes must be detected before singlechecker takes over the command line.
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		flagName, value, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if flagName == name && value != "false" && value != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// isTerminal checks if the file is a character device, e.g. an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
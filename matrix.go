@@ -0,0 +1,181 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/palkan/mulint/mulint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// buildConfig is a build configuration to load the packages with.
+type buildConfig struct {
+	goos   string
+	goarch string
+	tags   []string
+}
+
+func (c buildConfig) String() string {
+	var parts []string
+	if c.goos != "" || c.goarch != "" {
+		parts = append(parts, c.goos+"/"+c.goarch)
+	}
+	return strings.Join(append(parts, c.tags...), ",")
+}
+
+// parseMatrix parses semicolon-separated build configurations. Each configuration
+// is a comma-separated list of an optional GOOS/GOARCH pair and build tags:
+//
+//	linux/amd64;darwin/arm64;linux/amd64,integration
+func parseMatrix(spec string) ([]buildConfig, error) {
+	var configs []buildConfig
+	for _, entry := range strings.Split(spec, ";") {
+		var config buildConfig
+		for _, item := range splitList(entry) {
+			if goos, goarch, ok := strings.Cut(item, "/"); ok {
+				config.goos, config.goarch = goos, goarch
+				continue
+			}
+			config.tags = append(config.tags, item)
+		}
+		if config.String() != "" {
+			configs = append(configs, config)
+		}
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no build configurations in %q", spec)
+	}
+	return configs, nil
+}
+
+// analyzeMatrix analyzes the packages under each of the build configurations
+// and prints the merged findings. Findings reported only under some of
+// the configurations are marked with the list of these configurations, e.g.,
+// lock/unlock pairs split across build-tagged files.
+func analyzeMatrix(patterns []string, spec string) int {
+	configs, err := parseMatrix(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mulint: %v\n", err)
+		return 1
+	}
+
+	results := make([]map[string]string, len(configs))
+	for i, config := range configs {
+		results[i], err = analyzeConfig(patterns, config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mulint: %s: %v\n", config, err)
+			return 1
+		}
+	}
+
+	lines := mergeFindings(configs, results)
+	for _, line := range lines {
+		fmt.Fprintln(os.Stderr, line)
+	}
+	if len(lines) > 0 {
+		return 3
+	}
+	return 0
+}
+
+// analyzeConfig loads and analyzes the packages with the build configuration.
+func analyzeConfig(patterns []string, config buildConfig) (map[string]string, error) {
+	cfg := &packages.Config{Mode: loadMode, Env: os.Environ()}
+	if config.goos != "" {
+		cfg.Env = append(cfg.Env, "GOOS="+config.goos, "GOARCH="+config.goarch)
+	}
+	if len(config.tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(config.tags, ",")}
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("failed to load packages")
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{mulint.Mulint}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make(map[string]string)
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, act.Err
+		}
+		maps.Copy(findings, renderFindings(act.Package, act.Diagnostics))
+	}
+	return findings, nil
+}
+
+// mergeFindings returns the findings of all the configurations, sorted by position.
+func mergeFindings(configs []buildConfig, results []map[string]string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, findings := range results {
+		for key := range findings {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var finding string
+		var matched []string
+		for i, findings := range results {
+			if f, ok := findings[key]; ok {
+				finding = f
+				matched = append(matched, configs[i].String())
+			}
+		}
+		if len(matched) < len(configs) {
+			headline, details, _ := strings.Cut(finding, "\n")
+			finding = fmt.Sprintf("%s [only %s]", headline, strings.Join(matched, "; "))
+			if details != "" {
+				finding += "\n" + details
+			}
+		}
+		lines = append(lines, finding)
+	}
+
+	slices.SortFunc(lines, func(a, b string) int {
+		fileA, lineA, colA := findingPosition(a)
+		fileB, lineB, colB := findingPosition(b)
+		return cmp.Or(strings.Compare(fileA, fileB), cmp.Compare(lineA, lineB), cmp.Compare(colA, colB), strings.Compare(a, b))
+	})
+	return lines
+}
+
+// findingPosition parses the "file:line:col" prefix of the finding (see renderFindings).
+func findingPosition(finding string) (string, int, int) {
+	parts := strings.SplitN(finding, ":", 4)
+	if len(parts) < 4 {
+		return finding, 0, 0
+	}
+	line, _ := strconv.Atoi(parts[1])
+	col, _ := strconv.Atoi(parts[2])
+	return parts[0], line, col
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
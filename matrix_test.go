@@ -0,0 +1,97 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseMatrix(t *testing.T) {
+	configs, err := parseMatrix("linux/amd64; darwin/arm64,integration ;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, config := range configs {
+		names = append(names, config.String())
+	}
+	expected := []string{"linux/amd64", "darwin/arm64,integration"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	if _, err := parseMatrix(";"); err == nil {
+		t.Error("expected an error for an empty matrix")
+	}
+}
+
+func TestAnalyzeMatrix(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "queue.go"), `package app
+
+import "sync"
+
+type Queue struct {
+	mu sync.Mutex
+}
+
+func (q *Queue) Add() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.mu.Lock()
+}
+`)
+	writeFile(t, filepath.Join(dir, "queue_linux.go"), `package app
+
+func (q *Queue) Flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.Add()
+}
+`)
+	t.Chdir(dir)
+
+	configs, _ := parseMatrix("linux/amd64;darwin/arm64")
+	results := make([]map[string]string, len(configs))
+	for i, config := range configs {
+		var err error
+		if results[i], err = analyzeConfig([]string{"./..."}, config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := mergeFindings(configs, results)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 findings, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.HasPrefix(lines[0], "queue.go:12:2: Mutex lock is acquired on this line: q.mu.Lock()\n") {
+		t.Errorf("expected the common finding without configurations, got:\n%s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "queue_linux.go:6:2: Mutex lock is acquired on this line: q.Add() [only linux/amd64]\n") {
+		t.Errorf("expected the linux-only finding, got:\n%s", lines[1])
+	}
+}
+
+func TestMergeFindingsOrder(t *testing.T) {
+	configs, _ := parseMatrix("linux/amd64")
+	results := []map[string]string{{
+		"b": "a.go:10:2: Mutex lock is acquired on this line",
+		"a": "a.go:9:12: Mutex lock is acquired on this line",
+		"c": "a.go:9:2: Mutex lock is acquired on this line",
+		"d": "a_test.go:1:1: Mutex lock is acquired on this line",
+	}}
+
+	lines := mergeFindings(configs, results)
+	expected := []string{
+		"a.go:9:2: Mutex lock is acquired on this line",
+		"a.go:9:12: Mutex lock is acquired on this line",
+		"a.go:10:2: Mutex lock is acquired on this line",
+		"a_test.go:1:1: Mutex lock is acquired on this line",
+	}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("expected findings sorted by position %v, got %v", expected, lines)
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 )
 
 var Mulint = &analysis.Analyzer{
-	Name: "mulint",
-	Doc:  "reports reentrant mutex locks",
-	Run:  run,
+	Name:       "mulint",
+	Doc:        "reports reentrant mutex locks",
+	Run:        run,
+	ResultType: reflect.TypeOf((*LockGraph)(nil)),
+	FactTypes:  []analysis.Fact{new(MayBlock)},
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
@@ -25,45 +31,107 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 	v.AnalyzeAll()
 
-	a := NewAnalyzer(pass, v.Scopes(), v.Calls(), v.Funcs(), v.Wrappers(), v.Conditionals(), pass.TypesInfo)
+	if isDependency(pass) {
+		// Dependencies are only analyzed for their facts
+		a := NewAnalyzer(pass, v.Scopes(), v.Calls(), v.Funcs(), v.Wrappers(), v.Conditionals(), v.Pools(), v.Conds(), v.Timers(), pass.TypesInfo, config)
+		a.AddExternSummaries(config.externSummaries)
+		a.exportBlockingFacts()
+		return (*LockGraph)(nil), nil
+	}
+
+	dynamicCalls, err := ResolveDynamicCalls(pass, config.CallGraph)
+	if err != nil {
+		return nil, err
+	}
+
+	runFunc := config.runFunc
+	if runFunc != nil {
+		pass = restrictReports(pass, runFunc)
+	}
+
+	a := NewAnalyzer(pass, v.Scopes(), v.Calls(), v.Funcs(), v.Wrappers(), v.Conditionals(), v.Pools(), v.Conds(), v.Timers(), pass.TypesInfo, config)
+	a.AddExternSummaries(config.externSummaries)
+	a.AddDynamicCalls(dynamicCalls)
+	a.RestrictTo(runFunc)
 	a.Analyze()
+	a.exportBlockingFacts()
 
-	for _, e := range a.Errors() {
-		e.Report(pass)
+	if config.Group {
+		for _, g := range GroupLintErrors(a.Errors()) {
+			g.Report(pass)
+		}
+	} else {
+		for _, e := range a.Errors() {
+			e.Report(pass)
+		}
 	}
 
-	for _, e := range a.MissingUnlockErrors() {
-		e.Report(pass)
+	for _, d := range a.diagnostics {
+		d.Report(pass)
 	}
 
-	return nil, nil
+	return a.LockGraph(), nil
+}
+
+// isDependency checks if the package is only analyzed as a dependency of the packages being checked
+// (the analysis framework runs the analyzers with facts on all of them): a package of the standard library
+// or of a required module, the one with a version. The lock graph isn't built for them (it's nil).
+func isDependency(pass *analysis.Pass) bool {
+	return isStdLibrary(pass) || (pass.Module != nil && pass.Module.Version != "")
+}
+
+// reporter is a finding (or a report) of the analyzer.
+type reporter interface {
+	Report(pass *analysis.Pass)
 }
 
 // Analyzer checks for mutex-related issues in collected scopes.
 type Analyzer struct {
-	errors         []LintError
-	missingUnlocks []MissingUnlockError
-	pass           *analysis.Pass
-	scopes         map[FQN]*LockTracker
-	calls          map[FQN][]FQN
-	reported       map[token.Pos]bool // tracks secondLock positions to avoid duplicates
-	funcs          []*ast.FuncDecl
-	wrappers       *WrapperRegistry
-	conditionals   *ConditionalLockRegistry
-	info           *types.Info
+	errors           []LintError // reentrant locks, grouped with -group
+	diagnostics      []reporter  // the findings of the other checks and the reports, in the order of the checks
+	pass             *analysis.Pass
+	scopes           map[FQN]*LockTracker
+	calls            map[FQN][]FQN
+	reported         map[token.Pos]bool    // tracks reported return positions to avoid duplicates
+	reportedLocks    map[diagnosticKey]int // reported reentrant locks -> index in errors
+	funcs            []*ast.FuncDecl
+	wrappers         *WrapperRegistry
+	conditionals     *ConditionalLockRegistry
+	pools            *PoolRegistry
+	conds            *CondRegistry
+	timers           *TimerRegistry
+	info             *types.Info
+	config           Config
+	live             map[FQN]bool             // functions reachable from entry points; nil means all
+	runFunc          *regexp.Regexp           // functions to analyze (see -run-func); nil means all
+	guards           *GuardIndex              // built lazily by guardIndex()
+	externSummaries  ExternSummaries          // built lazily by externs()
+	dynamicCalls     map[token.Pos][]FQN      // possible callees of dynamic calls (see -callgraph)
+	iterators        *IteratorIndex           // built lazily by iteratorIndex()
+	lockedVariants   map[FQN]string           // built lazily by lockedVariant()
+	funcSummaries    map[FQN]*FunctionSummary // built lazily by summaryOf()
+	decls            map[FQN]*ast.FuncDecl    // built along with funcSummaries
+	blockingFacts    map[FQN]*MayBlock        // built lazily by importedMayBlock()
+	declaredBlocking map[FQN]bool             // built along with blockingFacts
+	std              bool                     // the package belongs to the standard library (see isStdLibrary)
 }
 
-func NewAnalyzer(pass *analysis.Pass, scopes map[FQN]*LockTracker, calls map[FQN][]FQN, funcs []*ast.FuncDecl, wrappers *WrapperRegistry, conditionals *ConditionalLockRegistry, info *types.Info) *Analyzer {
+func NewAnalyzer(pass *analysis.Pass, scopes map[FQN]*LockTracker, calls map[FQN][]FQN, funcs []*ast.FuncDecl, wrappers *WrapperRegistry, conditionals *ConditionalLockRegistry, pools *PoolRegistry, conds *CondRegistry, timers *TimerRegistry, info *types.Info, config Config) *Analyzer {
 	return &Analyzer{
-		pass:           pass,
-		scopes:         scopes,
-		calls:          calls,
-		reported:       make(map[token.Pos]bool),
-		funcs:          funcs,
-		wrappers:       wrappers,
-		conditionals:   conditionals,
-		info:           info,
-		missingUnlocks: make([]MissingUnlockError, 0),
+		pass:          pass,
+		scopes:        scopes,
+		calls:         calls,
+		reported:      make(map[token.Pos]bool),
+		reportedLocks: make(map[diagnosticKey]int),
+		funcs:         funcs,
+		wrappers:      wrappers,
+		conditionals:  conditionals,
+		pools:         pools,
+		conds:         conds,
+		timers:        timers,
+		info:          info,
+		config:        config,
+		std:           isStdLibrary(pass),
 	}
 }
 
@@ -71,33 +139,135 @@ func (a *Analyzer) Errors() []LintError {
 	return a.errors
 }
 
-func (a *Analyzer) MissingUnlockErrors() []MissingUnlockError {
-	return a.missingUnlocks
-}
-
 // Analyze runs all checks on collected scopes.
 func (a *Analyzer) Analyze() {
+	a.seedEntryPoints()
 	a.checkReentrantLocks()
+	a.checkLockedCycles()
 	a.checkMissingUnlocks()
+	a.checkUnlockPaths()
+	a.checkConditionalUnlocks()
+	a.checkSuspectWrappers()
+	a.checkCondWaits()
+	a.checkDoubleCheckedLocking()
+	a.checkTimerCallbackWaits()
+	a.checkBlockingCalls()
+	a.checkSelectDeadlocks()
+	a.checkMutexAssignments()
+	a.checkHeldMutexCopies()
+	a.checkLoopVarLocks()
+	a.checkCopiedElementLocks()
+	if a.config.HTTPHandlers {
+		a.checkSharedHandlerLocks()
+	}
+	if a.config.ExportedCalls {
+		a.checkExportedCalls()
+	}
+	if a.config.LockedConvention {
+		a.checkLockedConvention()
+	}
+	if a.config.WriterStarvation {
+		a.checkWriterStarvation()
+	}
+	if a.config.UseAfterUnlock {
+		a.checkUseAfterUnlock()
+	}
+	if a.config.GuardedReturns {
+		a.checkGuardedReturns()
+	}
+	if a.config.EscapingClosures {
+		a.checkEscapingClosures()
+	}
+	if a.config.RedundantMutexes {
+		a.checkRedundantMutexes()
+	}
+	if a.config.LocalMutexes {
+		a.checkLocalMutexes()
+	}
+	if a.config.HotMethodLocks {
+		a.checkHotMethodLocks()
+	}
+	if a.config.RequireDeferUnlock || a.isStrict() {
+		a.checkDeferredUnlocks()
+	}
+	if a.isStrict() {
+		a.checkUnverifiableCalls()
+		a.checkBlockingOps()
+		a.checkRequiredLocks()
+	}
+	if a.config.Summary {
+		a.summarizeExported()
+	}
+	if a.config.CriticalSections {
+		a.inventoryCriticalSections()
+	}
+	if a.config.LockMetrics {
+		a.reportLockMetrics()
+	}
 	// Future: a.checkDoubleUnlocks()
 	// Future: a.checkUnlockWithoutLock()
 }
 
+// isFreshLock checks if the lock acquired by the function at the position is made on a new value
+// that hasn't escaped yet (see markFreshScopes).
+func (a *Analyzer) isFreshLock(fqn FQN, pos token.Pos) bool {
+	return slices.ContainsFunc(a.summaryOf(fqn).Scopes, func(scope *MutexScope) bool {
+		return scope.Pos() == pos && scope.IsFresh()
+	})
+}
+
 // checkMissingUnlocks detects return statements that occur while a lock is held.
+// It also detects returns made after releasing a lock with a deferred unlock
+// (and before re-acquiring it), where the deferred unlock would run on an unlocked mutex,
+// and deferred unlocks registered more times than the mutex is acquired on the path.
 func (a *Analyzer) checkMissingUnlocks() {
+	earlyReported := make(map[token.Pos]bool)
+	doubleReported := make(map[token.Pos]bool)
+
 	for _, fn := range a.funcs {
-		if fn.Body == nil {
+		fqn := a.declFQN(fn)
+		if fn.Body == nil || !a.isLive(fqn) {
 			continue
 		}
 
 		tracker := NewBranchTrackerWithWrappers(a.wrappers, a.info)
+		tracker.CollectLabels(fn.Body)
+		tracker.CollectFlags(fn.Body)
 		tracker.AnalyzeStatements(fn.Body.List)
+		tracker.CheckEnd(fn.Body.Rbrace)
+
+		for _, early := range tracker.EarlyUnlockReturns() {
+			if earlyReported[early.returnPos] {
+				continue
+			}
+			earlyReported[early.returnPos] = true
+			a.diagnostics = append(a.diagnostics, NewEarlyUnlockError(
+				NewLocation(early.unlockInfo.pos),
+				NewLocation(early.returnPos),
+			))
+		}
+
+		for _, double := range tracker.DoubleDeferredUnlocks() {
+			if doubleReported[double.deferPos] {
+				continue
+			}
+			doubleReported[double.deferPos] = true
+			a.diagnostics = append(a.diagnostics, NewDoubleDeferUnlockError(
+				NewLocation(double.deferPos),
+				NewLocation(double.firstPos),
+				double.selector,
+			))
+		}
 
 		for _, err := range tracker.Errors() {
 			// Deduplicate by return position
 			if a.reported[err.returnPos] {
 				continue
 			}
+			// New values can be returned locked for the caller
+			if a.isFreshLock(fqn, err.lockInfo.pos) && handsOffFresh(fn.Body, err.lockInfo.pos, err.returnPos, a.info) {
+				continue
+			}
 			a.reported[err.returnPos] = true
 
 			var unlockErr MissingUnlockError
@@ -113,37 +283,67 @@ func (a *Analyzer) checkMissingUnlocks() {
 					NewLocation(err.returnPos),
 				)
 			}
-			a.missingUnlocks = append(a.missingUnlocks, unlockErr)
+			a.diagnostics = append(a.diagnostics, unlockErr)
 		}
 	}
 }
 
-// checkReentrantLocks detects attempts to acquire a lock that's already held.
+// checkReentrantLocks detects attempts to acquire a lock that's already held, including
+// within the callbacks of lock helpers (see checkLockHelperCalls).
 func (a *Analyzer) checkReentrantLocks() {
 	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
 		for _, scope := range tracker.Scopes() {
 			for _, node := range scope.Nodes() {
 				a.checkNodeForReentrantLock(node, scope, fqn)
 			}
 		}
 	}
+	a.checkLockHelperCalls()
 }
 
 func (a *Analyzer) checkNodeForReentrantLock(n ast.Node, scope *MutexScope, currentFQN FQN) {
+	inspectScopeCalls(n, a.info, func(call *ast.CallExpr) {
+		a.checkDirectReentrantLock(scope, call, currentFQN)
+		a.checkTransitiveReentrantLock(scope, call, currentFQN)
+		a.checkIteratorLock(scope, call, currentFQN)
+		a.checkLockerArgs(scope, call, currentFQN)
+		a.checkPoolGet(scope, call, currentFQN)
+		a.checkSortCall(scope, call, currentFQN)
+		a.checkSyncCallbacks(scope, call, currentFQN)
+		a.checkMaybeSyncCallbacks(scope, call, currentFQN)
+	})
+}
+
+// inspectScopeCalls calls fn for every call expression within a scope node
+// that executes synchronously while the lock is held.
+func inspectScopeCalls(n ast.Node, info *types.Info, fn func(call *ast.CallExpr)) {
 	// Collect func literals that should be skipped from analysis:
-	// 1. Func literals passed as arguments to calls - may run asynchronously
+	// 1. Func literals passed as arguments to calls - may run asynchronously,
+	//    unless the callee is known to call them synchronously (e.g., singleflight.Group.Do),
+	//    or any callee not known to be asynchronous is assumed to (see -assume-sync-callbacks)
 	// 2. Func literals that are returned - will be executed by caller after lock is released
 	// 3. Func literals assigned to variables - likely returned or called later
+	// 4. Func literals passed to functions called in goroutines
 	// Note: func literals that are called directly (e.g., defer func(){}()) are NOT skipped.
 	skipFuncLits := make(map[*ast.FuncLit]bool)
 	ast.Inspect(n, func(node ast.Node) bool {
-		if call, ok := node.(*ast.CallExpr); ok {
+		if call, ok := node.(*ast.CallExpr); ok && !callsFuncLitsInPlace(call, info) {
 			for _, arg := range call.Args {
 				if funcLit, ok := arg.(*ast.FuncLit); ok {
 					skipFuncLits[funcLit] = true
 				}
 			}
 		}
+		if stmt, ok := node.(*ast.GoStmt); ok {
+			for _, arg := range stmt.Call.Args {
+				if funcLit, ok := arg.(*ast.FuncLit); ok {
+					skipFuncLits[funcLit] = true
+				}
+			}
+		}
 		if ret, ok := node.(*ast.ReturnStmt); ok {
 			for _, result := range ret.Results {
 				if funcLit, ok := result.(*ast.FuncLit); ok {
@@ -161,10 +361,25 @@ func (a *Analyzer) checkNodeForReentrantLock(n ast.Node, scope *MutexScope, curr
 		return true
 	})
 
+	// Range-over-func loops call the iterator (the ranged expression is a scope node itself)
+	if expr, ok := n.(ast.Expr); ok {
+		if call := iteratorCall(expr, info); call != nil {
+			fn(call)
+		}
+	}
+
 	// Walk the AST to find all CallExpr nodes within this statement
-	ast.Inspect(n, func(node ast.Node) bool {
-		// Skip goroutines - they run asynchronously, lock may be released
-		if _, ok := node.(*ast.GoStmt); ok {
+	var visit func(node ast.Node) bool
+	visit = func(node ast.Node) bool {
+		// Skip goroutines - they run asynchronously, lock may be released.
+		// The receiver and the arguments are evaluated in place, though.
+		if stmt, ok := node.(*ast.GoStmt); ok {
+			if sel, ok := stmt.Call.Fun.(*ast.SelectorExpr); ok {
+				ast.Inspect(sel.X, visit)
+			}
+			for _, arg := range stmt.Call.Args {
+				ast.Inspect(arg, visit)
+			}
 			return false
 		}
 		// Skip func literals that are passed as arguments or returned
@@ -174,15 +389,20 @@ func (a *Analyzer) checkNodeForReentrantLock(n ast.Node, scope *MutexScope, curr
 			}
 		}
 		if call, ok := node.(*ast.CallExpr); ok {
-			a.checkDirectReentrantLock(scope, call)
-			a.checkTransitiveReentrantLock(scope, call)
+			fn(call)
+		}
+		if loop, ok := node.(*ast.RangeStmt); ok {
+			if call := iteratorCall(loop.X, info); call != nil {
+				fn(call)
+			}
 		}
 		return true
-	})
+	}
+	ast.Inspect(n, visit)
 }
 
 // checkDirectReentrantLock checks if a call is a direct lock on the same mutex.
-func (a *Analyzer) checkDirectReentrantLock(scope *MutexScope, call *ast.CallExpr) {
+func (a *Analyzer) checkDirectReentrantLock(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
 	subject := SubjectForCall(call, lockMethods)
 	if subject == nil {
 		return
@@ -193,38 +413,52 @@ func (a *Analyzer) checkDirectReentrantLock(scope *MutexScope, call *ast.CallExp
 		return
 	}
 
-	selector := StrExpr(subject)
+	selector := LockSelector(subject, a.info)
 	if selector == scope.Selector() {
-		a.recordError(scope.Pos(), call.Pos(), scope.Wrapper())
+		a.recordLockError(currentFQN, scope, call, nil, isWriteLockCall(call))
 	}
 }
 
 // checkTransitiveReentrantLock checks if a call leads to a lock on the same mutex.
-func (a *Analyzer) checkTransitiveReentrantLock(scope *MutexScope, call *ast.CallExpr) {
-	pkg, name, ok := GetCallInfo(call, a.pass.TypesInfo)
-	if !ok {
-		return
-	}
-
+func (a *Analyzer) checkTransitiveReentrantLock(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
 	// Skip if call is on a different receiver instance
 	if a.isCallOnDifferentReceiver(call, scope) {
 		return
 	}
 
-	fqn := FromCallInfo(pkg, name)
+	var site *LockSite
+	var callee FQN
+	if pkg, name, ok := GetCallInfo(call, a.pass.TypesInfo); ok {
+		fqn := FromCallInfo(pkg, name)
+		callee = fqn
 
-	// Check if this is a conditional lock that won't be taken based on arguments
-	if a.conditionals.ShouldSkipLock(fqn, call, scope.Selector()) {
-		return
+		// Check if this is a conditional lock that won't be taken based on arguments
+		// (and the values of the bool variables passed implied by the path conditions)
+		if len(a.summaryOf(fqn).Conditional) > 0 && a.conditionals.ShouldSkipLock(fqn, call, scope.Selector(), a.pathConditions(call)) {
+			return
+		}
+
+		site = a.findTransitiveLock(fqn, a.calleeScope(call, scope), make(map[FQN]*LockSite))
+	}
+
+	// Check interface method calls and function values resolved via the call graph
+	for _, callee := range a.dynamicCalls[call.Lparen] {
+		if site != nil {
+			break
+		}
+		site = a.findTransitiveLock(callee, scope, make(map[FQN]*LockSite))
 	}
 
-	if a.hasTransitiveLock(fqn, scope, make(map[FQN]bool)) {
-		a.recordError(scope.Pos(), call.Pos(), scope.Wrapper())
+	if site != nil {
+		a.recordErrorVia(currentFQN, scope, call, site)
+		a.suggestLockedVariant(call, callee)
+		a.suggestSlowPath(call, callee, site)
 	}
 }
 
 // isCallOnDifferentReceiver checks if a method call is on a different receiver
-// than the one used in the mutex scope.
+// than the one used in the mutex scope. The full receiver path is compared,
+// so b.nested.helper() is on the receiver of b.nested.m but not of b.mu.
 func (a *Analyzer) isCallOnDifferentReceiver(call *ast.CallExpr, scope *MutexScope) bool {
 	selector := SelectorExpr(call)
 	if selector == nil {
@@ -237,66 +471,176 @@ func (a *Analyzer) isCallOnDifferentReceiver(call *ast.CallExpr, scope *MutexSco
 	}
 
 	scopeRoot, _ := SplitSelector(scope.Selector())
-	if scopeRoot == "" {
+	if scopeRoot == "" || scope.IsGlobal() {
 		return false
 	}
 
-	return callReceiver.Name != scopeRoot
+	if LockSelector(callReceiver, a.info) != scopeRoot {
+		return true
+	}
+	// Interface values may hold the root receiver itself (see -callgraph)
+	if t := a.info.TypeOf(selector.X); t == nil || types.IsInterface(t) {
+		return false
+	}
+
+	return !strings.HasPrefix(scope.Selector(), LockSelector(selector.X, a.info)+".")
 }
 
-// hasTransitiveLock checks if a function (or its callees) locks the same mutex.
-func (a *Analyzer) hasTransitiveLock(fqn FQN, scope *MutexScope, checked map[FQN]bool) bool {
-	if result, ok := checked[fqn]; ok {
-		return result
+// calleeScope returns the scope with the mutex selector expressed via the receiver name
+// of the called method (b.nested.m -> n.m for b.nested.helper() declared on n),
+// so that it can be matched against the callee's scopes. Promoted methods are called
+// on the embedded value (s.refresh() -> s.core.refresh()), and promoted fields of the selector
+// are expanded the same way (s.mu -> s.core.mu), so both resolve to c.mu for refresh declared on c.
+func (a *Analyzer) calleeScope(call *ast.CallExpr, scope *MutexScope) *MutexScope {
+	sel := SelectorExpr(call)
+	if sel == nil {
+		return scope
+	}
+	selection, ok := a.info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return scope
 	}
+	method, ok := selection.Obj().(*types.Func)
+	if !ok {
+		return scope
+	}
+	if calleeScope := a.receiverScope(sel.X, method, selection.Index(), scope); calleeScope != nil {
+		return calleeScope
+	}
+	return scope
+}
 
-	// Check if this function directly locks the same mutex
-	if tracker, ok := a.scopes[fqn]; ok {
-		for _, s := range tracker.Scopes() {
-			if s.HasSameSelector(scope) {
-				checked[fqn] = true
-				return true
-			}
+// expandFields returns the field path with the embedded fields promoted fields are accessed
+// through spelled out (mu -> core.mu for a struct embedding core).
+func (a *Analyzer) expandFields(t types.Type, fields []string) ([]string, bool) {
+	var path []string
+	for _, name := range fields {
+		obj, index, _ := types.LookupFieldOrMethod(t, true, a.pass.Pkg, name)
+		field, ok := obj.(*types.Var)
+		if !ok || !field.IsField() {
+			return nil, false
 		}
+		embedded := embeddedFields(t, index[:len(index)-1])
+		path = append(append(path, embedded...), name)
+		t = field.Type()
 	}
+	return path, true
+}
 
-	// Check callees recursively
-	calls, ok := a.calls[fqn]
-	if !ok {
-		checked[fqn] = false
-		return false
+// embeddedFields returns the names of the fields at the index path of the struct type.
+func embeddedFields(t types.Type, index []int) []string {
+	var names []string
+	for _, i := range index {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok || i >= st.NumFields() {
+			return nil
+		}
+		names = append(names, st.Field(i).Name())
+		t = st.Field(i).Type()
 	}
+	return names
+}
 
-	for _, callee := range calls {
-		if a.hasTransitiveLock(callee, scope, checked) {
-			checked[fqn] = true
-			return true
+// LockSite is a lock acquisition performed by a function reached through the call graph.
+type LockSite struct {
+	FQN   FQN       // the function acquiring the lock
+	Pos   token.Pos // position of the lock inside the function
+	Write bool      // true if the lock is acquired for writing (Lock)
+}
+
+// findTransitiveLock returns the site where a function (or one of its callees)
+// locks the same mutex, or nil if there is none.
+// Visited functions are recorded in checked (with a nil site while being resolved),
+// so recursive call chains terminate.
+func (a *Analyzer) findTransitiveLock(fqn FQN, scope *MutexScope, checked map[FQN]*LockSite) *LockSite {
+	if site, ok := checked[fqn]; ok {
+		return site
+	}
+	checked[fqn] = nil
+
+	// Check if this function directly locks the same mutex
+	if s := a.summaryOf(fqn).lockScope(scope.Selector()); s != nil {
+		site := &LockSite{FQN: fqn, Pos: s.Pos(), Write: !s.IsRead()}
+		checked[fqn] = site
+		return site
+	}
+
+	// Check iterators the function ranges over
+	for _, iterFQN := range a.iteratorIndex().Ranges(fqn) {
+		if site := a.iteratorIndex().LockSite(iterFQN, scope); site != nil {
+			checked[fqn] = site
+			return site
 		}
 	}
 
-	checked[fqn] = false
-	return false
+	// Check callees recursively
+	for _, callee := range a.calls[fqn] {
+		if site := a.findTransitiveLock(callee, scope, checked); site != nil {
+			checked[fqn] = site
+			return site
+		}
+	}
+
+	return nil
+}
+
+func (a *Analyzer) recordError(fqn FQN, scope *MutexScope, call *ast.CallExpr) {
+	a.recordErrorVia(fqn, scope, call, nil)
+}
+
+// recordErrorVia records a reentrant lock error; site is the lock inside the callee
+// for transitive locks.
+func (a *Analyzer) recordErrorVia(fqn FQN, scope *MutexScope, call *ast.CallExpr, site *LockSite) {
+	a.recordLockError(fqn, scope, call, site, site != nil && site.Write)
 }
 
-func (a *Analyzer) recordError(origin, secondLock token.Pos, wrapper *WrapperInfo) {
-	// Deduplicate errors by secondLock position
-	if a.reported[secondLock] {
+// recordLockError records a reentrant lock error for the call acquiring the lock again;
+// write is true if the second lock is acquired for writing, which upgrades the read lock
+// held by the scope (if any).
+// The same call may be reached through different scopes of the mutex (e.g., direct and wrapper-derived
+// ones, or a lock and a re-lock after an unlock), in which case a single error is reported
+// with the closest origin (see closestOrigin and isCloserOrigin).
+func (a *Analyzer) recordLockError(fqn FQN, scope *MutexScope, call *ast.CallExpr, site *LockSite, write bool) {
+	key := newDiagnosticKey(CodeReentrantLock, call, a.info, scope.Selector())
+	origin := a.closestOrigin(fqn, scope, call.Pos())
+	idx, reported := a.reportedLocks[key]
+	if reported && !a.isCloserOrigin(fqn, origin, a.errors[idx].origin.pos, call.Pos()) {
+		return
+	}
+
+	err := newReentrantLockError(fqn, scope, origin, call.Pos(), site, write)
+	if reported {
+		a.errors[idx] = err
 		return
 	}
-	a.reported[secondLock] = true
+	a.reportedLocks[key] = len(a.errors)
+	a.errors = append(a.errors, err)
+}
 
+// newReentrantLockError builds the reentrant lock error of the second lock acquired under the scope
+// locked at the origin (the scope's lock or a later re-lock of the mutex).
+func newReentrantLockError(fqn FQN, scope *MutexScope, origin, secondLock token.Pos, site *LockSite, write bool) LintError {
 	var err LintError
-	if wrapper != nil {
-		err = NewLintErrorWithWrapper(NewLocation(origin), NewLocation(secondLock), wrapper)
+	if scope.Wrapper() != nil && origin == scope.Pos() {
+		err = NewLintErrorWithWrapper(NewLocation(origin), NewLocation(secondLock), scope.Wrapper())
 	} else {
 		err = NewLintError(NewLocation(origin), NewLocation(secondLock))
 	}
-	a.errors = append(a.errors, err)
+	err.fqn = fqn
+	err.selector = scope.Selector()
+	err.via = site
+	err.upgrade = scope.IsRead() && write
+	return err
 }
 
 // GetCallInfo extracts the package path and function name from a call expression.
+// Functions are identified by the path of the package declaring them, so aliased
+// and dot-imported packages resolve to the same names as the regular imports.
 func GetCallInfo(call *ast.CallExpr, info *types.Info) (string, string, bool) {
-	switch fun := call.Fun.(type) {
+	switch fun := calledFunc(call, info).(type) {
 	case *ast.SelectorExpr:
 		// Method call: x.Method() or pkg.Function()
 		if sel, ok := info.Selections[fun]; ok {
@@ -305,11 +649,18 @@ func GetCallInfo(call *ast.CallExpr, info *types.Info) (string, string, bool) {
 			if recv == nil || obj == nil {
 				return "", "", false
 			}
+			// Methods are named after the type declaring them rather than the receiver's type,
+			// which differ for promoted methods of embedded types (possibly of other packages)
+			if method, ok := obj.(*types.Func); ok {
+				if sig, ok := method.Type().(*types.Signature); ok && sig.Recv() != nil {
+					recv = sig.Recv().Type()
+				}
+			}
 			pkgPath := ""
 			if pkg := obj.Pkg(); pkg != nil {
 				pkgPath = pkg.Path()
 			}
-			recvTypeName := getTypeName(recv)
+			pkgPath, recvTypeName := qualifiedTypeName(recv, pkgPath)
 			return pkgPath, recvTypeName + ":" + fun.Sel.Name, true
 		}
 		// Package-qualified function call
@@ -333,14 +684,30 @@ func GetCallInfo(call *ast.CallExpr, info *types.Info) (string, string, bool) {
 	return "", "", false
 }
 
+// calledFunc returns the expression of the called function without parentheses
+// and explicit instantiations of generic functions ((pkg.F)() or pkg.F[int]() -> pkg.F).
+func calledFunc(call *ast.CallExpr, info *types.Info) ast.Expr {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		// Unlike calls of function values stored in slices and maps (handlers[i]())
+		if info.Types[f.Index].IsType() {
+			return ast.Unparen(f.X)
+		}
+	case *ast.IndexListExpr:
+		return ast.Unparen(f.X)
+	}
+	return fun
+}
+
 // getTypeName extracts just the type name from a types.Type.
 func getTypeName(t types.Type) string {
-	switch ty := t.(type) {
+	switch ty := types.Unalias(t).(type) {
 	case *types.Pointer:
 		return getTypeName(ty.Elem())
 	case *types.Named:
 		return ty.Obj().Name()
 	default:
-		return t.String()
+		return ty.String()
 	}
 }
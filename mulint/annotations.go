@@ -0,0 +1,38 @@
+package mulint
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// directivePrefix is the prefix of mulint annotations in function doc comments,
+// e.g. "//mulint:entrypoint".
+const directivePrefix = "//mulint:"
+
+// FuncDirectives returns the mulint directives declared in a function's doc comment,
+// mapped to their (possibly empty) arguments.
+func FuncDirectives(fn *ast.FuncDecl) map[string][]string {
+	directives := make(map[string][]string)
+	if fn.Doc == nil {
+		return directives
+	}
+
+	for _, comment := range fn.Doc.List {
+		text, ok := strings.CutPrefix(comment.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		directives[fields[0]] = append(directives[fields[0]], fields[1:]...)
+	}
+	return directives
+}
+
+// HasDirective checks if a function is annotated with the given mulint directive.
+func HasDirective(fn *ast.FuncDecl, name string) bool {
+	_, ok := FuncDirectives(fn)[name]
+	return ok
+}
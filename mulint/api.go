@@ -0,0 +1,59 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// checkExportedCalls detects exported methods that call other exported methods
+// of the same type while holding a mutex that the callee also acquires.
+// Unlike the reentrancy check, conditional locks in the callee are not taken into account:
+// public API methods calling each other under lock is a maintenance trap on its own.
+func (a *Analyzer) checkExportedCalls() {
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		typeName, method, ok := splitMethod(fqn)
+		if !ok || !ast.IsExported(method) || !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			key := scope.Key(fqn)
+
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if reported[call.Pos()] || SelectorExpr(call) == nil || a.isCallOnDifferentReceiver(call, scope) {
+						return
+					}
+					pkg, name, ok := GetCallInfo(call, a.info)
+					if !ok {
+						return
+					}
+					callee := FromCallInfo(pkg, name)
+					calleeType, calleeMethod, ok := splitMethod(callee)
+					if !ok || callee == fqn || calleeType != typeName || !ast.IsExported(calleeMethod) {
+						return
+					}
+					if !slices.Contains(a.LocksReachable(callee), key) {
+						return
+					}
+
+					reported[call.Pos()] = true
+					a.diagnostics = append(a.diagnostics, NewExportedCallError(
+						NewLocation(scope.Pos()),
+						NewLocation(call.Pos()),
+						callee,
+					))
+				})
+			}
+		}
+	}
+}
+
+// splitMethod splits a method FQN into its receiver type and method names.
+func splitMethod(fqn FQN) (string, string, bool) {
+	return strings.Cut(fqn.ShortName(), ":")
+}
@@ -0,0 +1,70 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// checkMutexAssignments detects assignments to struct fields of mutex types (e.g., s.mu = sync.Mutex{}).
+// Resetting a mutex that is (or may be) held elsewhere corrupts the lock.
+// Assignments are allowed in constructors (functions returning the struct) and on new values
+// that haven't escaped yet (see markFreshScopes).
+func (a *Analyzer) checkMutexAssignments() {
+	for _, fn := range a.funcs {
+		if !a.isLive(a.declFQN(fn)) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || assign.Tok != token.ASSIGN {
+				return true
+			}
+			for _, lhs := range assign.Lhs {
+				sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr)
+				if !ok {
+					continue
+				}
+				selection, ok := a.info.Selections[sel]
+				if !ok || selection.Kind() != types.FieldVal || !isMutexTypeName(selection.Type()) {
+					continue
+				}
+				if a.isConstructorOf(fn, selection.Recv()) || a.isFreshAt(fn.Body, sel, assign.Pos()) {
+					continue
+				}
+				a.diagnostics = append(a.diagnostics, NewMutexAssignError(NewLocation(assign.Pos()), StrExpr(sel)))
+			}
+			return true
+		})
+	}
+}
+
+// isConstructorOf checks if the function is a constructor of the struct type,
+// i.e. a function (not a method) returning the type or a pointer to it.
+func (a *Analyzer) isConstructorOf(fn *ast.FuncDecl, recv types.Type) bool {
+	if fn.Recv != nil || fn.Type.Results == nil {
+		return false
+	}
+	named, ok := derefType(recv).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	for _, result := range fn.Type.Results.List {
+		if t, ok := derefType(a.info.TypeOf(result.Type)).(*types.Named); ok && t.Obj() == named.Obj() {
+			return true
+		}
+	}
+	return false
+}
+
+// isFreshAt checks if the selector is rooted at a new value that hasn't escaped the function before the position.
+func (a *Analyzer) isFreshAt(body *ast.BlockStmt, sel *ast.SelectorExpr, pos token.Pos) bool {
+	root := RootSelector(sel)
+	if root == nil {
+		return false
+	}
+	v := freshVar(body, root, pos, a.info)
+	return v != nil && !escapesBefore(body, v, pos, a.info)
+}
@@ -0,0 +1,71 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// checkBlockingCalls detects calls made while holding a lock to functions declared
+// as blocking (see EffectBlocks), either directly or through the package functions
+// and the functions of the imported packages that may block (see MayBlock).
+// In the strict profile, well-known blocking functions of the standard library
+// and the functions performing channel operations that may block are reported too.
+func (a *Analyzer) checkBlockingCalls() {
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if reported[call.Pos()] {
+						return
+					}
+					pkg, name, ok := GetCallInfo(call, a.info)
+					if !ok {
+						return
+					}
+					callee := FromCallInfo(pkg, name)
+					blocking, ok := a.findBlockingCall(callee)
+					if !ok {
+						return
+					}
+					reported[call.Pos()] = true
+					a.diagnostics = append(a.diagnostics, NewBlockingCallError(
+						NewLocation(scope.Pos()),
+						NewLocation(call.Pos()),
+						callee,
+						blocking,
+					))
+				})
+			}
+		}
+	}
+}
+
+// findBlockingCall returns the blocking function reachable from fqn (possibly fqn itself) to report:
+// any of them in the strict profile, only the ones declared as blocking otherwise.
+func (a *Analyzer) findBlockingCall(fqn FQN) (FQN, bool) {
+	for _, blocking := range a.blockingCalls(fqn) {
+		if a.isStrict() || a.isDeclaredBlocking(blocking) {
+			return blocking, true
+		}
+	}
+	return "", false
+}
+
+// blockingCalls returns the blocking functions reachable from fqn (possibly fqn itself),
+// regardless of the profile: the ones of isBlockingFunc, the ones performing channel operations
+// that may block and the ones reached through the functions of the imported packages (see MayBlock).
+func (a *Analyzer) blockingCalls(fqn FQN) []FQN {
+	if a.isBlockingFunc(fqn) {
+		return []FQN{fqn}
+	}
+	if fact := a.importedMayBlock(fqn); fact != nil {
+		return []FQN{fact.Via}
+	}
+	return a.reachableSummary(fqn).blocking
+}
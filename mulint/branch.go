@@ -4,6 +4,7 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"slices"
 )
 
 // BranchLockInfo tracks a lock's state at a point in code.
@@ -11,6 +12,7 @@ type BranchLockInfo struct {
 	selector string
 	pos      token.Pos
 	wrapper  *WrapperInfo
+	read     bool // the read lock is held (RLock)
 }
 
 // MissingUnlock records a return statement that occurs while a lock is held.
@@ -19,12 +21,49 @@ type MissingUnlock struct {
 	returnPos token.Pos
 }
 
+// EarlyUnlockReturn records a return statement that occurs after a lock with a deferred
+// unlock was released manually and not re-acquired: the deferred unlock will run
+// on an unlocked mutex.
+type EarlyUnlockReturn struct {
+	unlockInfo BranchLockInfo
+	returnPos  token.Pos
+}
+
+// DoubleDeferredUnlock records a deferred unlock registered while the deferred unlocks
+// of the mutex on the path already match its acquisitions: one of them runs on an unlocked mutex.
+type DoubleDeferredUnlock struct {
+	selector string
+	firstPos token.Pos // the previous deferred unlock
+	deferPos token.Pos
+}
+
+// flagMirror relates the value of a bool flag to the lock state: the lock is released
+// when the flag has the value, and held otherwise.
+type flagMirror struct {
+	lock     BranchLockInfo
+	released bool
+}
+
 // BranchTracker tracks lock state through branching control flow.
 // It detects return statements that occur while locks are held.
 type BranchTracker struct {
-	ongoing map[string]BranchLockInfo
-	defers  map[string]bool
-	errors  *[]MissingUnlock // Pointer to shared slice for collecting errors
+	ongoing  map[string]BranchLockInfo
+	defers   map[string]bool
+	released map[string]BranchLockInfo     // locks with deferred unlocks released manually
+	nested   map[string]int                // re-acquisitions of held locks (reported as reentrant locks)
+	acquired map[string]int                // acquisitions on the path
+	deferred map[string][]token.Pos        // deferred unlocks registered on the path
+	labels   map[string][]ast.Stmt         // goto targets: statements starting from the label (shared between clones)
+	errors   *[]MissingUnlock              // Pointer to shared slice for collecting errors
+	early    *[]EarlyUnlockReturn          // Pointer to shared slice for collecting early unlock returns
+	doubles  *[]DoubleDeferredUnlock       // Pointer to shared slice for collecting double deferred unlocks
+	left     bool                          // true if the path left the function (returned or panicked)
+	jumped   bool                          // true if the path jumped elsewhere (break, continue or goto)
+	flagVars map[*types.Var]bool           // tracked bool flags (see boolFlags), shared between clones
+	flags    flagValues                    // known values of the flags on the path
+	mirrors  map[*types.Var]flagMirror     // flags mirroring the lock state (see joinFlags)
+	guards   map[string][]ast.Expr         // conditions of the guarded deferred unlocks (see guardedDeferUnlock)
+	failures map[*types.Var]BranchLockInfo // error variables set to nil only if the lock is acquired (see OnNilError)
 
 	// For wrapper support
 	registry *WrapperRegistry
@@ -33,10 +72,23 @@ type BranchTracker struct {
 
 func NewBranchTracker() *BranchTracker {
 	errors := make([]MissingUnlock, 0)
+	early := make([]EarlyUnlockReturn, 0)
+	doubles := make([]DoubleDeferredUnlock, 0)
 	return &BranchTracker{
 		ongoing:  make(map[string]BranchLockInfo),
 		defers:   make(map[string]bool),
+		released: make(map[string]BranchLockInfo),
+		nested:   make(map[string]int),
+		acquired: make(map[string]int),
+		deferred: make(map[string][]token.Pos),
+		labels:   make(map[string][]ast.Stmt),
 		errors:   &errors,
+		early:    &early,
+		doubles:  &doubles,
+		flags:    make(flagValues),
+		mirrors:  make(map[*types.Var]flagMirror),
+		guards:   make(map[string][]ast.Expr),
+		failures: make(map[*types.Var]BranchLockInfo),
 		registry: nil,
 		typeInfo: nil,
 	}
@@ -44,10 +96,23 @@ func NewBranchTracker() *BranchTracker {
 
 func NewBranchTrackerWithWrappers(registry *WrapperRegistry, typeInfo *types.Info) *BranchTracker {
 	errors := make([]MissingUnlock, 0)
+	early := make([]EarlyUnlockReturn, 0)
+	doubles := make([]DoubleDeferredUnlock, 0)
 	return &BranchTracker{
 		ongoing:  make(map[string]BranchLockInfo),
 		defers:   make(map[string]bool),
+		released: make(map[string]BranchLockInfo),
+		nested:   make(map[string]int),
+		acquired: make(map[string]int),
+		deferred: make(map[string][]token.Pos),
+		labels:   make(map[string][]ast.Stmt),
 		errors:   &errors,
+		early:    &early,
+		doubles:  &doubles,
+		flags:    make(flagValues),
+		mirrors:  make(map[*types.Var]flagMirror),
+		guards:   make(map[string][]ast.Expr),
+		failures: make(map[*types.Var]BranchLockInfo),
 		registry: registry,
 		typeInfo: typeInfo,
 	}
@@ -58,7 +123,19 @@ func (t *BranchTracker) Clone() *BranchTracker {
 	clone := &BranchTracker{
 		ongoing:  make(map[string]BranchLockInfo, len(t.ongoing)),
 		defers:   make(map[string]bool, len(t.defers)),
+		released: make(map[string]BranchLockInfo, len(t.released)),
+		nested:   make(map[string]int, len(t.nested)),
+		acquired: make(map[string]int, len(t.acquired)),
+		deferred: make(map[string][]token.Pos, len(t.deferred)),
+		labels:   t.labels,
 		errors:   t.errors, // Share pointer to collect all errors
+		early:    t.early,
+		doubles:  t.doubles,
+		flagVars: t.flagVars,
+		flags:    t.flags.clone(),
+		mirrors:  make(map[*types.Var]flagMirror, len(t.mirrors)),
+		guards:   make(map[string][]ast.Expr, len(t.guards)),
+		failures: make(map[*types.Var]BranchLockInfo, len(t.failures)),
 		registry: t.registry,
 		typeInfo: t.typeInfo,
 	}
@@ -68,6 +145,27 @@ func (t *BranchTracker) Clone() *BranchTracker {
 	for k, v := range t.defers {
 		clone.defers[k] = v
 	}
+	for k, v := range t.released {
+		clone.released[k] = v
+	}
+	for k, v := range t.nested {
+		clone.nested[k] = v
+	}
+	for k, v := range t.acquired {
+		clone.acquired[k] = v
+	}
+	for k, v := range t.deferred {
+		clone.deferred[k] = slices.Clone(v)
+	}
+	for k, v := range t.mirrors {
+		clone.mirrors[k] = v
+	}
+	for k, v := range t.guards {
+		clone.guards[k] = slices.Clone(v)
+	}
+	for k, v := range t.failures {
+		clone.failures[k] = v
+	}
 	return clone
 }
 
@@ -76,6 +174,57 @@ func (t *BranchTracker) Errors() []MissingUnlock {
 	return *t.errors
 }
 
+// EarlyUnlockReturns returns all collected returns made after releasing a lock
+// with a deferred unlock.
+func (t *BranchTracker) EarlyUnlockReturns() []EarlyUnlockReturn {
+	return *t.early
+}
+
+// DoubleDeferredUnlocks returns all collected deferred unlocks exceeding the acquisitions.
+func (t *BranchTracker) DoubleDeferredUnlocks() []DoubleDeferredUnlock {
+	return *t.doubles
+}
+
+// CheckEnd checks the state at the end of the function body, which is an implicit return,
+// unless every path has already left the function.
+func (t *BranchTracker) CheckEnd(pos token.Pos) {
+	if t.left {
+		return
+	}
+	t.checkReturnAfterEarlyUnlock(pos)
+}
+
+// CollectLabels records the labeled statements of the function body as goto targets,
+// along with the statements following them in the same block.
+func (t *BranchTracker) CollectLabels(body *ast.BlockStmt) {
+	collect := func(stmts []ast.Stmt) {
+		for i, stmt := range stmts {
+			if labeled, ok := stmt.(*ast.LabeledStmt); ok {
+				t.labels[labeled.Label.Name] = stmts[i:]
+			}
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			collect(s.List)
+		case *ast.CaseClause:
+			collect(s.Body)
+		case *ast.CommClause:
+			collect(s.Body)
+		}
+		return true
+	})
+}
+
+// CollectFlags records the bool flags of the function body to track along the paths (see boolFlags).
+func (t *BranchTracker) CollectFlags(body *ast.BlockStmt) {
+	t.flagVars = boolFlags(body, t.typeInfo)
+}
+
 // AnalyzeStatements analyzes a sequence of statements for missing unlocks.
 func (t *BranchTracker) AnalyzeStatements(stmts []ast.Stmt) {
 	for _, stmt := range stmts {
@@ -84,18 +233,39 @@ func (t *BranchTracker) AnalyzeStatements(stmts []ast.Stmt) {
 }
 
 func (t *BranchTracker) analyzeStmt(stmt ast.Stmt) {
+	// Labels don't affect the lock state, analyze the labeled statement itself
+	if labeled, ok := stmt.(*ast.LabeledStmt); ok {
+		t.analyzeStmt(labeled.Stmt)
+		return
+	}
+
+	// Follow forward gotos with the current lock state
+	if branch, ok := stmt.(*ast.BranchStmt); ok && branch.Tok != token.FALLTHROUGH {
+		t.jumped = true
+		if branch.Tok == token.GOTO && branch.Label != nil {
+			t.analyzeGoto(branch)
+		}
+		return
+	}
+
 	// Check for lock acquisition (direct)
 	if e := subjectForLockCall(stmt); e != nil {
 		// Only track if it's actually a sync.Mutex or sync.RWMutex
 		if IsMutexType(e, t.typeInfo) {
-			selector := StrExpr(e)
+			selector := LockSelector(e, t.typeInfo)
 			if _, exists := t.ongoing[selector]; !exists {
 				t.ongoing[selector] = BranchLockInfo{
 					selector: selector,
 					pos:      stmt.Pos(),
 					wrapper:  nil,
+					read:     isReadLockCall(stmt),
 				}
+			} else {
+				t.nested[selector]++
 			}
+			t.acquired[selector]++
+			delete(t.released, selector)
+			t.dropMirrors(selector)
 		}
 	}
 
@@ -105,121 +275,463 @@ func (t *BranchTracker) analyzeStmt(stmt ast.Stmt) {
 	// Check for deferred unlock (direct)
 	if e := subjectForDeferUnlockCall(stmt); e != nil {
 		if IsMutexType(e, t.typeInfo) {
-			selector := StrExpr(e)
+			selector := LockSelector(e, t.typeInfo)
 			t.defers[selector] = true
+			t.countDeferredUnlock(selector, stmt.Pos())
 		}
 	}
 
 	// Check for deferred wrapper unlock
 	t.checkDeferredWrapperUnlock(stmt)
 
+	// Check for unlock deferred under a condition
+	if e, guard := guardedDeferUnlock(stmt); e != nil && IsMutexType(e, t.typeInfo) {
+		selector := LockSelector(e, t.typeInfo)
+		t.guards[selector] = append(t.guards[selector], guard)
+	}
+
 	// Check for direct unlock
 	if e := subjectForUnlockCall(stmt); e != nil {
 		if IsMutexType(e, t.typeInfo) {
-			selector := StrExpr(e)
-			delete(t.ongoing, selector)
+			selector := LockSelector(e, t.typeInfo)
+			if t.nested[selector] > 0 {
+				// Releases the re-acquired lock, the original one is still held
+				t.nested[selector]--
+			} else {
+				if _, held := t.ongoing[selector]; held && t.defers[selector] {
+					t.released[selector] = BranchLockInfo{selector: selector, pos: stmt.Pos()}
+				}
+				delete(t.ongoing, selector)
+				t.dropMirrors(selector)
+			}
 		}
 	}
 
 	// Check for wrapper unlock call
 	t.checkWrapperUnlockCall(stmt)
 
+	// Track the values of the flags
+	for _, v := range t.flags.assign(stmt, t.flagVars, t.typeInfo) {
+		delete(t.mirrors, v)
+	}
+
 	// Check for return statement
 	if ret, ok := stmt.(*ast.ReturnStmt); ok {
 		t.checkReturnWithLocks(ret)
+		t.checkReturnAfterEarlyUnlock(ret.Pos())
+		t.left = true
 		return // Don't recurse into return
 	}
+	if call := CallExpr(stmt); call != nil && isNoReturnCall(call, t.typeInfo) {
+		t.left = true
+		return
+	}
 
 	// Recurse into nested structures
-	t.analyzeNestedStmt(stmt)
+	walkFlow(t, stmt)
 }
 
-func (t *BranchTracker) analyzeNestedStmt(stmt ast.Stmt) {
-	switch s := stmt.(type) {
-	case *ast.IfStmt:
-		// Process init statement in current scope
-		if s.Init != nil {
-			t.analyzeStmt(s.Init)
-		}
+// walkBranch analyzes the statements of a branch of a compound statement (see walkFlow).
+func (t *BranchTracker) walkBranch(stmts []ast.Stmt) {
+	t.AnalyzeStatements(stmts)
+}
 
-		// Fork for if body
-		ifTracker := t.Clone()
-		ifTracker.AnalyzeStatements(s.Body.List)
+// enterFlow analyzes the parts of a compound statement executed before its branches
+// in the current scope: init statements and the wrapper calls made by the conditions
+// (lock wrappers hold the lock on return whatever they report, e.g. if s.tryInit() { ... }).
+// The flags assigned within a loop may have any value in the next iterations.
+func (t *BranchTracker) enterFlow(shape *flowShape) {
+	for _, init := range prefixStmts(shape.stmt) {
+		t.analyzeStmt(init)
+	}
+	for _, expr := range prefixExprs(shape.stmt) {
+		t.applyWrapperCalls(expr)
+	}
+	if shape.kind == flowLoop {
+		t.forgetFlags(assignedFlags(shape.stmt, t.flagVars, t.typeInfo))
+	}
+}
 
-		// Fork for else body if exists
-		if s.Else != nil {
-			elseTracker := t.Clone()
-			switch e := s.Else.(type) {
-			case *ast.BlockStmt:
-				elseTracker.AnalyzeStatements(e.List)
-			case *ast.IfStmt:
-				elseTracker.analyzeStmt(e)
-			}
+// forkFlow analyzes each branch with a clone of the tracker, while blocks are analyzed in place.
+// The if branches contradicting the known values of the flags aren't taken, and the conditions
+// of the taken ones are assumed to hold (see assume).
+func (t *BranchTracker) forkFlow(shape *flowShape, branch int) (*BranchTracker, bool) {
+	switch shape.kind {
+	case flowBlock:
+		return t, true
+	case flowIf:
+		value, known := t.condValue(shape.cond)
+		taken := !known || value == (branch == 0)
+		if branch == 1 {
+			taken = taken && t.exhaustiveIf(shape)
+		}
+		if !taken {
+			return nil, false
 		}
+		fork := t.Clone()
+		fork.assume(shape.cond, branch == 0)
+		return fork, true
+	}
+	return t.Clone(), true
+}
 
-		// After if/else, the lock state is uncertain (could be either branch)
-		// We keep the original state since we can't merge branches
-		// The errors are already collected in each branch
+// joinFlow continues after a compound statement with the lock state of the branches.
+//
+// After an if without else, the lock state is uncertain (the branch may or may not run),
+// so we keep the original state: the errors are already collected in the branch,
+// only the early unlocks are carried over (see mergeReleased).
+// The branches of an exhaustive if/else chain are joined, as well as the ones of an if
+// without else testing a flag mirroring the lock state (see joinFlags) or a flag with a known value.
+//
+// Loop bodies are analyzed once. The post statement of a for loop executes after each iteration;
+// if it changes the lock state, the next iteration runs the body with the new one.
+func (t *BranchTracker) joinFlow(shape *flowShape, ends []flowEnd[*BranchTracker]) {
+	branches := make([]*BranchTracker, len(ends))
+	for i, end := range ends {
+		branches[i] = end.state
+	}
 
-	case *ast.ForStmt:
-		if s.Init != nil {
-			t.analyzeStmt(s.Init)
+	switch shape.kind {
+	case flowBlock:
+	case flowIf:
+		exhaustive := t.exhaustiveIf(shape)
+		if exhaustive {
+			t.joinLocks(branches)
+		}
+		t.mergeReleased(branches, exhaustive)
+		t.joinFlags(branches, exhaustive)
+	case flowLoop:
+		if shape.post == nil || len(branches) == 0 {
+			return
+		}
+		loopTracker := branches[0]
+		before := loopTracker.Clone()
+		loopTracker.analyzeStmt(shape.post)
+		if !loopTracker.sameLocks(before) {
+			nextTracker := loopTracker.Clone()
+			nextTracker.AnalyzeStatements(shape.branches[0].body)
 		}
-		// Fork for loop body
-		loopTracker := t.Clone()
-		loopTracker.AnalyzeStatements(s.Body.List)
+	case flowSwitch, flowSelect:
+		t.mergeReleased(branches, shape.exhaustive)
+		t.joinFlags(branches, shape.exhaustive)
+	}
+}
+
+// condValue evaluates the if condition testing the tracked flags with their known values.
+func (t *BranchTracker) condValue(cond ast.Expr) (bool, bool) {
+	if !testsFlags(cond, t.flagVars, t.typeInfo) {
+		return false, false
+	}
+	return t.flags.eval(cond, t.typeInfo)
+}
 
-	case *ast.RangeStmt:
-		// Fork for loop body
-		loopTracker := t.Clone()
-		loopTracker.AnalyzeStatements(s.Body.List)
+// exhaustiveIf checks if one of the branches of the if statement always runs, given the known values
+// of the flags and the flags (errors) mirroring the lock state.
+func (t *BranchTracker) exhaustiveIf(shape *flowShape) bool {
+	_, known := t.condValue(shape.cond)
+	return shape.exhaustive || known || t.mirrorsIn(shape.cond)
+}
 
-	case *ast.SwitchStmt:
-		if s.Init != nil {
-			t.analyzeStmt(s.Init)
+// mergeReleased carries the locks released early on the branches reaching the statement following
+// a branching one (see checkReturnAfterEarlyUnlock) over to the tracker: their deferred unlocks
+// run on an unlocked mutex if any of these branches is taken. Unless the branches are exhaustive,
+// the current state is one of the paths, too. If none of the exhaustive branches reaches
+// the following statement, the path leaves the function or jumps elsewhere.
+// Branches jumping elsewhere (break, continue, goto) aren't followed.
+func (t *BranchTracker) mergeReleased(branches []*BranchTracker, exhaustive bool) {
+	var reaching []*BranchTracker
+	for _, branch := range branches {
+		if !branch.left && !branch.jumped {
+			reaching = append(reaching, branch)
 		}
-		if s.Body != nil {
-			for _, clause := range s.Body.List {
-				if cc, ok := clause.(*ast.CaseClause); ok {
-					caseTracker := t.Clone()
-					caseTracker.AnalyzeStatements(cc.Body)
-				}
+	}
+	if exhaustive {
+		if len(reaching) == 0 {
+			if slices.ContainsFunc(branches, func(b *BranchTracker) bool { return b.jumped }) {
+				t.jumped = true
+			} else {
+				t.left = len(branches) > 0
 			}
+			return
+		}
+		t.released = make(map[string]BranchLockInfo)
+	}
+	for _, branch := range reaching {
+		for selector, info := range branch.released {
+			t.released[selector] = info
 		}
+	}
+}
 
-	case *ast.TypeSwitchStmt:
-		if s.Init != nil {
-			t.analyzeStmt(s.Init)
+// joinLocks joins the lock state of the exhaustive branches reaching the statement following
+// a branching one (if/else chains). A lock held at the end of any of them remains held, while
+// a lock released on all of them doesn't. A held lock has a deferred unlock only if it has one
+// on every branch holding it.
+// The acquisition and deferred unlock counts (see countDeferredUnlock) aren't joined.
+func (t *BranchTracker) joinLocks(branches []*BranchTracker) {
+	var reaching []*BranchTracker
+	for _, branch := range branches {
+		if !branch.left && !branch.jumped {
+			reaching = append(reaching, branch)
 		}
-		if s.Body != nil {
-			for _, clause := range s.Body.List {
-				if cc, ok := clause.(*ast.CaseClause); ok {
-					caseTracker := t.Clone()
-					caseTracker.AnalyzeStatements(cc.Body)
-				}
+	}
+	if len(reaching) == 0 {
+		return
+	}
+
+	ongoing := make(map[string]BranchLockInfo)
+	defers := make(map[string]bool)
+	for _, branch := range reaching {
+		for selector := range branch.defers {
+			defers[selector] = true
+		}
+	}
+	for _, branch := range reaching {
+		for selector, info := range branch.ongoing {
+			// Branches holding the lock without a deferred unlock take precedence
+			if _, held := ongoing[selector]; !held || !branch.defers[selector] {
+				ongoing[selector] = info
+			}
+			if !branch.defers[selector] {
+				delete(defers, selector)
 			}
 		}
+	}
+	t.ongoing = ongoing
+	t.defers = defers
+}
 
-	case *ast.SelectStmt:
-		if s.Body != nil {
-			for _, clause := range s.Body.List {
-				if cc, ok := clause.(*ast.CommClause); ok {
-					caseTracker := t.Clone()
-					caseTracker.AnalyzeStatements(cc.Body)
-				}
+// hasDefaultClause checks if the switch body has the default case.
+func hasDefaultClause(body *ast.BlockStmt) bool {
+	for _, clause := range body.List {
+		if cc, ok := clause.(*ast.CaseClause); ok && cc.List == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sameLocks checks if both trackers hold the same locks.
+func (t *BranchTracker) sameLocks(other *BranchTracker) bool {
+	if len(t.ongoing) != len(other.ongoing) {
+		return false
+	}
+	for selector := range t.ongoing {
+		if _, ok := other.ongoing[selector]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// joinFlags joins the values of the flags on the branches reaching the statement following
+// a branching one: a flag keeps its value only if it has the same value on all of them.
+// A flag having different values on the branches mirrors the lock state if a lock is held
+// on the branches with one value and released on the others, e.g. unlocked after
+//
+//	if cached {
+//	    s.mu.Unlock()
+//	    unlocked = true
+//	}
+//
+// Unless the branches are exhaustive, the current state is one of the paths, too.
+func (t *BranchTracker) joinFlags(branches []*BranchTracker, exhaustive bool) {
+	if len(t.flagVars) == 0 {
+		return
+	}
+	var reaching []*BranchTracker
+	for _, branch := range branches {
+		if !branch.left && !branch.jumped {
+			reaching = append(reaching, branch)
+		}
+	}
+	if !exhaustive {
+		reaching = append(reaching, t)
+	}
+	if len(reaching) == 0 {
+		return
+	}
+
+	flags := make(flagValues)
+	mirrors := make(map[*types.Var]flagMirror)
+	for v := range t.flagVars {
+		value, known := reaching[0].flags[v]
+		mirror, mirrored := reaching[0].mirrors[v]
+		same, allKnown := known, known
+		for _, branch := range reaching[1:] {
+			other, ok := branch.flags[v]
+			allKnown = allKnown && ok
+			same = same && ok && other == value
+			mirrored = mirrored && branch.mirrors[v] == mirror
+		}
+		if same {
+			flags[v] = value
+		}
+		if !same && allKnown {
+			mirror, mirrored = mirrorOf(v, reaching)
+		}
+		if mirrored {
+			mirrors[v] = mirror
+		}
+	}
+	t.flags = flags
+	t.mirrors = mirrors
+}
+
+// mirrorOf returns the lock the flag with known values on the branches mirrors, if any.
+func mirrorOf(v *types.Var, branches []*BranchTracker) (flagMirror, bool) {
+	var selectors []string
+	for _, branch := range branches {
+		for selector := range branch.ongoing {
+			if !slices.Contains(selectors, selector) {
+				selectors = append(selectors, selector)
 			}
 		}
+	}
+	slices.Sort(selectors)
+
+	for _, selector := range selectors {
+		var mirror flagMirror
+		holding, releasing := 0, 0
+		for _, branch := range branches {
+			if info, held := branch.ongoing[selector]; held {
+				mirror.lock = info
+				holding++
+			} else {
+				mirror.released = branch.flags[v]
+				releasing++
+			}
+		}
+		if holding == 0 || releasing == 0 {
+			continue
+		}
+		consistent := true
+		for _, branch := range branches {
+			_, held := branch.ongoing[selector]
+			consistent = consistent && held == (branch.flags[v] != mirror.released)
+		}
+		if consistent {
+			return mirror, true
+		}
+	}
+	return flagMirror{}, false
+}
+
+// assume applies the values of the flags implied by the condition having the value,
+// holding or releasing the locks the flags mirror. The locks of the error wrappers
+// (see OnNilError) the condition reports failing aren't held.
+func (t *BranchTracker) assume(cond ast.Expr, value bool) {
+	if selector, failed, ok := t.failureTest(cond); ok && failed == value {
+		delete(t.ongoing, selector)
+	}
+	for _, v := range t.flags.assume(cond, value, t.flagVars, t.typeInfo) {
+		mirror, ok := t.mirrors[v]
+		if !ok {
+			continue
+		}
+		if t.flags[v] == mirror.released {
+			delete(t.ongoing, mirror.lock.selector)
+		} else if _, held := t.ongoing[mirror.lock.selector]; !held {
+			t.ongoing[mirror.lock.selector] = mirror.lock
+		}
+	}
+}
+
+// mirrorsIn checks if the condition tests a flag (or an error, see failureTest) mirroring the lock state.
+func (t *BranchTracker) mirrorsIn(cond ast.Expr) bool {
+	if _, _, ok := t.failureTest(cond); ok {
+		return true
+	}
+	for v := range condVars(cond, t.typeInfo) {
+		if _, ok := t.mirrors[v]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dropMirrors forgets the flags (and the error variables) mirroring the lock state of the mutex
+// once it changes.
+func (t *BranchTracker) dropMirrors(selector string) {
+	for v, mirror := range t.mirrors {
+		if mirror.lock.selector == selector {
+			delete(t.mirrors, v)
+		}
+	}
+	for v, lock := range t.failures {
+		if lock.selector == selector {
+			delete(t.failures, v)
+		}
+	}
+}
+
+// failureTest checks if the condition compares the error of a lock wrapper holding the lock
+// only on success (see OnNilError) with nil, e.g. err != nil after err := s.acquire()
+// or s.acquire() == nil. It returns the selector of the mutex and whether the condition
+// being true means the wrapper failed (and the lock isn't held).
+func (t *BranchTracker) failureTest(cond ast.Expr) (string, bool, bool) {
+	binary, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || (binary.Op != token.EQL && binary.Op != token.NEQ) || t.typeInfo == nil {
+		return "", false, false
+	}
+	operand := binary.X
+	if t.typeInfo.Types[operand].IsNil() {
+		operand = binary.Y
+	} else if !t.typeInfo.Types[binary.Y].IsNil() {
+		return "", false, false
+	}
+
+	switch e := ast.Unparen(operand).(type) {
+	case *ast.Ident:
+		if lock, ok := t.failures[varOf(e, t.typeInfo)]; ok {
+			return lock.selector, binary.Op == token.NEQ, true
+		}
+	case *ast.CallExpr:
+		if t.registry == nil {
+			break
+		}
+		if selector, wrapper, ok := wrapperCallSelector(e, t.registry, t.typeInfo, WrapperLock); ok && wrapper.OnNilError {
+			return selector, binary.Op == token.NEQ, true
+		}
+	}
+	return "", false, false
+}
+
+// forgetFlags forgets the values of the flags (and the locks they mirror).
+func (t *BranchTracker) forgetFlags(vars []*types.Var) {
+	for _, v := range vars {
+		delete(t.flags, v)
+		delete(t.mirrors, v)
+	}
+}
+
+// guardReleases checks if an unlock deferred under a condition may release the lock,
+// i.e., the condition isn't known not to hold.
+func (t *BranchTracker) guardReleases(selector string) bool {
+	for _, guard := range t.guards[selector] {
+		if value, known := t.flags.eval(guard, t.typeInfo); value || !known {
+			return true
+		}
+	}
+	return false
+}
 
-	case *ast.BlockStmt:
-		t.AnalyzeStatements(s.List)
+// analyzeGoto analyzes the statements at the goto target with the lock state at the jump.
+// Only forward jumps are followed, so that loops built with gotos terminate.
+func (t *BranchTracker) analyzeGoto(branch *ast.BranchStmt) {
+	target, ok := t.labels[branch.Label.Name]
+	if !ok || len(target) == 0 || target[0].Pos() < branch.Pos() {
+		return
 	}
+	gotoTracker := t.Clone()
+	gotoTracker.AnalyzeStatements(target)
 }
 
 // checkReturnWithLocks checks if there are held locks when returning.
 func (t *BranchTracker) checkReturnWithLocks(ret *ast.ReturnStmt) {
 	for selector, lockInfo := range t.ongoing {
 		// Skip if there's a deferred unlock for this lock
-		if t.defers[selector] {
+		if t.defers[selector] || t.guardReleases(selector) {
 			continue
 		}
 		*t.errors = append(*t.errors, MissingUnlock{
@@ -229,47 +741,83 @@ func (t *BranchTracker) checkReturnWithLocks(ret *ast.ReturnStmt) {
 	}
 }
 
+// checkReturnAfterEarlyUnlock checks if there are locks released manually
+// (and not re-acquired) while their deferred unlocks are pending when returning.
+func (t *BranchTracker) checkReturnAfterEarlyUnlock(pos token.Pos) {
+	for _, unlockInfo := range t.released {
+		*t.early = append(*t.early, EarlyUnlockReturn{
+			unlockInfo: unlockInfo,
+			returnPos:  pos,
+		})
+	}
+}
+
 // checkWrapperLockCall checks if a statement is a call to a lock wrapper method.
 func (t *BranchTracker) checkWrapperLockCall(stmt ast.Stmt) {
 	if t.registry == nil || t.typeInfo == nil {
 		return
 	}
 
-	call := CallExpr(stmt)
-	if call == nil {
+	if assign, ok := stmt.(*ast.AssignStmt); ok {
+		for _, lhs := range assign.Lhs {
+			if ident, ok := ast.Unparen(lhs).(*ast.Ident); ok {
+				delete(t.failures, varOf(ident, t.typeInfo))
+			}
+		}
+		for _, call := range wrapperCandidateCalls(stmt) {
+			t.lockWithWrapper(call, call.Pos())
+		}
+		t.trackFailure(assign)
 		return
 	}
+	if call := CallExpr(stmt); call != nil {
+		t.lockWithWrapper(call, stmt.Pos())
+	}
+}
 
-	pkg, name, ok := GetCallInfo(call, t.typeInfo)
-	if !ok {
+// trackFailure records the error variable assigned the result of a lock wrapper holding the lock
+// only on success (see OnNilError), e.g. err in err := s.acquire().
+func (t *BranchTracker) trackFailure(assign *ast.AssignStmt) {
+	if len(assign.Rhs) != 1 {
 		return
 	}
-
-	fqn := FromCallInfo(pkg, name)
-	wrapper, isWrapper := t.registry.Get(fqn)
-	if !isWrapper || wrapper.Kind != WrapperLock {
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
 		return
 	}
-
-	// Get the receiver
-	selector := SelectorExpr(call)
-	if selector == nil {
+	selector, wrapper, ok := wrapperCallSelector(call, t.registry, t.typeInfo, WrapperLock)
+	if !ok || !wrapper.OnNilError {
 		return
 	}
-	receiver := RootSelector(selector)
-	if receiver == nil {
+	ident, ok := ast.Unparen(assign.Lhs[len(assign.Lhs)-1]).(*ast.Ident)
+	if !ok {
 		return
 	}
+	if v := varOf(ident, t.typeInfo); v != nil {
+		if lock, held := t.ongoing[selector]; held {
+			t.failures[v] = lock
+		}
+	}
+}
 
-	effectiveSelector := receiver.Name + "." + wrapper.MutexField
+// lockWithWrapper marks the mutex as held if the call is made to a lock wrapper method.
+func (t *BranchTracker) lockWithWrapper(call *ast.CallExpr, pos token.Pos) {
+	effectiveSelector, wrapper, ok := wrapperCallSelector(call, t.registry, t.typeInfo, WrapperLock)
+	if !ok {
+		return
+	}
+	t.acquired[effectiveSelector]++
+	t.dropMirrors(effectiveSelector)
 	if _, exists := t.ongoing[effectiveSelector]; !exists {
 		t.ongoing[effectiveSelector] = BranchLockInfo{
 			selector: effectiveSelector,
-			pos:      stmt.Pos(),
+			pos:      pos,
 			wrapper: &WrapperInfo{
 				FQN:     wrapper.FQN,
 				LockPos: wrapper.LockPos,
+				Read:    wrapper.Read,
 			},
+			read: wrapper.Read,
 		}
 	}
 }
@@ -280,34 +828,42 @@ func (t *BranchTracker) checkWrapperUnlockCall(stmt ast.Stmt) {
 		return
 	}
 
-	call := CallExpr(stmt)
-	if call == nil {
+	if _, ok := stmt.(*ast.AssignStmt); ok {
+		for _, call := range wrapperCandidateCalls(stmt) {
+			t.unlockWithWrapper(call)
+		}
 		return
 	}
-
-	pkg, name, ok := GetCallInfo(call, t.typeInfo)
-	if !ok {
-		return
+	if call := CallExpr(stmt); call != nil {
+		t.unlockWithWrapper(call)
 	}
+}
 
-	fqn := FromCallInfo(pkg, name)
-	wrapper, isWrapper := t.registry.Get(fqn)
-	if !isWrapper || wrapper.Kind != WrapperUnlock {
-		return
+// unlockWithWrapper releases the mutex if the call is made to an unlock wrapper method
+// of the held lock's variant (see mismatchedUnlock).
+func (t *BranchTracker) unlockWithWrapper(call *ast.CallExpr) {
+	if effectiveSelector, wrapper, ok := wrapperCallSelector(call, t.registry, t.typeInfo, WrapperUnlock); ok {
+		if t.mismatchedUnlock(effectiveSelector, wrapper) {
+			return
+		}
+		if _, held := t.ongoing[effectiveSelector]; held && t.defers[effectiveSelector] {
+			t.released[effectiveSelector] = BranchLockInfo{selector: effectiveSelector, pos: call.Pos()}
+		}
+		delete(t.ongoing, effectiveSelector)
+		t.dropMirrors(effectiveSelector)
 	}
+}
 
-	// Get the receiver
-	selector := SelectorExpr(call)
-	if selector == nil {
+// applyWrapperCalls applies the effects of the wrapper calls made by the expression
+// (e.g., an if condition, a switch tag or a ranged expression) to the lock state.
+func (t *BranchTracker) applyWrapperCalls(expr ast.Expr) {
+	if t.registry == nil || t.typeInfo == nil {
 		return
 	}
-	receiver := RootSelector(selector)
-	if receiver == nil {
-		return
+	for _, call := range exprCalls(expr) {
+		t.lockWithWrapper(call, call.Pos())
+		t.unlockWithWrapper(call)
 	}
-
-	effectiveSelector := receiver.Name + "." + wrapper.MutexField
-	delete(t.ongoing, effectiveSelector)
 }
 
 // checkDeferredWrapperUnlock checks if a statement is a deferred call to an unlock wrapper.
@@ -321,27 +877,60 @@ func (t *BranchTracker) checkDeferredWrapperUnlock(stmt ast.Stmt) {
 		return
 	}
 
-	call := deferStmt.Call
-	pkg, name, ok := GetCallInfo(call, t.typeInfo)
+	if effectiveSelector, wrapper, ok := wrapperCallSelector(deferStmt.Call, t.registry, t.typeInfo, WrapperUnlock); ok {
+		if t.mismatchedUnlock(effectiveSelector, wrapper) {
+			return
+		}
+		t.defers[effectiveSelector] = true
+		t.countDeferredUnlock(effectiveSelector, stmt.Pos())
+	}
+}
+
+// mismatchedUnlock checks if the unlock wrapper releases the other variant of the held lock
+// (e.g., runlock() for s.mu.Lock()): that's a fatal error at run time, so the lock
+// isn't considered released and is reported as missing an unlock.
+func (t *BranchTracker) mismatchedUnlock(selector string, wrapper WrapperMethod) bool {
+	lock, held := t.ongoing[selector]
+	return held && lock.read != wrapper.Read
+}
+
+// countDeferredUnlock registers a deferred unlock on the path. A function may release the lock
+// acquired by its caller, so a single deferred unlock without acquisitions is fine, but every
+// following one must be matched by an acquisition on the path.
+func (t *BranchTracker) countDeferredUnlock(selector string, pos token.Pos) {
+	prev := t.deferred[selector]
+	t.deferred[selector] = append(prev, pos)
+	if len(prev) > 0 && len(prev) >= t.acquired[selector] {
+		*t.doubles = append(*t.doubles, DoubleDeferredUnlock{
+			selector: selector,
+			firstPos: prev[len(prev)-1],
+			deferPos: pos,
+		})
+	}
+}
+
+// wrapperCallSelector returns the selector of the mutex a call to a wrapper of the given kind
+// locks or unlocks (e.g., "s.mu" for s.lock()), along with the wrapper.
+func wrapperCallSelector(call *ast.CallExpr, registry *WrapperRegistry, info *types.Info, kind WrapperKind) (string, WrapperMethod, bool) {
+	pkg, name, ok := GetCallInfo(call, info)
 	if !ok {
-		return
+		return "", WrapperMethod{}, false
 	}
 
-	fqn := FromCallInfo(pkg, name)
-	wrapper, isWrapper := t.registry.Get(fqn)
-	if !isWrapper || wrapper.Kind != WrapperUnlock {
-		return
+	wrapper, isWrapper := registry.Get(FromCallInfo(pkg, name))
+	if !isWrapper || wrapper.Kind != kind {
+		return "", WrapperMethod{}, false
 	}
 
+	// Get the receiver
 	selector := SelectorExpr(call)
 	if selector == nil {
-		return
+		return "", WrapperMethod{}, false
 	}
 	receiver := RootSelector(selector)
 	if receiver == nil {
-		return
+		return "", WrapperMethod{}, false
 	}
 
-	effectiveSelector := receiver.Name + "." + wrapper.MutexField
-	t.defers[effectiveSelector] = true
+	return LockSelector(receiver, info) + "." + wrapper.MutexField, wrapper, true
 }
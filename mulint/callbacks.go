@@ -0,0 +1,92 @@
+package mulint
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+)
+
+// checkSyncCallbacks checks if function values (e.g., s.load) passed to a synchronous
+// callback-taker acquire the mutex held by the scope.
+// Func literal arguments of known callback-takers are analyzed in place as part of the scope,
+// while the arguments of functions declared with the calls-back effect are checked here.
+// Package functions calling their function parameters synchronously (see FunctionSummary.CallbackParams)
+// are callback-takers for these parameters, including func literals.
+func (a *Analyzer) checkSyncCallbacks(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
+	callsBack := a.callsBack(call)
+	takesCallbacks := callsBack || isSyncCallbackTaker(call, a.info)
+	params := a.callbackParamsOf(call)
+	if !takesCallbacks && len(params) == 0 {
+		return
+	}
+
+	key := scope.Key(currentFQN)
+	for i, arg := range call.Args {
+		_, isLit := arg.(*ast.FuncLit)
+		switch {
+		case slices.Contains(params, i) && !(isLit && callsFuncLitsInPlace(call, a.info)):
+		case !takesCallbacks, isLit && !callsBack:
+			continue
+		}
+		if a.callbackLocks(arg, key) {
+			a.recordError(currentFQN, scope, call)
+			return
+		}
+	}
+}
+
+// callbackParamsOf returns the indices of the arguments the called package function calls synchronously.
+func (a *Analyzer) callbackParamsOf(call *ast.CallExpr) []int {
+	pkg, name, ok := GetCallInfo(call, a.info)
+	if !ok {
+		return nil
+	}
+	return a.summaryOf(FromCallInfo(pkg, name)).CallbackParams
+}
+
+// isMaybeSyncCallbackTaker checks if the call registers callbacks with a function configured
+// as maybe-synchronous (see Config.MaybeSyncCallbacks), e.g. an emitter that may fire in place.
+func (c Config) isMaybeSyncCallbackTaker(fqn FQN) bool {
+	for _, item := range splitList(c.MaybeSyncCallbacks) {
+		if item == string(fqn) || item == strings.ReplaceAll(string(fqn), ":", ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMaybeSyncCallbacks checks if the callbacks registered under lock with a maybe-synchronous
+// registration function (e.g., s.emitter.On("x", fn)) acquire the mutex held by the scope.
+// That's fine as long as the callbacks are invoked asynchronously, but deadlocks otherwise.
+// Registrations within nested scopes are reported once.
+func (a *Analyzer) checkMaybeSyncCallbacks(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
+	if a.config.MaybeSyncCallbacks == "" {
+		return
+	}
+	for _, d := range a.diagnostics {
+		if e, ok := d.(MaybeSyncCallbackError); ok && e.callPos.pos == call.Pos() {
+			return
+		}
+	}
+	pkg, name, ok := GetCallInfo(call, a.info)
+	if !ok {
+		return
+	}
+	callee := FromCallInfo(pkg, name)
+	if !a.config.isMaybeSyncCallbackTaker(callee) {
+		return
+	}
+
+	key := scope.Key(currentFQN)
+	for _, arg := range call.Args {
+		if a.callbackLocks(arg, key) {
+			a.diagnostics = append(a.diagnostics, NewMaybeSyncCallbackError(
+				NewLocation(call.Pos()),
+				NewLocation(scope.Pos()),
+				NewLocation(arg.Pos()),
+				callee,
+			))
+			return
+		}
+	}
+}
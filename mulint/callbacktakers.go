@@ -0,0 +1,103 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// callbackKind tells when a function invokes its callback (func-typed) arguments.
+type callbackKind int
+
+const (
+	// callbackUnknown is the kind of functions not listed in callbackTakers.
+	callbackUnknown callbackKind = iota
+	// callbackSync functions invoke the callbacks before returning, i.e., under the caller's locks.
+	callbackSync
+	// callbackAsync functions invoke the callbacks later or in another goroutine,
+	// after the caller's locks may have been released (e.g., registered handlers).
+	callbackAsync
+)
+
+// callbackTakers classifies the well-known callback-taking functions of the standard library
+// and golang.org/x. Func literals passed to synchronous ones are analyzed as part of the caller's
+// lock scopes, while the ones passed to asynchronous (and, unless -assume-sync-callbacks is set,
+// unknown) functions are skipped.
+var callbackTakers = map[FQN]callbackKind{
+	// Sorting and searching
+	"sort.Slice":              callbackSync,
+	"sort.SliceStable":        callbackSync,
+	"sort.Search":             callbackSync,
+	"sort.Find":               callbackSync,
+	"slices.SortFunc":         callbackSync,
+	"slices.SortStableFunc":   callbackSync,
+	"slices.BinarySearchFunc": callbackSync,
+	"slices.IndexFunc":        callbackSync,
+	"slices.ContainsFunc":     callbackSync,
+	"slices.DeleteFunc":       callbackSync,
+	"strings.Map":             callbackSync,
+	"strings.FieldsFunc":      callbackSync,
+	"strings.IndexFunc":       callbackSync,
+
+	// File tree walks
+	"path/filepath.Walk":    callbackSync,
+	"path/filepath.WalkDir": callbackSync,
+	"io/fs.WalkDir":         callbackSync,
+
+	// One-time initialization: the first caller runs the function, the others wait for it
+	"sync.Once:Do": callbackSync,
+
+	"golang.org/x/sync/singleflight.Group:Do":     callbackSync,
+	"golang.org/x/sync/singleflight.Group:DoChan": callbackAsync,
+
+	// Timers and cancellation callbacks
+	"time.AfterFunc":    callbackAsync,
+	"context.AfterFunc": callbackAsync,
+
+	// Goroutines
+	"sync.WaitGroup:Go":                      callbackAsync,
+	"golang.org/x/sync/errgroup.Group:Go":    callbackAsync,
+	"golang.org/x/sync/errgroup.Group:TryGo": callbackAsync,
+
+	// HTTP handler registration: handlers run on incoming requests
+	"net/http.HandleFunc":          callbackAsync,
+	"net/http.ServeMux:HandleFunc": callbackAsync,
+
+	"runtime.SetFinalizer": callbackAsync,
+}
+
+// callbackKindOf returns when the called function invokes its callback arguments.
+func callbackKindOf(call *ast.CallExpr, info *types.Info) callbackKind {
+	if info == nil {
+		return callbackUnknown
+	}
+	pkg, name, ok := GetCallInfo(call, info)
+	if !ok {
+		return callbackUnknown
+	}
+	return callbackTakers[FromCallInfo(pkg, name)]
+}
+
+// isSyncCallbackTaker checks if the call invokes its callback arguments synchronously.
+func isSyncCallbackTaker(call *ast.CallExpr, info *types.Info) bool {
+	return callbackKindOf(call, info) == callbackSync
+}
+
+// callsFuncLitsInPlace checks if the func literal arguments of the call are to be analyzed
+// as executing under the caller's locks: the callee is a known synchronous callback-taker or,
+// with -assume-sync-callbacks, any function not known to invoke its callbacks asynchronously.
+func callsFuncLitsInPlace(call *ast.CallExpr, info *types.Info) bool {
+	switch callbackKindOf(call, info) {
+	case callbackSync:
+		return true
+	case callbackAsync:
+		return false
+	}
+	if !config.AssumeSyncCallbacks || info == nil {
+		return false
+	}
+	// Conversions and builtins (e.g., append) don't call the literals
+	if tv, ok := info.Types[call.Fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+		return false
+	}
+	return true
+}
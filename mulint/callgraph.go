@@ -0,0 +1,161 @@
+package mulint
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Call graph algorithms to resolve dynamic calls (interface methods and function values) with.
+// The static algorithm (the default) only follows the calls of statically known functions.
+const (
+	CallGraphStatic = "static"
+	CallGraphCHA    = "cha"
+	CallGraphRTA    = "rta"
+	CallGraphVTA    = "vta"
+)
+
+// DynamicCalls holds the possible callees of the dynamic calls within the package.
+type DynamicCalls struct {
+	// Sites maps call positions (the opening parenthesis) to the possible callees.
+	Sites map[token.Pos][]FQN
+	// Edges maps functions to the possible callees of their dynamic calls.
+	Edges map[FQN][]FQN
+}
+
+// ResolveDynamicCalls builds the SSA form of the package and resolves its dynamic calls
+// using the given call graph algorithm: CHA is the fastest and the least precise one,
+// RTA only considers the types instantiated in the package, and VTA tracks the values
+// flowing into the call sites.
+func ResolveDynamicCalls(pass *analysis.Pass, algorithm string) (_ *DynamicCalls, err error) {
+	if algorithm == "" || algorithm == CallGraphStatic {
+		return nil, nil
+	}
+
+	// The SSA builder panics on the code it doesn't support; fail the analysis of the package instead
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to build the %s call graph: %v", algorithm, r)
+		}
+	}()
+
+	prog, pkg := buildSSA(pass)
+
+	var graph *callgraph.Graph
+	switch algorithm {
+	case CallGraphCHA:
+		graph = cha.CallGraph(prog)
+	case CallGraphRTA:
+		var roots []*ssa.Function
+		for fn := range ssautil.AllFunctions(prog) {
+			if fn.Pkg == pkg && fn.Parent() == nil {
+				roots = append(roots, fn)
+			}
+		}
+		graph = rta.Analyze(roots, true).CallGraph
+	case CallGraphVTA:
+		graph = vta.CallGraph(ssautil.AllFunctions(prog), cha.CallGraph(prog))
+	default:
+		return nil, fmt.Errorf("unknown call graph algorithm: %s", algorithm)
+	}
+
+	calls := &DynamicCalls{
+		Sites: make(map[token.Pos][]FQN),
+		Edges: make(map[FQN][]FQN),
+	}
+
+	for fn, node := range graph.Nodes {
+		if fn == nil || fn.Pkg != pkg {
+			continue
+		}
+		caller, ok := ssaFQN(fn)
+		if !ok {
+			continue
+		}
+
+		for _, edge := range node.Out {
+			if edge.Site == nil || edge.Site.Common().StaticCallee() != nil {
+				continue
+			}
+			callee, ok := ssaFQN(edge.Callee.Func)
+			if !ok {
+				continue
+			}
+			if pos := edge.Site.Common().Pos(); !slices.Contains(calls.Sites[pos], callee) {
+				calls.Sites[pos] = append(calls.Sites[pos], callee)
+			}
+			if !slices.Contains(calls.Edges[caller], callee) {
+				calls.Edges[caller] = append(calls.Edges[caller], callee)
+			}
+		}
+	}
+
+	for _, callees := range calls.Sites {
+		slices.Sort(callees)
+	}
+	return calls, nil
+}
+
+// buildSSA builds the SSA form of the analyzed package; dependencies are created
+// from their type information only.
+func buildSSA(pass *analysis.Pass) (*ssa.Program, *ssa.Package) {
+	prog := ssa.NewProgram(pass.Fset, ssa.InstantiateGenerics)
+
+	created := make(map[*types.Package]bool)
+	var createAll func(pkgs []*types.Package)
+	createAll = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if !created[p] {
+				created[p] = true
+				prog.CreatePackage(p, nil, nil, true)
+				createAll(p.Imports())
+			}
+		}
+	}
+	createAll(pass.Pkg.Imports())
+
+	pkg := prog.CreatePackage(pass.Pkg, pass.Files, pass.TypesInfo, false)
+	pkg.Build()
+	return prog, pkg
+}
+
+// ssaFQN returns the FQN of a function; calls within closures are attributed
+// to the enclosing function, as the visitor does.
+func ssaFQN(fn *ssa.Function) (FQN, bool) {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	obj, ok := fn.Object().(*types.Func)
+	if !ok {
+		return "", false
+	}
+	return FromFunc(obj), true
+}
+
+// AddDynamicCalls makes the resolved dynamic calls part of the call graph.
+func (a *Analyzer) AddDynamicCalls(calls *DynamicCalls) {
+	if calls == nil {
+		return
+	}
+	a.dynamicCalls = calls.Sites
+	a.funcSummaries = nil // summaries depend on the call graph
+	for caller, callees := range calls.Edges {
+		for _, callee := range callees {
+			if !slices.Contains(a.calls[caller], callee) {
+				a.calls[caller] = append(a.calls[caller], callee)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// checkEscapingClosures reports func literals accessing guarded fields (see GuardIndex) of captured values
+// that escape the function: returned, stored in fields, map or slice elements or package-level variables,
+// or sent on channels. Such literals run later, without the lock, unless they acquire it themselves.
+// These are the literals skipped by the reentrant lock checks, as they don't run under the caller's lock.
+func (a *Analyzer) checkEscapingClosures() {
+	guards := a.guardIndex()
+
+	for _, fn := range a.funcs {
+		if fn.Body == nil || !a.isLive(a.declFQN(fn)) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			for _, escape := range a.escapingLiterals(n) {
+				access, mutex := a.unguardedCapture(escape.lit, guards)
+				if access == nil {
+					continue
+				}
+				a.diagnostics = append(a.diagnostics, NewEscapingClosureError(
+					NewLocation(escape.lit.Pos()),
+					NewLocation(access.Pos()),
+					StrExpr(access),
+					mutex,
+					escape.how,
+				))
+			}
+			return true
+		})
+	}
+}
+
+// escapingLiteral is a func literal escaping the function it's declared in.
+type escapingLiteral struct {
+	lit *ast.FuncLit
+	how string // "returned", "stored" or "sent"
+}
+
+// escapingLiterals returns the func literals escaping the function via the statement.
+func (a *Analyzer) escapingLiterals(n ast.Node) []escapingLiteral {
+	var escapes []escapingLiteral
+	add := func(expr ast.Expr, how string) {
+		if lit, ok := ast.Unparen(expr).(*ast.FuncLit); ok {
+			escapes = append(escapes, escapingLiteral{lit: lit, how: how})
+		}
+	}
+
+	switch s := n.(type) {
+	case *ast.ReturnStmt:
+		for _, result := range s.Results {
+			add(result, "returned")
+		}
+	case *ast.SendStmt:
+		add(s.Value, "sent")
+	case *ast.AssignStmt:
+		if len(s.Lhs) != len(s.Rhs) {
+			break
+		}
+		for i, lhs := range s.Lhs {
+			if a.isNonLocal(lhs) {
+				add(s.Rhs[i], "stored")
+			}
+		}
+	}
+	return escapes
+}
+
+// isNonLocal checks if the assignment target outlives the function: a field,
+// a map or slice element, or a package-level variable.
+func (a *Analyzer) isNonLocal(lhs ast.Expr) bool {
+	switch e := ast.Unparen(lhs).(type) {
+	case *ast.SelectorExpr, *ast.IndexExpr:
+		return true
+	case *ast.Ident:
+		v, ok := a.info.ObjectOf(e).(*types.Var)
+		return ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+	}
+	return false
+}
+
+// unguardedCapture returns the first access in the literal to a guarded field of a captured value,
+// along with the guarding mutex, unless the literal acquires the mutex itself.
+func (a *Analyzer) unguardedCapture(lit *ast.FuncLit, guards *GuardIndex) (*ast.SelectorExpr, string) {
+	locked := make(map[string]bool)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if subject := subjectForLockCall(n); subject != nil && IsMutexType(subject, a.info) {
+			locked[typedMutexKey(subject, a.info)] = true
+		}
+		return true
+	})
+
+	var access *ast.SelectorExpr
+	var mutex string
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if access != nil {
+			return false
+		}
+		field := guardableField(n, a.info)
+		if field == "" {
+			return true
+		}
+		sel := n.(*ast.SelectorExpr)
+		root := RootSelector(sel)
+		if root == nil {
+			return true
+		}
+		// Values declared within the literal aren't captured
+		if obj := a.info.ObjectOf(root); obj == nil || (lit.Pos() <= obj.Pos() && obj.Pos() < lit.End()) {
+			return true
+		}
+		for _, m := range guards.GuardedBy(field) {
+			if locked[m] {
+				return true
+			}
+		}
+		if mutexes := guards.GuardedBy(field); len(mutexes) > 0 {
+			access, mutex = sel, mutexes[0]
+			return false
+		}
+		return true
+	})
+	return access, mutex
+}
@@ -0,0 +1,110 @@
+package mulint
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// Color modes for the -color flag.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ANSI escape sequences used for colored output.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiCyan    = "\x1b[36m"
+	ansiBlue    = "\x1b[34m"
+)
+
+// How the standalone CLI prints the diagnostics (see SetOutput). Other drivers (go vet, golangci-lint)
+// can't be told apart, so the analyzer doesn't color its output unless asked to.
+var (
+	jsonOutput     bool
+	terminalOutput bool
+)
+
+// SetOutput tells the analyzer how the standalone CLI prints the diagnostics: as JSON (never colored)
+// or as plain text, to a terminal (colored in the auto mode) or not.
+func SetOutput(json, terminal bool) {
+	jsonOutput, terminalOutput = json, terminal
+}
+
+// colorOutput checks if diagnostics should be rendered with colors and source snippets.
+func colorOutput() bool {
+	if config.Format == FormatShort || jsonOutput {
+		return false
+	}
+	switch config.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return terminalOutput
+	}
+}
+
+// paint wraps the text into the given ANSI style.
+func paint(style, text string) string {
+	return style + text + ansiReset
+}
+
+// snippet renders the source line of the position with a line number gutter and
+// a marker line underlining the call starting at the position's column:
+//
+//	14 |	c.mu.Lock()
+//	   |	^^^^^^^^^^^
+func snippet(position token.Position, marker byte, style string) string {
+	line := sourceLine(position)
+	if line == "" || position.Column < 1 || position.Column > len(line) {
+		return ""
+	}
+
+	number := fmt.Sprintf("%5d", position.Line)
+	gutter := strings.Repeat(" ", len(number))
+
+	// Keep tabs in the indentation, so the marker lines up with the source
+	var indent strings.Builder
+	for _, ch := range line[:position.Column-1] {
+		if ch == '\t' {
+			indent.WriteByte('\t')
+		} else {
+			indent.WriteByte(' ')
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s\n%s %s %s%s\n",
+		paint(ansiBlue, number), paint(ansiBlue, "|"), line,
+		gutter, paint(ansiBlue, "|"), indent.String(),
+		paint(style, strings.Repeat(string(marker), callWidth(line[position.Column-1:]))),
+	)
+}
+
+// callWidth returns the length of the call expression at the beginning of the text,
+// i.e. up to the closing parenthesis of the first argument list.
+// Falls back to the first word if there are no parentheses.
+func callWidth(text string) int {
+	depth := 0
+	for i, ch := range text {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case ' ', '\t':
+			if depth == 0 {
+				return max(i, 1)
+			}
+		}
+	}
+	return max(len(strings.TrimRight(text, " \t")), 1)
+}
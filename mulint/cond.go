@@ -0,0 +1,192 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// CondRegistry links sync.Cond values to the mutexes they were constructed with.
+// Condition variables are identified by their struct field ("Queue.cond") or
+// package variable name, mutexes by their typed key ("Queue.mu").
+type CondRegistry struct {
+	lockers map[string]string
+	info    *types.Info
+}
+
+func NewCondRegistry(info *types.Info) *CondRegistry {
+	return &CondRegistry{
+		lockers: make(map[string]string),
+		info:    info,
+	}
+}
+
+// Collect records sync.Cond initializations found in the node:
+//
+//	q.cond = sync.NewCond(&q.mu)
+//	q := &Queue{cond: sync.NewCond(&mu)}
+//	q.cond.L = &q.mu
+func (r *CondRegistry) Collect(node ast.Node) {
+	forEachInit(node, r.info, func(target ast.Expr, key string, value ast.Expr) {
+		// cond.L = &mu
+		if sel, ok := target.(*ast.SelectorExpr); ok && sel.Sel.Name == "L" && isCondType(r.info.TypeOf(sel.X)) {
+			if condKey := fieldOrVarKey(sel.X, r.info); condKey != "" {
+				r.lockers[condKey] = typedMutexKey(value, r.info)
+			}
+			return
+		}
+		if locker := newCondLocker(value, r.info); locker != nil && key != "" {
+			r.lockers[key] = typedMutexKey(locker, r.info)
+		}
+	})
+}
+
+// Locker returns the mutex key the condition variable was constructed with, if known.
+func (r *CondRegistry) Locker(cond ast.Expr) (string, bool) {
+	key := fieldOrVarKey(cond, r.info)
+	if key == "" {
+		return "", false
+	}
+	locker, ok := r.lockers[key]
+	return locker, ok
+}
+
+// CondForWait returns the condition variable expression if the call is a Wait() on a sync.Cond.
+func (r *CondRegistry) CondForWait(call *ast.CallExpr) ast.Expr {
+	selector := SelectorExpr(call)
+	if selector == nil || selector.Sel.Name != "Wait" || !isCondType(r.info.TypeOf(selector.X)) {
+		return nil
+	}
+	return selector.X
+}
+
+// newCondLocker returns the locker argument if the expression is a sync.NewCond(...) call.
+func newCondLocker(expr ast.Expr, info *types.Info) ast.Expr {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil
+	}
+	pkg, name, ok := GetCallInfo(call, info)
+	if !ok || pkg != "sync" || name != "NewCond" {
+		return nil
+	}
+	return call.Args[0]
+}
+
+// isCondType checks if a type is sync.Cond or *sync.Cond.
+func isCondType(t types.Type) bool {
+	named, ok := derefType(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "sync" && named.Obj().Name() == "Cond"
+}
+
+// checkCondWaits verifies that sync.Cond.Wait() is called while holding the mutex
+// the condition variable was constructed with, and not while holding only other mutexes.
+func (a *Analyzer) checkCondWaits() {
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		held := a.heldAtCalls(fqn)
+		requires := FuncDirectives(fn)[requiresDirective]
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			// Func literals may run with a different lock state
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			cond := a.conds.CondForWait(call)
+			if cond == nil {
+				return true
+			}
+			locker, ok := a.conds.Locker(cond)
+			if !ok {
+				return true
+			}
+
+			heldKeys := held[call.Pos()]
+			for _, key := range heldKeys {
+				if key == locker {
+					return true
+				}
+			}
+			// Helpers waiting on behalf of their callers are expected to be called under lock
+			if len(heldKeys) == 0 && (requiresLock(requires, locker) || a.calledUnderLock(fqn, locker)) {
+				return true
+			}
+			a.diagnostics = append(a.diagnostics, NewCondWaitError(NewLocation(call.Pos()), locker, heldKeys))
+			return true
+		})
+	}
+}
+
+// heldAtCalls returns the mutex keys held at each call position within a function.
+func (a *Analyzer) heldAtCalls(fqn FQN) map[token.Pos][]string {
+	held := make(map[token.Pos][]string)
+	tracker, ok := a.scopes[fqn]
+	if !ok {
+		return held
+	}
+
+	for _, scope := range tracker.Scopes() {
+		key := scope.Key(fqn)
+		for _, node := range scope.Nodes() {
+			inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+				for _, existing := range held[call.Pos()] {
+					if existing == key {
+						return
+					}
+				}
+				held[call.Pos()] = append(held[call.Pos()], key)
+			})
+		}
+	}
+
+	for pos := range held {
+		sort.Strings(held[pos])
+	}
+	return held
+}
+
+// requiresLock checks if the annotated requirements ("mu" or "Queue.mu") include the mutex key.
+func requiresLock(requires []string, key string) bool {
+	_, field := SplitSelector(key)
+	for _, r := range requires {
+		if r == key || r == field {
+			return true
+		}
+	}
+	return false
+}
+
+// calledUnderLock checks if the function is called from a scope holding the mutex identified by key.
+func (a *Analyzer) calledUnderLock(fqn FQN, key string) bool {
+	for caller, tracker := range a.scopes {
+		for _, scope := range tracker.Scopes() {
+			if scope.Key(caller) != key {
+				continue
+			}
+			found := false
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if pkg, name, ok := GetCallInfo(call, a.info); ok && FromCallInfo(pkg, name) == fqn {
+						found = true
+					}
+				})
+			}
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
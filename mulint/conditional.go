@@ -2,6 +2,7 @@ package mulint
 
 import (
 	"go/ast"
+	"go/token"
 	"go/types"
 )
 
@@ -14,23 +15,33 @@ import (
 //	        defer a.mu.Unlock()
 //	    }
 //	}
+//
+// The guard may also be a bool field of an options struct parameter (if !opts.NoLock { ... }),
+// the value of which is taken from the struct literal passed at the call site,
+// or of the options struct built from variadic functional options (opts ...Option),
+// the value of which is set by the option constructors passed at the call site (WithoutLocking()).
 type ConditionalLock struct {
 	ParamIndex int    // Index of the bool parameter that controls the lock
 	ParamName  string // Name of the parameter
+	Field      string // Name of the bool field of the options parameter, if any (e.g., "NoLock")
+	Variadic   bool   // True if the options are passed as variadic functional options
+	Default    bool   // Value of the field unless set by a functional option
 	Selector   string // The mutex selector (e.g., "a.mu")
 	Negated    bool   // True if condition is negated (if !lock)
 }
 
 // ConditionalLockRegistry tracks functions with conditional locks.
 type ConditionalLockRegistry struct {
-	locks map[FQN][]ConditionalLock
-	info  *types.Info
+	locks   map[FQN][]ConditionalLock
+	setters map[FQN]map[string]bool // functional option constructors and the bool fields they set
+	info    *types.Info
 }
 
 func NewConditionalLockRegistry(info *types.Info) *ConditionalLockRegistry {
 	return &ConditionalLockRegistry{
-		locks: make(map[FQN][]ConditionalLock),
-		info:  info,
+		locks:   make(map[FQN][]ConditionalLock),
+		setters: make(map[FQN]map[string]bool),
+		info:    info,
 	}
 }
 
@@ -41,29 +52,15 @@ func (r *ConditionalLockRegistry) Get(fqn FQN) []ConditionalLock {
 
 // AnalyzeFunc analyzes a function for conditional lock patterns.
 func (r *ConditionalLockRegistry) AnalyzeFunc(fqn FQN, fn *ast.FuncDecl) {
+	r.analyzeOptionSetter(fqn, fn)
+
 	if fn.Type.Params == nil {
 		return
 	}
 
-	// Build map of bool parameter names to their indices
-	boolParams := make(map[string]int)
-	paramIndex := 0
-	for _, field := range fn.Type.Params.List {
-		// Check if this is a bool type
-		if ident, ok := field.Type.(*ast.Ident); ok && ident.Name == "bool" {
-			for _, name := range field.Names {
-				boolParams[name.Name] = paramIndex
-				paramIndex++
-			}
-		} else {
-			paramIndex += len(field.Names)
-			if len(field.Names) == 0 {
-				paramIndex++ // unnamed parameter
-			}
-		}
-	}
-
-	if len(boolParams) == 0 {
+	boolParams, optionParams := r.conditionParams(fn)
+	variadic := r.functionalOptions(fn)
+	if len(boolParams) == 0 && len(optionParams) == 0 && variadic == nil {
 		return
 	}
 
@@ -74,20 +71,33 @@ func (r *ConditionalLockRegistry) AnalyzeFunc(fqn FQN, fn *ast.FuncDecl) {
 			continue
 		}
 
-		paramName, negated := extractBoolParamCondition(ifStmt.Cond, boolParams)
-		if paramName == "" {
-			continue
-		}
-
 		// Check if the if body contains a lock
 		selector := findLockInBlock(ifStmt.Body)
 		if selector == "" {
 			continue
 		}
 
+		if variadic != nil {
+			if lock, ok := r.extractOptionsCondition(fn, ifStmt.Cond, variadic); ok {
+				lock.Selector = selector
+				r.locks[fqn] = append(r.locks[fqn], lock)
+				continue
+			}
+		}
+
+		paramName, field, negated := r.extractParamCondition(ifStmt.Cond, boolParams, optionParams)
+		if paramName == "" {
+			continue
+		}
+		paramIndex := boolParams[paramName]
+		if field != "" {
+			paramIndex = optionParams[paramName]
+		}
+
 		r.locks[fqn] = append(r.locks[fqn], ConditionalLock{
-			ParamIndex: boolParams[paramName],
+			ParamIndex: paramIndex,
 			ParamName:  paramName,
+			Field:      field,
 			Selector:   selector,
 			Negated:    negated,
 		})
@@ -115,24 +125,8 @@ func (r *ConditionalLockRegistry) PropagateConditionalLocks(funcs []*ast.FuncDec
 
 			fqn := funcFQN(fn)
 
-			// Build map of bool parameter names to their indices for this function
-			boolParams := make(map[string]int)
-			paramIndex := 0
-			for _, field := range fn.Type.Params.List {
-				if ident, ok := field.Type.(*ast.Ident); ok && ident.Name == "bool" {
-					for _, name := range field.Names {
-						boolParams[name.Name] = paramIndex
-						paramIndex++
-					}
-				} else {
-					paramIndex += len(field.Names)
-					if len(field.Names) == 0 {
-						paramIndex++
-					}
-				}
-			}
-
-			if len(boolParams) == 0 {
+			boolParams, optionParams := r.conditionParams(fn)
+			if len(boolParams) == 0 && len(optionParams) == 0 {
 				continue
 			}
 
@@ -158,26 +152,26 @@ func (r *ConditionalLockRegistry) PropagateConditionalLocks(funcs []*ast.FuncDec
 
 				// Check if any of our bool params are passed to callee's conditional params
 				for _, calleeLock := range calleeLocks {
-					if calleeLock.ParamIndex >= len(call.Args) {
+					// Functional options aren't forwarded
+					if calleeLock.Variadic || calleeLock.ParamIndex >= len(call.Args) {
 						continue
 					}
 
-					arg := call.Args[calleeLock.ParamIndex]
-					argIdent, ok := arg.(*ast.Ident)
+					// Check if one of our params is passed to callee's conditional param
+					paramName, field, ok := r.forwardedParam(call.Args[calleeLock.ParamIndex], calleeLock, boolParams, optionParams)
 					if !ok {
 						continue
 					}
-
-					// Check if this argument is one of our bool parameters
-					ourParamIndex, isBoolParam := boolParams[argIdent.Name]
-					if !isBoolParam {
-						continue
+					ourParamIndex := boolParams[paramName]
+					if field != "" {
+						ourParamIndex = optionParams[paramName]
 					}
 
 					// Check if we already have this conditional lock
 					alreadyHave := false
 					for _, existing := range r.locks[fqn] {
 						if existing.ParamIndex == ourParamIndex &&
+							existing.Field == field &&
 							existing.Selector == calleeLock.Selector &&
 							existing.Negated == calleeLock.Negated {
 							alreadyHave = true
@@ -188,7 +182,8 @@ func (r *ConditionalLockRegistry) PropagateConditionalLocks(funcs []*ast.FuncDec
 					if !alreadyHave {
 						r.locks[fqn] = append(r.locks[fqn], ConditionalLock{
 							ParamIndex: ourParamIndex,
-							ParamName:  argIdent.Name,
+							ParamName:  paramName,
+							Field:      field,
 							Selector:   calleeLock.Selector,
 							Negated:    calleeLock.Negated,
 						})
@@ -202,26 +197,134 @@ func (r *ConditionalLockRegistry) PropagateConditionalLocks(funcs []*ast.FuncDec
 	}
 }
 
-// extractBoolParamCondition checks if the condition is a simple bool parameter check.
-// Returns the parameter name and whether it's negated.
-func extractBoolParamCondition(cond ast.Expr, boolParams map[string]int) (string, bool) {
+// conditionParams returns the indices of the parameters that may guard a lock by name:
+// the bool ones and the option structs (passed by value or by pointer).
+func (r *ConditionalLockRegistry) conditionParams(fn *ast.FuncDecl) (boolParams, optionParams map[string]int) {
+	boolParams = make(map[string]int)
+	optionParams = make(map[string]int)
+	paramIndex := 0
+	for _, field := range fn.Type.Params.List {
+		if len(field.Names) == 0 {
+			paramIndex++ // unnamed parameter
+			continue
+		}
+		ident, isBool := field.Type.(*ast.Ident)
+		isBool = isBool && ident.Name == "bool"
+		isOptions := optionsStruct(r.info.TypeOf(field.Type)) != nil
+		for _, name := range field.Names {
+			if isBool {
+				boolParams[name.Name] = paramIndex
+			} else if isOptions {
+				optionParams[name.Name] = paramIndex
+			}
+			paramIndex++
+		}
+	}
+	return boolParams, optionParams
+}
+
+// extractParamCondition checks if the condition is a simple check of a bool parameter (lock)
+// or of a bool field of an options parameter (opts.NoLock).
+// Returns the parameter name, the field name (if any) and whether it's negated.
+func (r *ConditionalLockRegistry) extractParamCondition(cond ast.Expr, boolParams, optionParams map[string]int) (string, string, bool) {
+	negated := false
+	if unary, ok := cond.(*ast.UnaryExpr); ok && unary.Op.String() == "!" {
+		// if !lock { ... }
+		cond, negated = unary.X, true
+	}
+
 	switch c := cond.(type) {
 	case *ast.Ident:
 		// if lock { ... }
 		if _, ok := boolParams[c.Name]; ok {
-			return c.Name, false
+			return c.Name, "", negated
 		}
-	case *ast.UnaryExpr:
-		// if !lock { ... }
-		if c.Op.String() == "!" {
-			if ident, ok := c.X.(*ast.Ident); ok {
-				if _, ok := boolParams[ident.Name]; ok {
-					return ident.Name, true
-				}
+	case *ast.SelectorExpr:
+		// if opts.NoLock { ... }
+		ident, ok := c.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		if _, ok := optionParams[ident.Name]; !ok {
+			break
+		}
+		if basic, ok := r.info.TypeOf(c).Underlying().(*types.Basic); ok && basic.Kind() == types.Bool {
+			return ident.Name, c.Sel.Name, negated
+		}
+	}
+	return "", "", false
+}
+
+// forwardedParam checks if the argument passed to the conditional param of a callee is one of
+// the caller's params: a bool param, an options param, or a bool param set as the field of
+// an options literal (Options{NoLock: noLock}). Returns the name and the field of the caller's param.
+func (r *ConditionalLockRegistry) forwardedParam(arg ast.Expr, calleeLock ConditionalLock, boolParams, optionParams map[string]int) (string, string, bool) {
+	if calleeLock.Field != "" {
+		if ident, ok := arg.(*ast.Ident); ok {
+			if _, ok := optionParams[ident.Name]; ok {
+				return ident.Name, calleeLock.Field, true
 			}
 		}
+		value, ok := r.optionField(arg, calleeLock.Field)
+		if !ok || value == nil {
+			return "", "", false
+		}
+		arg = value
+	}
+
+	ident, ok := arg.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	if _, ok := boolParams[ident.Name]; !ok {
+		return "", "", false
+	}
+	return ident.Name, "", true
+}
+
+// optionField returns the value of the field in the options struct literal (Options{NoLock: true}
+// or &Options{...}), or nil if the field is omitted (i.e., it's false).
+// Reports false if the argument isn't a struct literal.
+func (r *ConditionalLockRegistry) optionField(arg ast.Expr, field string) (ast.Expr, bool) {
+	arg = ast.Unparen(arg)
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		arg = ast.Unparen(unary.X)
+	}
+	lit, ok := arg.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	st := optionsStruct(r.info.TypeOf(lit))
+	if st == nil {
+		return nil, false
+	}
+
+	for i, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			// Positional fields
+			if i < st.NumFields() && st.Field(i).Name() == field {
+				return elt, true
+			}
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return kv.Value, true
+		}
 	}
-	return "", false
+	return nil, true
+}
+
+// optionsStruct returns the struct type of an options value (possibly a pointer), or nil.
+func optionsStruct(t types.Type) *types.Struct {
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, _ := t.Underlying().(*types.Struct)
+	return st
 }
 
 // findLockInBlock searches for a Lock() call in a block and returns its selector.
@@ -241,7 +344,9 @@ func findLockInBlock(block *ast.BlockStmt) string {
 }
 
 // ShouldSkipLock checks if a transitive lock should be skipped based on the call arguments.
-func (r *ConditionalLockRegistry) ShouldSkipLock(fqn FQN, call *ast.CallExpr, lockSelector string) bool {
+// Bool variables passed as arguments are resolved with the values implied by the path
+// conditions of the call (see pathConditions), if known.
+func (r *ConditionalLockRegistry) ShouldSkipLock(fqn FQN, call *ast.CallExpr, lockSelector string, known map[*types.Var]bool) bool {
 	condLocks := r.locks[fqn]
 	if len(condLocks) == 0 {
 		return false
@@ -252,13 +357,7 @@ func (r *ConditionalLockRegistry) ShouldSkipLock(fqn FQN, call *ast.CallExpr, lo
 			continue
 		}
 
-		// Check if we have enough arguments
-		if cl.ParamIndex >= len(call.Args) {
-			continue
-		}
-
-		arg := call.Args[cl.ParamIndex]
-		boolValue, ok := extractBoolLiteral(arg)
+		boolValue, ok := r.conditionValue(call, cl, known)
 		if !ok {
 			continue // Can't determine value statically
 		}
@@ -279,6 +378,52 @@ func (r *ConditionalLockRegistry) ShouldSkipLock(fqn FQN, call *ast.CallExpr, lo
 	return false
 }
 
+// conditionValue returns the value of the lock condition passed as the argument of the call:
+// a bool literal or a variable of a known value, a field of an options struct literal
+// or the field set by functional options.
+func (r *ConditionalLockRegistry) conditionValue(call *ast.CallExpr, cl ConditionalLock, known map[*types.Var]bool) (bool, bool) {
+	if cl.Variadic {
+		if cl.ParamIndex > len(call.Args) || call.Ellipsis.IsValid() {
+			return false, false
+		}
+		return r.functionalOptionsValue(call.Args[cl.ParamIndex:], cl)
+	}
+	// Check if we have enough arguments
+	if cl.ParamIndex >= len(call.Args) {
+		return false, false
+	}
+
+	arg := call.Args[cl.ParamIndex]
+	if cl.Field == "" {
+		return r.boolValue(arg, known)
+	}
+	value, ok := r.optionField(arg, cl.Field)
+	if !ok {
+		return false, false
+	}
+	if value == nil {
+		return false, true // omitted fields are zero
+	}
+	return r.boolValue(value, known)
+}
+
+// boolValue returns the value of a bool literal, a variable of a known value or their negation.
+func (r *ConditionalLockRegistry) boolValue(expr ast.Expr, known map[*types.Var]bool) (bool, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if v, ok := r.info.Uses[e].(*types.Var); ok {
+			value, isKnown := known[v]
+			return value, isKnown
+		}
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			value, ok := r.boolValue(e.X, known)
+			return !value, ok
+		}
+	}
+	return extractBoolLiteral(expr)
+}
+
 // extractBoolLiteral extracts a boolean literal value from an expression.
 func extractBoolLiteral(expr ast.Expr) (bool, bool) {
 	switch e := expr.(type) {
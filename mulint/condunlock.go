@@ -0,0 +1,108 @@
+package mulint
+
+import (
+	"go/ast"
+)
+
+// checkConditionalUnlocks detects locks acquired conditionally (if lock { s.mu.Lock() })
+// and released unconditionally later in the same block (s.mu.Unlock() or defer s.mu.Unlock()):
+// when the condition doesn't hold, the unlock of an unlocked mutex is a fatal error.
+// Branches releasing the lock themselves or leaving the block, and locks acquired
+// on the else branch, too, are fine.
+func (a *Analyzer) checkConditionalUnlocks() {
+	for _, fn := range a.funcs {
+		if fn.Body == nil || !a.isLive(a.declFQN(fn)) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			for _, list := range stmtLists(n) {
+				for i, stmt := range list {
+					ifStmt, ok := stmt.(*ast.IfStmt)
+					if !ok {
+						continue
+					}
+					for _, lock := range ifStmt.Body.List {
+						subject := subjectForLockCall(lock)
+						if subject == nil || !IsMutexType(subject, a.info) {
+							continue
+						}
+						selector := LockSelector(subject, a.info)
+						if a.releasesOrLeaves(ifStmt.Body, selector) || (ifStmt.Else != nil && a.locksIn(ifStmt.Else, selector)) {
+							continue
+						}
+						if unlock := a.followingUnlock(list[i+1:], selector, unlockMethodFor(lock)); unlock != nil {
+							a.diagnostics = append(a.diagnostics, NewConditionalUnlockError(
+								NewLocation(unlock.Pos()),
+								NewLocation(lock.Pos()),
+								StrExpr(ifStmt.Cond),
+							))
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// releasesOrLeaves checks if the branch releases the mutex (or defers the release)
+// or ends with leaving the block.
+func (a *Analyzer) releasesOrLeaves(body *ast.BlockStmt, selector string) bool {
+	if len(body.List) > 0 {
+		switch body.List[len(body.List)-1].(type) {
+		case *ast.ReturnStmt, *ast.BranchStmt:
+			return true
+		}
+	}
+
+	released := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		for _, subject := range []ast.Expr{subjectForUnlockCall(n), deferredUnlockSubject(n)} {
+			if subject != nil && IsMutexType(subject, a.info) && LockSelector(subject, a.info) == selector {
+				released = true
+			}
+		}
+		return !released
+	})
+	return released
+}
+
+// locksIn checks if the node acquires the mutex.
+func (a *Analyzer) locksIn(node ast.Node, selector string) bool {
+	locked := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if subject := subjectForLockCall(n); subject != nil && IsMutexType(subject, a.info) && LockSelector(subject, a.info) == selector {
+			locked = true
+		}
+		return !locked
+	})
+	return locked
+}
+
+// followingUnlock returns the first statement of the list unconditionally releasing the mutex
+// with the unlock method (or deferring the release), unless the mutex is acquired again before.
+func (a *Analyzer) followingUnlock(list []ast.Stmt, selector, unlock string) ast.Stmt {
+	sameMutex := func(e ast.Expr) bool {
+		return e != nil && IsMutexType(e, a.info) && LockSelector(e, a.info) == selector
+	}
+
+	for _, stmt := range list {
+		if sameMutex(subjectForLockCall(stmt)) {
+			return nil
+		}
+		if sameMutex(SubjectForCall(stmt, []string{unlock})) {
+			return stmt
+		}
+		if deferStmt, ok := stmt.(*ast.DeferStmt); ok && sameMutex(SubjectForCall(deferStmt.Call, []string{unlock})) {
+			return stmt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package mulint
+
+import "regexp"
+
+// Config holds the analyzer options that can be set via command-line flags.
+type Config struct {
+	// HTTPHandlers enables the check for helpers shared between HTTP handlers
+	// that lock the same mutex.
+	HTTPHandlers bool
+
+	// EntryPoints is a comma-separated list of functions to start the analysis from.
+	// When set (or when functions are annotated with //mulint:entrypoint),
+	// functions unreachable from the entry points are not checked.
+	EntryPoints string
+
+	// ExportedOnly restricts the entry points to the exported functions and methods
+	// (see EntryPoints), so unreachable internal helpers are not checked.
+	ExportedOnly bool
+
+	// RunFunc is a regular expression restricting the analysis and its output to the functions
+	// with matching FQNs ("pkg.Queue:Push", also matched as "pkg.Queue.Push"), e.g. `(Queue|Cache)\.`.
+	// Callees outside of the matching functions are still followed.
+	RunFunc string
+	runFunc *regexp.Regexp // compiled when the flag is set
+
+	// Summary enables reporting of lock summaries for exported functions.
+	Summary bool
+
+	// CriticalSections enables reporting, for each lock scope, of the calls to other packages
+	// made while holding the lock (the critical section inventory for reviews).
+	CriticalSections bool
+
+	// LockMetrics enables reporting, for each mutex, of the number of distinct functions acquiring it
+	// and the maximum number of mutexes held when acquiring it (for performance and architecture reviews).
+	LockMetrics bool
+
+	// LockMetricsFanIn is the number of functions acquiring a mutex to flag it as a refactoring
+	// candidate in the lock metrics (see LockMetrics); zero disables flagging.
+	LockMetricsFanIn int
+
+	// ExportedCalls enables the advisory check for exported methods calling
+	// other exported methods of the same type under lock.
+	ExportedCalls bool
+
+	// LockedConvention enables the checks for the "Locked" naming convention:
+	// functions named *Locked must be called with the lock held and must not acquire it.
+	LockedConvention bool
+
+	// WriterStarvation enables the advisory check for read lock scopes containing
+	// loops or blocking calls, which may starve the writers.
+	WriterStarvation bool
+
+	// UseAfterUnlock enables the advisory check for fields guarded by a mutex
+	// accessed after releasing it (and before acquiring it again) in the same function.
+	UseAfterUnlock bool
+
+	// GuardedReturns enables the check for guarded maps, slices and pointers returned under lock,
+	// which callers may access after the lock is released.
+	GuardedReturns bool
+
+	// EscapingClosures enables the advisory check for func literals accessing guarded fields
+	// of captured values that are returned or stored, so they run later without the lock.
+	EscapingClosures bool
+
+	// RedundantMutexes enables the advisory check for mutexes only ever locked while holding
+	// another mutex of the same value, which may make them redundant.
+	RedundantMutexes bool
+
+	// LocalMutexes enables the advisory check for mutex fields locked within a single function only
+	// and guarding no fields accessed elsewhere.
+	LocalMutexes bool
+
+	// HotMethodLocks enables the advisory check for String, Error, Hash and Less methods
+	// acquiring locks: they are commonly called implicitly or on hot paths.
+	HotMethodLocks bool
+
+	// RequireDeferUnlock enables the style rule requiring every lock to be immediately followed
+	// by the deferred unlock of the mutex (except in lock wrappers and //mulint:manual-unlock functions).
+	RequireDeferUnlock bool
+
+	// Strict enables the strict profile for all packages: reporting of the calls under lock
+	// the analysis can't verify, blocking calls and channel operations under lock,
+	// locks without deferred unlocks and calls violating //mulint:requires annotations.
+	Strict bool
+
+	// StrictPackages is a comma-separated list of package patterns to enable the strict profile for,
+	// e.g. "github.com/acme/app/queue,github.com/acme/app/sync/...". Meant for concurrency-critical packages.
+	StrictPackages string
+
+	// MutexTypes is a comma-separated list of types to treat as sync.Mutex/sync.RWMutex,
+	// e.g. "github.com/acme/xsync.Mutex". Useful for internal drop-in replacements of sync.
+	MutexTypes string
+
+	// AssumeSyncCallbacks makes the analysis treat func literals passed to functions as executing
+	// under the caller's locks, unless the callee is known to invoke them asynchronously
+	// (e.g., time.AfterFunc or errgroup.Group.Go). By default, only the known synchronous
+	// callback-takers are assumed to (e.g., singleflight.Group.Do).
+	AssumeSyncCallbacks bool
+
+	// MaybeSyncCallbacks is a comma-separated list of callback registration functions that may invoke
+	// the callbacks synchronously, e.g. "github.com/acme/events.Emitter:On". Callbacks registered
+	// with them under lock are reported if they acquire the same mutex.
+	MaybeSyncCallbacks string
+
+	// Group clusters reentrant lock findings by function and mutex, reporting
+	// one diagnostic per cluster with the other sites as related locations.
+	Group bool
+
+	// Format is the diagnostics output format: FormatFull (default) or FormatShort.
+	Format string
+
+	// Color controls colored output with source snippets: ColorAuto (default),
+	// ColorAlways or ColorNever. In the auto mode, colors are only used by the standalone CLI
+	// printing plain text to a terminal (see SetOutput).
+	Color string
+
+	// ExternSummaryFile is a path to a JSON file declaring the effects of functions
+	// from external packages (see LoadSummaryFile).
+	ExternSummaryFile string
+	externSummaries   ExternSummaries // loaded when the flag is set
+
+	// CallGraph is the algorithm to resolve dynamic calls with: CallGraphStatic (default),
+	// CallGraphCHA, CallGraphRTA or CallGraphVTA.
+	CallGraph string
+}
+
+// Output formats.
+const (
+	// FormatFull includes the source lines of all involved locations in multi-line messages.
+	FormatFull = "full"
+	// FormatShort produces single-line "file:line:col: message [MU001]" diagnostics.
+	FormatShort = "short"
+)
+
+var config Config
+
+func init() {
+	Mulint.Flags.BoolVar(&config.HTTPHandlers, "http-handlers", false,
+		"report helpers called under a lock that is shared with other HTTP handlers")
+	Mulint.Flags.StringVar(&config.EntryPoints, "entrypoints", "",
+		"comma-separated list of entry point functions (e.g. main,Server:Serve); unreachable functions are skipped")
+	Mulint.Flags.BoolVar(&config.ExportedOnly, "exported-only", false,
+		"analyze only functions reachable from the exported functions and methods of the package")
+	Mulint.Flags.Var(&loadingFlag{&config.RunFunc, config.loadRunFunc}, "run-func",
+		"regular expression to restrict the analysis and output to the matching functions (e.g. '(Queue|Cache)\\.')")
+	Mulint.Flags.BoolVar(&config.Summary, "summary", false,
+		"report the lock behavior summary of each exported function")
+	Mulint.Flags.BoolVar(&config.CriticalSections, "critical-sections", false,
+		"report the calls to other packages made under each lock (sorted and deduplicated)")
+	Mulint.Flags.BoolVar(&config.LockMetrics, "lock-metrics", false,
+		"report the number of functions acquiring each mutex and the maximum number of mutexes held along with it")
+	Mulint.Flags.IntVar(&config.LockMetricsFanIn, "lock-metrics-fanin", 10,
+		"the number of functions acquiring a mutex to flag it as a refactoring candidate in the lock metrics (0 disables)")
+	Mulint.Flags.BoolVar(&config.ExportedCalls, "exported-calls", false,
+		"advise against exported methods calling exported methods of the same type under lock")
+	Mulint.Flags.BoolVar(&config.LockedConvention, "locked-convention", false,
+		"report *Locked functions acquiring the lock themselves and their calls made without the lock held")
+	Mulint.Flags.BoolVar(&config.WriterStarvation, "writer-starvation", false,
+		"advise against holding read locks over loops and blocking calls, which may starve writers")
+	Mulint.Flags.BoolVar(&config.UseAfterUnlock, "use-after-unlock", false,
+		"advise against accessing fields guarded by a mutex after releasing it")
+	Mulint.Flags.BoolVar(&config.GuardedReturns, "guarded-returns", false,
+		"report guarded maps, slices and pointers returned under lock instead of their copies")
+	Mulint.Flags.BoolVar(&config.EscapingClosures, "escaping-closures", false,
+		"advise against returning or storing func literals accessing guarded fields without acquiring the lock")
+	Mulint.Flags.BoolVar(&config.RedundantMutexes, "redundant-mutexes", false,
+		"advise against mutexes only ever locked while holding another mutex of the same value")
+	Mulint.Flags.BoolVar(&config.LocalMutexes, "local-mutexes", false,
+		"advise against mutex fields locked within a single function only and guarding no fields accessed elsewhere")
+	Mulint.Flags.BoolVar(&config.HotMethodLocks, "hot-method-locks", false,
+		"advise against String, Error, Hash and Less methods acquiring locks")
+	Mulint.Flags.BoolVar(&config.RequireDeferUnlock, "require-defer-unlock", false,
+		"require every lock to be immediately followed by the deferred unlock, except in lock wrappers and //mulint:manual-unlock functions")
+	Mulint.Flags.BoolVar(&config.Strict, "strict", false,
+		"enable the strict profile: report unverifiable calls, blocking calls and channel operations under lock, locks without deferred unlocks and //mulint:requires violations")
+	Mulint.Flags.StringVar(&config.StrictPackages, "strict-packages", "",
+		"comma-separated list of packages to enable the strict profile for (e.g. github.com/acme/app/sync/...)")
+	Mulint.Flags.StringVar(&config.MutexTypes, "mutex-types", "",
+		"comma-separated list of types to treat as sync mutexes (e.g. github.com/acme/xsync.Mutex)")
+	Mulint.Flags.BoolVar(&config.AssumeSyncCallbacks, "assume-sync-callbacks", false,
+		"analyze func literals passed to functions as running under the caller's locks, unless the callee is known to be asynchronous (e.g. time.AfterFunc)")
+	Mulint.Flags.StringVar(&config.MaybeSyncCallbacks, "maybe-sync-callbacks", "",
+		"comma-separated list of callback registration functions that may invoke the callbacks synchronously (e.g. github.com/acme/events.Emitter:On)")
+	Mulint.Flags.BoolVar(&config.Group, "group", false,
+		"report one diagnostic per function and mutex with all reentrant lock sites")
+	Mulint.Flags.StringVar(&config.Format, "format", FormatFull,
+		"output format: full (multi-line with source lines) or short (single-line with check codes)")
+	Mulint.Flags.StringVar(&config.Color, "color", ColorAuto,
+		"colorize diagnostics and underline the offending calls: auto (on terminals, without -json), always or never")
+	Mulint.Flags.Var(&loadingFlag{&config.ExternSummaryFile, config.loadExternSummaries}, "extern-summaries",
+		"path to a JSON file with the effects (blocks, calls-back, locks-nothing) of external functions")
+	Mulint.Flags.StringVar(&config.CallGraph, "callgraph", CallGraphStatic,
+		"call graph algorithm to resolve interface method calls and function values: static (no resolution), cha, rta or vta")
+}
+
+// loadingFlag is a string flag the value of which is loaded (compiled, read from a file, etc.)
+// when the flag is set: once per process rather than for every analyzed package,
+// with the errors reported once, by the flag parsing.
+type loadingFlag struct {
+	value *string
+	load  func(string) error
+}
+
+func (f *loadingFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f *loadingFlag) Set(value string) error {
+	if err := f.load(value); err != nil {
+		return err
+	}
+	*f.value = value
+	return nil
+}
+
+func (c *Config) loadRunFunc(pattern string) (err error) {
+	c.runFunc, err = CompileRunFunc(pattern)
+	return err
+}
+
+func (c *Config) loadExternSummaries(path string) (err error) {
+	c.externSummaries, err = LoadSummaryFile(path)
+	return err
+}
+
+// isCustomMutexType checks if the type with the given package path and name
+// is configured as a drop-in replacement of sync.Mutex or sync.RWMutex.
+func (c Config) isCustomMutexType(pkgPath, typeName string) bool {
+	for _, item := range splitList(c.MutexTypes) {
+		if item == pkgPath+"."+typeName {
+			return true
+		}
+	}
+	return false
+}
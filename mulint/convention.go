@@ -0,0 +1,226 @@
+package mulint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// lockedSuffix marks functions expecting their callers to hold the lock (e.g., flushLocked).
+const lockedSuffix = "Locked"
+
+// isLockedName checks if the function name follows the "Locked" naming convention.
+// Predicates like isLocked are not considered.
+func isLockedName(name string) bool {
+	prefix, ok := strings.CutSuffix(name, lockedSuffix)
+	return ok && prefix != "" && prefix != "is" && prefix != "Is"
+}
+
+// checkLockedConvention verifies the "Locked" naming convention:
+// functions named *Locked must not acquire the lock they're called with,
+// and they must be called with the lock held.
+func (a *Analyzer) checkLockedConvention() {
+	reported := make(map[token.Pos]bool)
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		requires := FuncDirectives(fn)[requiresDirective]
+		if isLockedName(fn.Name.Name) {
+			a.checkLockedSelfLocks(fn, fqn, requires)
+			// *Locked functions call each other with the lock held by their callers
+			continue
+		}
+		if len(requires) > 0 {
+			continue
+		}
+
+		held := a.heldSelectorsAtCalls(fqn)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			// Func literals may run with a different lock state
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok || reported[call.Pos()] {
+				return true
+			}
+			callee, ok := lockedCallee(call, a.info)
+			if !ok || a.holdsCalleeLock(fn.Body, call, held[call.Pos()]) {
+				return true
+			}
+
+			reported[call.Pos()] = true
+			a.diagnostics = append(a.diagnostics, NewLockedCallError(NewLocation(call.Pos()), callee))
+			return true
+		})
+	}
+}
+
+// checkLockedSelfLocks reports the locks a *Locked function acquires on the mutex it expects to be held:
+// the mutexes declared with //mulint:requires, if any, or the mutexes of the receiver for methods.
+func (a *Analyzer) checkLockedSelfLocks(fn *ast.FuncDecl, fqn FQN, requires []string) {
+	tracker, ok := a.scopes[fqn]
+	if !ok {
+		return
+	}
+
+	receiver := ""
+	if fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+		receiver = fn.Recv.List[0].Names[0].Name
+	}
+
+	for _, scope := range tracker.Scopes() {
+		root, _ := SplitSelector(scope.Selector())
+		switch {
+		case len(requires) > 0:
+			if !requiresLock(requires, scope.Key(fqn)) {
+				continue
+			}
+		case receiver != "":
+			if root != receiver {
+				continue
+			}
+		}
+		a.diagnostics = append(a.diagnostics, NewLockedSelfLockError(NewLocation(scope.Pos()), fqn))
+	}
+}
+
+// heldSelectorsAtCalls returns the selectors of the mutexes held at each call position within a function.
+func (a *Analyzer) heldSelectorsAtCalls(fqn FQN) map[token.Pos][]string {
+	held := make(map[token.Pos][]string)
+	tracker, ok := a.scopes[fqn]
+	if !ok {
+		return held
+	}
+
+	for _, scope := range tracker.Scopes() {
+		for _, node := range scope.Nodes() {
+			inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+				held[call.Pos()] = append(held[call.Pos()], scope.Selector())
+			})
+		}
+	}
+	return held
+}
+
+// lockedCallee returns the function called, if it follows the "Locked" naming convention.
+func lockedCallee(call *ast.CallExpr, info *types.Info) (FQN, bool) {
+	ident := calleeIdent(call)
+	if ident == nil {
+		return "", false
+	}
+	if _, isFunc := info.Uses[ident].(*types.Func); !isFunc || !isLockedName(ident.Name) {
+		return "", false
+	}
+
+	pkg, name, ok := GetCallInfo(call, info)
+	if !ok {
+		return "", false
+	}
+	return FromCallInfo(pkg, name), true
+}
+
+// calleeIdent returns the name of the called function (f() or x.f()), if any.
+func calleeIdent(call *ast.CallExpr) *ast.Ident {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	}
+	return nil
+}
+
+// holdsCalleeLock checks if a lock the *Locked callee may expect is held at the call:
+// a mutex of the receiver for method calls, any mutex otherwise.
+// Calls on new values that haven't escaped yet don't need the lock (see markFreshScopes).
+func (a *Analyzer) holdsCalleeLock(body *ast.BlockStmt, call *ast.CallExpr, held []string) bool {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return len(held) > 0
+	}
+	root := RootSelector(sel)
+	if root == nil {
+		return len(held) > 0
+	}
+	if _, isPkg := a.info.Uses[root].(*types.PkgName); isPkg {
+		return len(held) > 0
+	}
+
+	for _, selector := range held {
+		if heldRoot, _ := SplitSelector(selector); heldRoot == root.Name {
+			return true
+		}
+	}
+
+	if v := freshVar(body, root, call.Pos(), a.info); v != nil && !escapesBefore(body, v, call.Pos(), a.info) {
+		return true
+	}
+	return false
+}
+
+// BuildLockedVariants returns the names of the *Locked variants of the functions declared
+// along with them, e.g. "pkg.Buffer:flush" -> "flushLocked".
+func BuildLockedVariants(funcs []*ast.FuncDecl, info *types.Info) map[FQN]string {
+	declared := make(map[FQN]bool, len(funcs))
+	for _, fn := range funcs {
+		if obj, ok := info.Defs[fn.Name].(*types.Func); ok {
+			declared[FromFunc(obj)] = true
+		}
+	}
+
+	variants := make(map[FQN]string)
+	for _, fn := range funcs {
+		obj, ok := info.Defs[fn.Name].(*types.Func)
+		if !ok || !isLockedName(fn.Name.Name) {
+			continue
+		}
+		base := FQN(strings.TrimSuffix(string(FromFunc(obj)), lockedSuffix))
+		if declared[base] {
+			variants[base] = fn.Name.Name
+		}
+	}
+	return variants
+}
+
+// lockedVariant returns the name of the *Locked variant of the function, if any.
+func (a *Analyzer) lockedVariant(fqn FQN) (string, bool) {
+	if a.lockedVariants == nil {
+		a.lockedVariants = BuildLockedVariants(a.funcs, a.info)
+	}
+	name, ok := a.lockedVariants[fqn]
+	return name, ok
+}
+
+// suggestLockedVariant attaches a fix calling the *Locked variant of the callee
+// (flush() -> flushLocked()) to the reentrant lock error reported for the call.
+func (a *Analyzer) suggestLockedVariant(call *ast.CallExpr, callee FQN) {
+	name, ok := a.lockedVariant(callee)
+	if !ok {
+		return
+	}
+
+	ident := calleeIdent(call)
+	if ident == nil {
+		return
+	}
+
+	for i := len(a.errors) - 1; i >= 0; i-- {
+		if a.errors[i].secondLock.pos != call.Pos() {
+			continue
+		}
+		a.errors[i].fix = &analysis.SuggestedFix{
+			Message:   fmt.Sprintf("Call %s() instead, as the lock is already held", name),
+			TextEdits: []analysis.TextEdit{{Pos: ident.Pos(), End: ident.End(), NewText: []byte(name)}},
+		}
+		return
+	}
+}
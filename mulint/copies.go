@@ -0,0 +1,117 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// checkHeldMutexCopies detects values containing a held mutex copied into another goroutine:
+// passed by value to a go statement (as an argument or a value receiver) or sent on a channel.
+// The copy gets the mutex in the locked state, which is never released in the goroutine.
+func (a *Analyzer) checkHeldMutexCopies() {
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			for _, node := range scope.Nodes() {
+				ast.Inspect(node, func(n ast.Node) bool {
+					var copied []ast.Expr
+					viaChannel := false
+					switch s := n.(type) {
+					case *ast.FuncLit:
+						// Func literals run with a different lock state
+						return false
+					case *ast.GoStmt:
+						copied = a.goStmtCopies(s)
+					case *ast.SendStmt:
+						copied = []ast.Expr{s.Value}
+						viaChannel = true
+					}
+
+					for _, expr := range copied {
+						if reported[expr.Pos()] || !a.copiesHeldMutex(expr, scope) {
+							continue
+						}
+						reported[expr.Pos()] = true
+						a.diagnostics = append(a.diagnostics, NewHeldMutexCopyError(
+							NewLocation(scope.Pos()),
+							NewLocation(expr.Pos()),
+							scope.Selector(),
+							viaChannel,
+						))
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+// goStmtCopies returns the values copied into the goroutine started by the statement:
+// the arguments and the receiver of value receiver methods.
+func (a *Analyzer) goStmtCopies(stmt *ast.GoStmt) []ast.Expr {
+	copied := append([]ast.Expr{}, stmt.Call.Args...)
+
+	sel, ok := ast.Unparen(stmt.Call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return copied
+	}
+	selection, ok := a.info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return copied
+	}
+	sig, ok := selection.Obj().Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return copied
+	}
+	if _, isPtr := sig.Recv().Type().(*types.Pointer); !isPtr {
+		copied = append(copied, sel.X)
+	}
+	return copied
+}
+
+// copiesHeldMutex checks if copying the value of the expression copies the mutex held by the scope:
+// the value contains a mutex (not behind a pointer) and the held mutex belongs to it.
+// Pointers are dereferenced, as *s and value receivers called on s copy the pointed value.
+func (a *Analyzer) copiesHeldMutex(expr ast.Expr, scope *MutexScope) bool {
+	expr = ast.Unparen(expr)
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	t := a.info.TypeOf(expr)
+	if t == nil || !containsMutex(derefType(t), make(map[types.Type]bool)) {
+		return false
+	}
+
+	root := LockSelector(expr, a.info)
+	return scope.Selector() == root || strings.HasPrefix(scope.Selector(), root+".")
+}
+
+// containsMutex checks if the type is a mutex or holds one by value (in struct fields or array elements).
+func containsMutex(t types.Type, seen map[types.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	if _, isPtr := t.(*types.Pointer); !isPtr && isMutexTypeName(t) {
+		return true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := range u.NumFields() {
+			if containsMutex(u.Field(i).Type(), seen) {
+				return true
+			}
+		}
+	case *types.Array:
+		return containsMutex(u.Elem(), seen)
+	}
+	return false
+}
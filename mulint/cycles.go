@@ -0,0 +1,223 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+)
+
+// CallCycles holds the strongly connected components of the call graph
+// that form recursion cycles (mutually recursive or self-recursive functions).
+type CallCycles struct {
+	components [][]FQN
+	index      map[FQN]int // function -> index of its cyclic component
+}
+
+// FindCallCycles computes the strongly connected components of the calls map
+// (using Tarjan's algorithm) and keeps the ones forming cycles.
+func FindCallCycles(calls map[FQN][]FQN) *CallCycles {
+	cycles := &CallCycles{index: make(map[FQN]int)}
+
+	nodes := make([]FQN, 0, len(calls))
+	for fqn := range calls {
+		nodes = append(nodes, fqn)
+	}
+	slices.Sort(nodes)
+
+	counter := 0
+	indices := make(map[FQN]int)
+	lowlinks := make(map[FQN]int)
+	onStack := make(map[FQN]bool)
+	var stack []FQN
+
+	var connect func(fqn FQN)
+	connect = func(fqn FQN) {
+		indices[fqn] = counter
+		lowlinks[fqn] = counter
+		counter++
+		stack = append(stack, fqn)
+		onStack[fqn] = true
+
+		selfLoop := false
+		for _, callee := range calls[fqn] {
+			if callee == fqn {
+				selfLoop = true
+			}
+			if _, visited := indices[callee]; !visited {
+				connect(callee)
+				lowlinks[fqn] = min(lowlinks[fqn], lowlinks[callee])
+			} else if onStack[callee] {
+				lowlinks[fqn] = min(lowlinks[fqn], indices[callee])
+			}
+		}
+
+		if lowlinks[fqn] != indices[fqn] {
+			return
+		}
+
+		var component []FQN
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component = append(component, member)
+			if member == fqn {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop {
+			for _, member := range component {
+				cycles.index[member] = len(cycles.components)
+			}
+			cycles.components = append(cycles.components, component)
+		}
+	}
+
+	for _, fqn := range nodes {
+		if _, visited := indices[fqn]; !visited {
+			connect(fqn)
+		}
+	}
+
+	return cycles
+}
+
+// Component returns the index of the cyclic component containing the function.
+func (c *CallCycles) Component(fqn FQN) (int, bool) {
+	idx, ok := c.index[fqn]
+	return idx, ok
+}
+
+// Path returns the cycle through the component starting and ending at the function,
+// e.g. [a, b, c, a].
+func (c *CallCycles) Path(start FQN, calls map[FQN][]FQN) []FQN {
+	idx, ok := c.index[start]
+	if !ok {
+		return nil
+	}
+
+	// Breadth-first search for the shortest way back to start within the component
+	prev := make(map[FQN]FQN)
+	queue := []FQN{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, callee := range calls[current] {
+			if c.index[callee] != idx {
+				continue
+			}
+			if callee == start {
+				path := []FQN{start}
+				for at := current; at != start; at = prev[at] {
+					path = append(path, at)
+				}
+				path = append(path, start)
+				slices.Reverse(path)
+				return path
+			}
+			if _, seen := prev[callee]; !seen {
+				prev[callee] = current
+				queue = append(queue, callee)
+			}
+		}
+	}
+	return nil
+}
+
+// checkLockedCycles detects calls made under lock that enter a recursion cycle
+// containing a function which acquires the same lock.
+func (a *Analyzer) checkLockedCycles() {
+	cycles := FindCallCycles(a.calls)
+	if len(cycles.components) == 0 {
+		return
+	}
+
+	reported := make(map[token.Pos]map[int]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if a.isCallOnDifferentReceiver(call, scope) {
+						return
+					}
+					pkg, name, ok := GetCallInfo(call, a.info)
+					if !ok {
+						return
+					}
+
+					for _, entry := range a.enteredCycles(FromCallInfo(pkg, name), cycles) {
+						idx, _ := cycles.Component(entry)
+						if reported[scope.Pos()][idx] {
+							continue
+						}
+
+						path := cycles.Path(entry, a.calls)
+						site := a.cycleLockSite(path, scope)
+						if site == nil {
+							continue
+						}
+
+						if reported[scope.Pos()] == nil {
+							reported[scope.Pos()] = make(map[int]bool)
+						}
+						reported[scope.Pos()][idx] = true
+						a.diagnostics = append(a.diagnostics, NewLockedCycleError(
+							NewLocation(scope.Pos()),
+							NewLocation(call.Pos()),
+							path,
+							*site,
+						))
+						return
+					}
+				})
+			}
+		}
+	}
+}
+
+// enteredCycles returns the first reached function of each recursion cycle
+// reachable from fqn (in breadth-first order).
+func (a *Analyzer) enteredCycles(fqn FQN, cycles *CallCycles) []FQN {
+	var entries []FQN
+	entered := make(map[int]bool)
+
+	visited := map[FQN]bool{fqn: true}
+	queue := []FQN{fqn}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if idx, ok := cycles.Component(current); ok && !entered[idx] {
+			entered[idx] = true
+			entries = append(entries, current)
+		}
+		for _, callee := range a.calls[current] {
+			if !visited[callee] {
+				visited[callee] = true
+				queue = append(queue, callee)
+			}
+		}
+	}
+	return entries
+}
+
+// cycleLockSite returns the lock of the scope's mutex acquired by a function of the cycle.
+func (a *Analyzer) cycleLockSite(path []FQN, scope *MutexScope) *LockSite {
+	for _, fqn := range path {
+		tracker, ok := a.scopes[fqn]
+		if !ok {
+			continue
+		}
+		for _, s := range tracker.Scopes() {
+			if s.HasSameSelector(scope) {
+				return &LockSite{FQN: fqn, Pos: s.Pos(), Write: !s.IsRead()}
+			}
+		}
+	}
+	return nil
+}
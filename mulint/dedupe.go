@@ -0,0 +1,103 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// diagnosticKey identifies a reported issue semantically rather than by position,
+// so the same issue reached through different scopes is reported once, while
+// distinct issues sharing a position (e.g., synthesized nodes) are all reported.
+type diagnosticKey struct {
+	check    string   // the check code, e.g. CodeReentrantLock
+	origin   ast.Node // the offending node
+	callee   FQN      // the called function, if known
+	selector string   // the mutex selector
+}
+
+// newDiagnosticKey returns the key of an issue found at the call.
+func newDiagnosticKey(check string, call *ast.CallExpr, info *types.Info, selector string) diagnosticKey {
+	key := diagnosticKey{check: check, origin: callOrigin(call), selector: selector}
+	if pkg, name, ok := GetCallInfo(call, info); ok {
+		key.callee = FromCallInfo(pkg, name)
+	}
+	return key
+}
+
+// callOrigin returns the node identifying the call: the called expression, which is shared by
+// the implicit calls of range-over-func loops synthesized on each walk (see iteratorCall).
+func callOrigin(call *ast.CallExpr) ast.Node {
+	return call.Fun
+}
+
+// closestOrigin returns the position of the lock of the scope's mutex closest to the call among the ones
+// dominating it, starting with the lock of the scope itself. Re-locks following an unlock may not start
+// scopes of their own (e.g., when the unlocks happen in branches, which don't merge their lock states).
+func (a *Analyzer) closestOrigin(fqn FQN, scope *MutexScope, call token.Pos) token.Pos {
+	origin := scope.Pos()
+	decl := a.funcDecl(fqn)
+	if decl == nil || decl.Body == nil {
+		return origin
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		stmt, ok := n.(*ast.ExprStmt)
+		if !ok || stmt.Pos() <= origin || stmt.Pos() >= call {
+			return true
+		}
+		subject := subjectForLockCall(stmt)
+		if subject == nil || !IsMutexType(subject, a.info) || LockSelector(subject, a.info) != scope.Selector() || isReadLockCall(stmt) != scope.IsRead() {
+			return true
+		}
+		if dominatesCall(decl.Body, stmt.Pos(), call) {
+			origin = stmt.Pos()
+		}
+		return true
+	})
+	return origin
+}
+
+// isCloserOrigin checks if the lock at the candidate position is a better origin for the second lock
+// at the call than the current one: locks dominating the call (executed on every path to it) come first,
+// then the nearest lock preceding the call.
+func (a *Analyzer) isCloserOrigin(fqn FQN, candidate, current, call token.Pos) bool {
+	if decl := a.funcDecl(fqn); decl != nil && decl.Body != nil {
+		candidateDominates := dominatesCall(decl.Body, candidate, call)
+		if currentDominates := dominatesCall(decl.Body, current, call); candidateDominates != currentDominates {
+			return candidateDominates
+		}
+	}
+	return current < candidate && candidate < call
+}
+
+// dominatesCall checks if the statement at the lock position is executed before the call on every path:
+// the call is located in a later statement of the same statement list.
+func dominatesCall(body *ast.BlockStmt, lock, call token.Pos) bool {
+	dominates := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if dominates {
+			return false
+		}
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		for _, list := range stmtLists(n) {
+			for i, stmt := range list {
+				if stmt.Pos() != lock {
+					continue
+				}
+				for _, later := range list[i+1:] {
+					if later.Pos() <= call && call < later.End() {
+						dominates = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	return dominates
+}
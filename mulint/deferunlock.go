@@ -0,0 +1,113 @@
+package mulint
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// manualUnlockDirective exempts a function from the deferred unlock requirement,
+// e.g. "//mulint:manual-unlock" for hot paths releasing the lock before slow work.
+const manualUnlockDirective = "manual-unlock"
+
+// checkDeferredUnlocks enforces the style rule requiring every Lock() (or RLock()) call
+// to be immediately followed by the deferred Unlock() (or RUnlock()) of the same mutex.
+// Lock wrappers and functions annotated with //mulint:manual-unlock are exempt.
+func (a *Analyzer) checkDeferredUnlocks() {
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if fn.Body == nil || !a.isLive(fqn) || a.wrappers.IsLockWrapper(fqn) || HasDirective(fn, manualUnlockDirective) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			for _, list := range stmtLists(n) {
+				for i, stmt := range list {
+					subject := subjectForLockCall(stmt)
+					if subject == nil || !IsMutexType(subject, a.info) {
+						continue
+					}
+					if i+1 < len(list) {
+						if deferred := deferredUnlockSubject(list[i+1]); deferred != nil && StrExpr(deferred) == StrExpr(subject) {
+							continue
+						}
+					}
+
+					unlock := unlockMethodFor(stmt)
+					e := NewDeferUnlockError(NewLocation(stmt.Pos()), StrExpr(subject), unlock)
+					if block, ok := n.(*ast.BlockStmt); ok && block == fn.Body {
+						e.fix = a.deferUnlockFix(fn.Body, i, StrExpr(subject), unlock)
+					}
+					a.diagnostics = append(a.diagnostics, e)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// stmtLists returns the statement lists of the node (blocks and case bodies).
+func stmtLists(n ast.Node) [][]ast.Stmt {
+	switch s := n.(type) {
+	case *ast.BlockStmt:
+		return [][]ast.Stmt{s.List}
+	case *ast.CaseClause:
+		return [][]ast.Stmt{s.Body}
+	case *ast.CommClause:
+		return [][]ast.Stmt{s.Body}
+	}
+	return nil
+}
+
+// unlockMethodFor returns the unlock method matching the lock call (RLock -> RUnlock, Lock -> Unlock).
+func unlockMethodFor(lockStmt ast.Stmt) string {
+	if isReadLockCall(lockStmt) {
+		return "RUnlock"
+	}
+	return "Unlock"
+}
+
+// deferUnlockFix returns the fix replacing the unlock at the end of the function with the deferred one
+// right after the lock. Only the trivial case is fixed: the lock is a top-level statement of the body
+// and the only unlock of the mutex is the last statement.
+func (a *Analyzer) deferUnlockFix(body *ast.BlockStmt, lockIdx int, subject, unlock string) *analysis.SuggestedFix {
+	last := len(body.List) - 1
+	if lockIdx+1 >= last {
+		return nil
+	}
+	final := body.List[last]
+	if s := SubjectForCall(final, []string{unlock}); s == nil || StrExpr(s) != subject {
+		return nil
+	}
+
+	unlocks := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		if s := subjectForUnlockCall(n); s != nil && StrExpr(s) == subject {
+			unlocks++
+		}
+		return true
+	})
+	if unlocks != 1 {
+		return nil
+	}
+
+	lockStmt := body.List[lockIdx]
+	tokFile := a.pass.Fset.File(lockStmt.Pos())
+	line := tokFile.Line(final.Pos())
+	if line >= tokFile.LineCount() || tokFile.Line(body.List[last-1].End()) == line {
+		return nil
+	}
+	source := sourceLine(a.pass.Fset.Position(lockStmt.Pos()))
+	indent := source[:len(source)-len(strings.TrimLeft(source, " \t"))]
+
+	deferred := fmt.Sprintf("defer %s.%s()", subject, unlock)
+	return &analysis.SuggestedFix{
+		Message: fmt.Sprintf("Replace the final unlock with %s", deferred),
+		TextEdits: []analysis.TextEdit{
+			{Pos: lockStmt.End(), End: lockStmt.End(), NewText: []byte("\n" + indent + deferred)},
+			{Pos: tokFile.LineStart(line), End: tokFile.LineStart(line + 1)},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// checkDoubleCheckedLocking detects the double-checked locking pattern:
+//
+//	if s.x == nil {
+//	    s.mu.Lock()
+//	    if s.x == nil {
+//	        s.x = ...
+//	    }
+//	    s.mu.Unlock()
+//	}
+//
+// The outer check reads a field guarded by the mutex without holding it, which is a data race.
+func (a *Analyzer) checkDoubleCheckedLocking() {
+	guards := a.guardIndex()
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			outer, ok := n.(*ast.IfStmt)
+			if !ok || outer.Body == nil {
+				return true
+			}
+
+			mutex := ""
+			for _, stmt := range outer.Body.List {
+				if mutex == "" {
+					if subject := subjectForLockCall(stmt); subject != nil && IsMutexType(subject, a.info) {
+						mutex = typedMutexKey(subject, a.info)
+					}
+					continue
+				}
+
+				inner, ok := stmt.(*ast.IfStmt)
+				if !ok {
+					continue
+				}
+				field := rechecked(outer.Cond, inner.Cond, a.info)
+				if field == "" || !guards.IsGuardedBy(field, mutex) || a.isHeldAt(fqn, outer.Cond, mutex) {
+					continue
+				}
+
+				a.diagnostics = append(a.diagnostics, NewDoubleCheckedLockError(NewLocation(outer.Cond.Pos()), field, mutex))
+				break
+			}
+			return true
+		})
+	}
+}
+
+// rechecked returns the first field read in both conditions.
+func rechecked(outer, inner ast.Expr, info *types.Info) string {
+	innerFields := fieldsIn(inner, info)
+	for _, field := range fieldsIn(outer, info) {
+		for _, f := range innerFields {
+			if f == field {
+				return field
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// checkCopiedElementLocks detects locks acquired on mutexes of local variables holding copies
+// of map values or slice (array) elements of non-pointer types:
+//
+//	e := s.registry[key] // registry is map[string]entry
+//	e.mu.Lock()          // locks the mutex of the copy
+//
+// The copy has its own mutex, so the lock doesn't protect the stored value.
+func (a *Analyzer) checkCopiedElementLocks() {
+	for _, fn := range a.funcs {
+		if !a.isLive(a.declFQN(fn)) {
+			continue
+		}
+
+		copies := a.elementCopies(fn.Body)
+		if len(copies) == 0 {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			subject := SubjectForCall(call, lockMethods)
+			if subject == nil || !IsMutexType(subject, a.info) {
+				return true
+			}
+			if _, isPtr := a.info.TypeOf(subject).(*types.Pointer); isPtr {
+				return true
+			}
+			root := valueRoot(subject, a.info)
+			if root == nil {
+				return true
+			}
+			v, ok := a.info.Uses[root].(*types.Var)
+			if !ok {
+				return true
+			}
+			if source, ok := copies[v]; ok {
+				a.diagnostics = append(a.diagnostics, NewCopiedElementLockError(
+					NewLocation(call.Pos()),
+					NewLocation(source.pos),
+					StrExpr(subject),
+					source.fromMap,
+				))
+			}
+			return true
+		})
+	}
+}
+
+// elementCopy is a local variable initialized with a copy of a map value or a slice element.
+type elementCopy struct {
+	pos     token.Pos // the statement copying the value
+	fromMap bool
+}
+
+// elementCopies returns the local variables of the body initialized with copies of map values
+// or slice (array) elements containing mutexes: v := m[k], v, ok := m[k] and for _, v := range s.
+func (a *Analyzer) elementCopies(body *ast.BlockStmt) map[*types.Var]elementCopy {
+	copies := make(map[*types.Var]elementCopy)
+	add := func(ident ast.Expr, container ast.Expr, pos token.Pos) {
+		id, ok := ident.(*ast.Ident)
+		if !ok {
+			return
+		}
+		v, ok := a.info.Defs[id].(*types.Var)
+		if !ok || !containsMutex(v.Type(), make(map[types.Type]bool)) {
+			return
+		}
+		if _, isMap := a.info.TypeOf(container).Underlying().(*types.Map); isMap {
+			copies[v] = elementCopy{pos: pos, fromMap: true}
+		} else {
+			copies[v] = elementCopy{pos: pos}
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE || len(s.Rhs) != 1 {
+				return true
+			}
+			if index, ok := ast.Unparen(s.Rhs[0]).(*ast.IndexExpr); ok && a.isElementContainer(index.X) {
+				add(s.Lhs[0], index.X, s.Pos())
+			}
+		case *ast.RangeStmt:
+			if s.Tok == token.DEFINE && s.Value != nil && a.isElementContainer(s.X) {
+				add(s.Value, s.X, s.Pos())
+			}
+		}
+		return true
+	})
+	return copies
+}
+
+// isElementContainer checks if the expression is a map, a slice or an array (or a pointer to an array).
+func (a *Analyzer) isElementContainer(expr ast.Expr) bool {
+	t := a.info.TypeOf(expr)
+	if t == nil {
+		return false
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Map, *types.Slice, *types.Array:
+		return true
+	case *types.Pointer:
+		_, isArray := u.Elem().Underlying().(*types.Array)
+		return isArray
+	}
+	return false
+}
+
+// valueRoot returns the variable the selector is rooted at, if the selected value
+// is stored in the variable itself (not behind a pointer): v.a.mu but not v.ptr.mu.
+func valueRoot(expr ast.Expr, info *types.Info) *ast.Ident {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			if _, isPtr := info.TypeOf(e.X).Underlying().(*types.Pointer); isPtr {
+				return nil
+			}
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
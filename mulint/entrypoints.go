@@ -0,0 +1,136 @@
+package mulint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// entrypointDirective marks a function as an analysis entry point.
+const entrypointDirective = "entrypoint"
+
+// EntryPoints returns the functions declared as entry points, either via the
+// -entrypoints flag or the //mulint:entrypoint annotation, and the exported surface
+// of the package with -exported-only.
+func (a *Analyzer) EntryPoints() []FQN {
+	patterns := splitList(a.config.EntryPoints)
+
+	var entries []FQN
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if fqn == "" {
+			continue
+		}
+		if HasDirective(fn, entrypointDirective) || matchesAny(fqn, patterns) || (a.config.ExportedOnly && a.isSurface(fn)) {
+			entries = append(entries, fqn)
+		}
+	}
+	return entries
+}
+
+// isSurface checks if the function can be called from outside of the package: exported functions
+// and methods (of unexported types, too, as they may implement interfaces), init functions
+// and the main function of main packages.
+func (a *Analyzer) isSurface(fn *ast.FuncDecl) bool {
+	name := fn.Name.Name
+	if fn.Recv == nil && (name == "init" || (name == "main" && a.pass.Pkg.Name() == "main")) {
+		return true
+	}
+	return fn.Name.IsExported()
+}
+
+// seedEntryPoints computes the set of functions reachable from the declared entry points.
+// When no entry points are declared, all functions are considered live.
+func (a *Analyzer) seedEntryPoints() {
+	entries := a.EntryPoints()
+	if len(entries) == 0 {
+		return
+	}
+
+	a.live = make(map[FQN]bool)
+	for _, entry := range entries {
+		for fqn := range a.reachableFrom(entry) {
+			a.live[fqn] = true
+		}
+	}
+}
+
+// isLive checks if a function is reachable from the entry points
+// and matches the -run-func pattern, if any.
+func (a *Analyzer) isLive(fqn FQN) bool {
+	return (a.live == nil || a.live[fqn]) && matchesRunFunc(a.runFunc, fqn)
+}
+
+// RestrictTo restricts the analysis to the functions matching the pattern (see -run-func).
+func (a *Analyzer) RestrictTo(runFunc *regexp.Regexp) {
+	a.runFunc = runFunc
+}
+
+// CompileRunFunc compiles the -run-func pattern, returning nil for an empty one.
+func CompileRunFunc(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -run-func pattern: %w", err)
+	}
+	return re, nil
+}
+
+// matchesRunFunc checks if the function matches the pattern (nil matches all), either by
+// its fully qualified name ("pkg.Queue:Push") or the dotted form of it ("pkg.Queue.Push").
+func matchesRunFunc(runFunc *regexp.Regexp, fqn FQN) bool {
+	if runFunc == nil {
+		return true
+	}
+	return runFunc.MatchString(string(fqn)) || runFunc.MatchString(strings.ReplaceAll(string(fqn), ":", "."))
+}
+
+// restrictReports returns a copy of the pass dropping the diagnostics
+// located outside of the functions matching the pattern.
+func restrictReports(pass *analysis.Pass, runFunc *regexp.Regexp) *analysis.Pass {
+	restricted := *pass
+	restricted.Report = func(d analysis.Diagnostic) {
+		if decl, fqn := enclosingFunc(pass, d.Pos); decl == nil || !matchesRunFunc(runFunc, fqn) {
+			return
+		}
+		pass.Report(d)
+	}
+	return &restricted
+}
+
+// declFQN returns the fully qualified name of a function declaration.
+func (a *Analyzer) declFQN(fn *ast.FuncDecl) FQN {
+	obj, ok := a.info.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return ""
+	}
+	return FromFunc(obj)
+}
+
+// matchesAny checks if the function matches one of the patterns,
+// either by its fully qualified name or its short name (e.g. "Server:Serve").
+func matchesAny(fqn FQN, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == string(fqn) || pattern == fqn.ShortName() {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
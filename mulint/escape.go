@@ -0,0 +1,210 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// markFreshScopes marks the scopes locking a mutex of a value allocated in the function
+// that hasn't escaped it before the lock, e.g., constructors initializing a new value under lock:
+//
+//	r := &Registry{}
+//	r.mu.Lock() // nobody else can hold r.mu yet
+//
+// Only locks and allocations made by top-level statements of the body are considered,
+// so the lock can't be reached again (e.g., in a loop) after the value escapes.
+func markFreshScopes(body *ast.BlockStmt, scopes []*MutexScope, info *types.Info) {
+	for _, scope := range scopes {
+		if scope.Wrapper() != nil {
+			continue
+		}
+		for _, stmt := range body.List {
+			if stmt.Pos() != scope.Pos() {
+				continue
+			}
+			if v := freshLockedVar(body, stmt, info); v != nil && !escapesBefore(body, v, stmt.Pos(), info) {
+				scope.markFresh()
+			}
+			break
+		}
+	}
+}
+
+// freshLockedVar returns the local variable whose mutex the statement locks,
+// if the variable is initialized with a new value by a preceding top-level statement.
+func freshLockedVar(body *ast.BlockStmt, lockStmt ast.Stmt, info *types.Info) *types.Var {
+	subject := subjectForLockCall(lockStmt)
+	sel, ok := subject.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	root, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	return freshVar(body, root, lockStmt.Pos(), info)
+}
+
+// freshVar returns the local variable referenced by the identifier, if the variable
+// is initialized with a new value by a top-level statement preceding the position.
+func freshVar(body *ast.BlockStmt, ident *ast.Ident, pos token.Pos, info *types.Info) *types.Var {
+	v, ok := info.ObjectOf(ident).(*types.Var)
+	if !ok || v.Pos() < body.Pos() || v.Pos() >= pos {
+		return nil
+	}
+
+	for _, stmt := range body.List {
+		if stmt.Pos() >= pos {
+			break
+		}
+		if declaresFresh(stmt, v, info) {
+			return v
+		}
+	}
+	return nil
+}
+
+// declaresFresh checks if the statement declares the variable with a new value
+// (v := &T{}, v := T{}, v := new(T) or var v T).
+func declaresFresh(stmt ast.Stmt, v *types.Var, info *types.Info) bool {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.DEFINE || len(s.Lhs) != len(s.Rhs) {
+			return false
+		}
+		for i, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && info.Defs[ident] == v {
+				return isAllocation(s.Rhs[i], info)
+			}
+		}
+	case *ast.DeclStmt:
+		decl, ok := s.Decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			return false
+		}
+		for _, spec := range decl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if info.Defs[name] != v {
+					continue
+				}
+				if len(vs.Values) == 0 {
+					return true
+				}
+				return i < len(vs.Values) && isAllocation(vs.Values[i], info)
+			}
+		}
+	}
+	return false
+}
+
+// isAllocation checks if the expression creates a new value (&T{}, T{} or new(T)).
+func isAllocation(expr ast.Expr, info *types.Info) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CompositeLit:
+		return true
+	case *ast.UnaryExpr:
+		_, isLit := ast.Unparen(e.X).(*ast.CompositeLit)
+		return e.Op == token.AND && isLit
+	case *ast.CallExpr:
+		ident, ok := ast.Unparen(e.Fun).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		_, isBuiltin := info.Uses[ident].(*types.Builtin)
+		return isBuiltin && ident.Name == "new"
+	}
+	return false
+}
+
+// escapesBefore checks if the variable may escape the function before the position:
+// any use other than reading or writing its fields counts, as do uses in func literals
+// (which may run concurrently) and taking the address of its fields.
+func escapesBefore(body *ast.BlockStmt, v *types.Var, pos token.Pos, info *types.Info) bool {
+	// Field accesses (v.field) don't expose the value itself
+	fieldAccess := make(map[*ast.Ident]bool)
+	escapes := false
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		if escapes || n == nil || n.Pos() >= pos {
+			return false
+		}
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			ast.Inspect(e.Body, func(inner ast.Node) bool {
+				if ident, ok := inner.(*ast.Ident); ok && info.Uses[ident] == v {
+					escapes = true
+				}
+				return !escapes
+			})
+			return false
+		case *ast.UnaryExpr:
+			if sel, ok := ast.Unparen(e.X).(*ast.SelectorExpr); ok && e.Op == token.AND && isVarRoot(sel, v, info) {
+				escapes = true
+				return false
+			}
+		case *ast.SelectorExpr:
+			if ident, ok := e.X.(*ast.Ident); ok {
+				if s, ok := info.Selections[e]; ok && s.Kind() == types.FieldVal {
+					fieldAccess[ident] = true
+				}
+			}
+		case *ast.Ident:
+			if info.Uses[e] == v && !fieldAccess[e] {
+				escapes = true
+			}
+		}
+		return true
+	}
+	ast.Inspect(body, visit)
+	return escapes
+}
+
+// isVarRoot checks if the selector is rooted at the variable (v.a.b).
+func isVarRoot(sel *ast.SelectorExpr, v *types.Var, info *types.Info) bool {
+	root := RootSelector(sel)
+	return root != nil && info.Uses[root] == v
+}
+
+// handsOffFresh checks if the return statement at returnPos hands off the new value locked
+// by the top-level statement at lockPos (see markFreshScopes) to the caller, e.g.
+//
+//	c := &conn{}
+//	c.mu.Lock()
+//	return c // the caller gets the locked connection
+func handsOffFresh(body *ast.BlockStmt, lockPos, returnPos token.Pos, info *types.Info) bool {
+	var v *types.Var
+	for _, stmt := range body.List {
+		if stmt.Pos() == lockPos {
+			v = freshLockedVar(body, stmt, info)
+			break
+		}
+	}
+	if v == nil {
+		return false
+	}
+
+	handsOff := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if s.Pos() != returnPos {
+				return true
+			}
+			for _, result := range s.Results {
+				if u, ok := ast.Unparen(result).(*ast.UnaryExpr); ok && u.Op == token.AND {
+					result = u.X
+				}
+				if ident, ok := ast.Unparen(result).(*ast.Ident); ok && info.Uses[ident] == v {
+					handsOff = true
+				}
+			}
+		}
+		return !handsOff
+	})
+	return handsOff
+}
@@ -0,0 +1,114 @@
+package mulint
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"maps"
+	"os"
+	"slices"
+)
+
+// summaryDirective declares the effects of a function without a body (an assembly stub,
+// a linkname'd or cgo function), which can't be derived from its source:
+//
+//	//mulint:summary blocks
+//	func semacquire(addr *uint32)
+const summaryDirective = "summary"
+
+// Effects of functions that can't be analyzed.
+const (
+	// EffectLocksNothing declares that the function acquires no locks (the default assumption).
+	EffectLocksNothing = "locks-nothing"
+	// EffectBlocks declares that the function may block, e.g., on a syscall or I/O.
+	EffectBlocks = "blocks"
+	// EffectCallsBack declares that the function synchronously calls its function arguments.
+	EffectCallsBack = "calls-back"
+)
+
+// ExternSummaries maps functions that can't be analyzed to their declared effects.
+type ExternSummaries map[FQN][]string
+
+// Has checks if the function is declared to have the effect.
+func (s ExternSummaries) Has(fqn FQN, effect string) bool {
+	return slices.Contains(s[fqn], effect)
+}
+
+// knownEffects are the effects that can be declared for functions that can't be analyzed.
+var knownEffects = []string{EffectLocksNothing, EffectBlocks, EffectCallsBack}
+
+// LoadSummaryFile loads the effects of external (not analyzed) functions from a JSON file
+// mapping function FQNs to their effects:
+//
+//	{
+//	  "github.com/redis/go-redis/v9.Client:Do": ["blocks"],
+//	  "github.com/acme/collections.Each": ["calls-back"]
+//	}
+//
+// The file is loaded once, when the -extern-summaries flag is set, and shared between
+// the analyzed packages.
+func LoadSummaryFile(path string) (ExternSummaries, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summaries: %w", err)
+	}
+
+	var summaries ExternSummaries
+	if err := json.Unmarshal(data, &summaries); err != nil {
+		return nil, fmt.Errorf("failed to parse summaries %s: %w", path, err)
+	}
+	for fqn, effects := range summaries {
+		for _, effect := range effects {
+			if !slices.Contains(knownEffects, effect) {
+				return nil, fmt.Errorf("unknown effect %q of %s in %s", effect, fqn, path)
+			}
+		}
+	}
+
+	return summaries, nil
+}
+
+// CollectExternSummaries collects the summary directives of the functions without a body.
+func CollectExternSummaries(files []*ast.File, info *types.Info) ExternSummaries {
+	summaries := make(ExternSummaries)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body != nil {
+				continue
+			}
+			effects, ok := FuncDirectives(fn)[summaryDirective]
+			if !ok {
+				continue
+			}
+			if obj, ok := info.Defs[fn.Name].(*types.Func); ok {
+				summaries[FromFunc(obj)] = effects
+			}
+		}
+	}
+	return summaries
+}
+
+// externs returns the summaries of the functions that can't be analyzed (built lazily).
+func (a *Analyzer) externs() ExternSummaries {
+	if a.externSummaries == nil {
+		a.externSummaries = CollectExternSummaries(a.pass.Files, a.info)
+	}
+	return a.externSummaries
+}
+
+// AddExternSummaries adds the effects of external functions, e.g., loaded from a summary file.
+func (a *Analyzer) AddExternSummaries(summaries ExternSummaries) {
+	maps.Copy(a.externs(), summaries)
+}
+
+// callsBack checks if the call is made to a function declared to call its function arguments.
+func (a *Analyzer) callsBack(call *ast.CallExpr) bool {
+	pkg, name, ok := GetCallInfo(call, a.info)
+	return ok && a.externs().Has(FromCallInfo(pkg, name), EffectCallsBack)
+}
@@ -0,0 +1,362 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// boolFlags returns the local bool variables of the function body worth tracking along paths
+// (see flagValues): the ones tested by if conditions, e.g. unlocked in
+//
+//	unlocked := false
+//	if cached {
+//	    s.mu.Unlock()
+//	    unlocked = true
+//	}
+//	...
+//	if !unlocked {
+//	    s.mu.Unlock()
+//	}
+//
+// Variables having their address taken or assigned within func literals aren't tracked.
+func boolFlags(body *ast.BlockStmt, info *types.Info) map[*types.Var]bool {
+	flags := make(map[*types.Var]bool)
+	isLocal := func(v *types.Var) bool {
+		return body.Pos() <= v.Pos() && v.Pos() < body.End() && isBoolType(v.Type())
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if ifStmt, ok := n.(*ast.IfStmt); ok {
+			for v := range condVars(ifStmt.Cond, info) {
+				if isLocal(v) {
+					flags[v] = true
+				}
+			}
+		}
+		return true
+	})
+	if len(flags) == 0 {
+		return nil
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.UnaryExpr:
+			if e.Op == token.AND {
+				if ident, ok := ast.Unparen(e.X).(*ast.Ident); ok {
+					delete(flags, varOf(ident, info))
+				}
+			}
+		case *ast.FuncLit:
+			for v := range reassignedVars(e.Body, info) {
+				delete(flags, v)
+			}
+		}
+		return true
+	})
+	return flags
+}
+
+// condVars returns the bool variables the condition is built of (with !, && and ||).
+func condVars(cond ast.Expr, info *types.Info) map[*types.Var]bool {
+	vars := make(map[*types.Var]bool)
+	var collect func(e ast.Expr)
+	collect = func(e ast.Expr) {
+		switch e := ast.Unparen(e).(type) {
+		case *ast.Ident:
+			if v := varOf(e, info); v != nil {
+				vars[v] = true
+			}
+		case *ast.UnaryExpr:
+			if e.Op == token.NOT {
+				collect(e.X)
+			}
+		case *ast.BinaryExpr:
+			if e.Op == token.LAND || e.Op == token.LOR {
+				collect(e.X)
+				collect(e.Y)
+			}
+		}
+	}
+	collect(cond)
+	return vars
+}
+
+// testsFlags checks if the condition tests any of the tracked flags.
+func testsFlags(cond ast.Expr, flags map[*types.Var]bool, info *types.Info) bool {
+	for v := range condVars(cond, info) {
+		if flags[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// varOf returns the variable the identifier refers to (or declares), if any.
+func varOf(ident *ast.Ident, info *types.Info) *types.Var {
+	v, _ := info.ObjectOf(ident).(*types.Var)
+	return v
+}
+
+// flagValues are the known values of the tracked bool variables (see boolFlags) on a path.
+type flagValues map[*types.Var]bool
+
+func (f flagValues) clone() flagValues {
+	clone := make(flagValues, len(f))
+	for v, value := range f {
+		clone[v] = value
+	}
+	return clone
+}
+
+// eval evaluates the condition with the known values: constants, tracked variables,
+// their negations, conjunctions and disjunctions. It also reports whether the value is known.
+func (f flagValues) eval(cond ast.Expr, info *types.Info) (bool, bool) {
+	if tv, ok := info.Types[cond]; ok && tv.Value != nil && tv.Value.Kind() == constant.Bool {
+		return constant.BoolVal(tv.Value), true
+	}
+	switch e := ast.Unparen(cond).(type) {
+	case *ast.Ident:
+		if v := varOf(e, info); v != nil {
+			value, ok := f[v]
+			return value, ok
+		}
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			value, ok := f.eval(e.X, info)
+			return !value, ok
+		}
+	case *ast.BinaryExpr:
+		if e.Op != token.LAND && e.Op != token.LOR {
+			break
+		}
+		// The short-circuit value of the operator: false for && and true for ||
+		short := e.Op == token.LOR
+		x, xok := f.eval(e.X, info)
+		y, yok := f.eval(e.Y, info)
+		switch {
+		case (xok && x == short) || (yok && y == short):
+			return short, true
+		case xok && yok:
+			return !short, true
+		}
+	}
+	return false, false
+}
+
+// assume records the values of the tracked variables implied by the condition having the value.
+// It returns the variables with recorded values.
+func (f flagValues) assume(cond ast.Expr, value bool, flags map[*types.Var]bool, info *types.Info) []*types.Var {
+	implied := make(map[*types.Var]bool)
+	impliedValues(cond, value, info, implied)
+
+	var assumed []*types.Var
+	for v, value := range implied {
+		if flags[v] {
+			f[v] = value
+			assumed = append(assumed, v)
+		}
+	}
+	return assumed
+}
+
+// assign updates the values with the assignments made by the node (an assignment, a declaration,
+// a value spec or a range statement). It returns the assigned tracked variables.
+func (f flagValues) assign(node ast.Node, flags map[*types.Var]bool, info *types.Info) []*types.Var {
+	var lhs []*ast.Ident
+	var rhs []ast.Expr
+	zero := false // declarations without values
+	switch s := node.(type) {
+	case *ast.AssignStmt:
+		for _, expr := range s.Lhs {
+			ident, _ := ast.Unparen(expr).(*ast.Ident)
+			lhs = append(lhs, ident)
+		}
+		if s.Tok == token.ASSIGN || s.Tok == token.DEFINE {
+			rhs = s.Rhs
+		}
+	case *ast.DeclStmt:
+		var assigned []*types.Var
+		if decl, ok := s.Decl.(*ast.GenDecl); ok && decl.Tok == token.VAR {
+			for _, spec := range decl.Specs {
+				assigned = append(assigned, f.assign(spec, flags, info)...)
+			}
+		}
+		return assigned
+	case *ast.ValueSpec:
+		lhs = s.Names
+		rhs = s.Values
+		zero = len(s.Values) == 0
+	case *ast.RangeStmt:
+		if s.Tok == token.ASSIGN {
+			for _, expr := range []ast.Expr{s.Key, s.Value} {
+				ident, _ := ast.Unparen(expr).(*ast.Ident)
+				lhs = append(lhs, ident)
+			}
+		}
+	default:
+		return nil
+	}
+
+	// The right-hand sides are evaluated before any assignment
+	values := make([]*bool, len(lhs))
+	for i := range lhs {
+		switch {
+		case zero:
+			values[i] = new(bool)
+		case len(rhs) == len(lhs):
+			if value, ok := f.eval(rhs[i], info); ok {
+				values[i] = &value
+			}
+		}
+	}
+
+	var assigned []*types.Var
+	for i, ident := range lhs {
+		if ident == nil {
+			continue
+		}
+		v := varOf(ident, info)
+		if !flags[v] {
+			continue
+		}
+		if values[i] != nil {
+			f[v] = *values[i]
+		} else {
+			delete(f, v)
+		}
+		assigned = append(assigned, v)
+	}
+	return assigned
+}
+
+// key renders the values of the variables in the order as a string (e.g., "1-0" for true, unknown and false).
+func (f flagValues) key(order []*types.Var) string {
+	var b strings.Builder
+	for _, v := range order {
+		value, ok := f[v]
+		switch {
+		case !ok:
+			b.WriteByte('-')
+		case value:
+			b.WriteByte('1')
+		default:
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// sortedFlags returns the variables ordered by their declarations.
+func sortedFlags(flags map[*types.Var]bool) []*types.Var {
+	order := make([]*types.Var, 0, len(flags))
+	for v := range flags {
+		order = append(order, v)
+	}
+	slices.SortFunc(order, func(a, b *types.Var) int { return int(a.Pos() - b.Pos()) })
+	return order
+}
+
+// assignedFlags returns the tracked variables assigned within the node (outside func literals).
+func assignedFlags(node ast.Node, flags map[*types.Var]bool, info *types.Info) []*types.Var {
+	if len(flags) == 0 {
+		return nil
+	}
+	var assigned []*types.Var
+	scratch := make(flagValues)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		assigned = append(assigned, scratch.assign(n, flags, info)...)
+		return true
+	})
+	return assigned
+}
+
+// guardedDeferUnlock returns the subject of an unlock deferred under a condition
+// and the condition itself, e.g. s.mu and !unlocked for
+//
+//	defer func() {
+//	    if !unlocked {
+//	        s.mu.Unlock()
+//	    }
+//	}()
+//
+// Unlocks following early returns are deferred under the negated conditions of the returns,
+// e.g. s.mu and !(handedOff) for
+//
+//	defer func() {
+//	    if handedOff {
+//	        return
+//	    }
+//	    s.mu.Unlock()
+//	}()
+//
+// The condition is nil if the unlock follows other statements which may return,
+// as it isn't known then; unknown conditions are considered to hold.
+func guardedDeferUnlock(node ast.Node) (ast.Expr, ast.Expr) {
+	deferStmt, ok := node.(*ast.DeferStmt)
+	if !ok {
+		return nil, nil
+	}
+	funcLit, ok := deferStmt.Call.Fun.(*ast.FuncLit)
+	if !ok || funcLit.Body == nil {
+		return nil, nil
+	}
+
+	var skips []ast.Expr // conditions of the preceding early returns
+	known := true
+	guard := func(cond ast.Expr) ast.Expr {
+		if !known {
+			return nil
+		}
+		for _, skip := range skips {
+			negated := &ast.UnaryExpr{OpPos: skip.Pos(), Op: token.NOT, X: &ast.ParenExpr{Lparen: skip.Pos(), X: skip, Rparen: skip.End()}}
+			if cond == nil {
+				cond = negated
+			} else {
+				cond = &ast.BinaryExpr{X: cond, OpPos: skip.Pos(), Op: token.LAND, Y: negated}
+			}
+		}
+		return cond
+	}
+	for _, stmt := range funcLit.Body.List {
+		if subject := SubjectForCall(stmt, unlockMethods); subject != nil {
+			if len(skips) == 0 && known {
+				return nil, nil // deferred unconditionally (see subjectForDeferUnlockCall)
+			}
+			return subject, guard(nil)
+		}
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok || ifStmt.Init != nil || ifStmt.Else != nil {
+			known = known && !mayReturn(stmt)
+			continue
+		}
+		for _, inner := range ifStmt.Body.List {
+			if subject := SubjectForCall(inner, unlockMethods); subject != nil {
+				return subject, guard(ifStmt.Cond)
+			}
+		}
+		switch {
+		case isEarlyReturn(ifStmt):
+			skips = append(skips, ifStmt.Cond)
+		case mayReturn(stmt):
+			known = false
+		}
+	}
+	return nil, nil
+}
+
+// isEarlyReturn checks if the if statement body ends with a return.
+func isEarlyReturn(ifStmt *ast.IfStmt) bool {
+	list := ifStmt.Body.List
+	if len(list) == 0 {
+		return false
+	}
+	_, ok := list[len(list)-1].(*ast.ReturnStmt)
+	return ok
+}
@@ -0,0 +1,154 @@
+package mulint
+
+import "go/ast"
+
+// flowKind is the kind of a compound statement (see flowShape).
+type flowKind int
+
+const (
+	flowBlock  flowKind = iota // a block, executed in place
+	flowIf                     // an if statement: the then branch and the else one
+	flowLoop                   // a for or range loop: the body, executed zero or more times
+	flowSwitch                 // a switch or type switch: the case clauses
+	flowSelect                 // a select: the communication clauses
+)
+
+// flowBranch is a statement list a compound statement may execute.
+type flowBranch struct {
+	body         []ast.Stmt
+	fallsThrough bool // the branch continues into the next one (ends with fallthrough)
+	present      bool // false for the else branch of an if statement without else
+}
+
+// flowShape is the control flow of a compound statement: its branches and whether one of them
+// always runs. The trackers (LockTracker and BranchTracker) walk compound statements via their
+// shapes (see walkFlow), so they cover the same statements and differ only in how they fork
+// the lock state for the branches and join it after the statement.
+type flowShape struct {
+	stmt       ast.Stmt
+	kind       flowKind
+	branches   []flowBranch
+	cond       ast.Expr // the if condition: the then branch runs when it's true, the else one otherwise
+	post       ast.Stmt // the post statement of a for loop, executed after each iteration
+	exhaustive bool     // one of the branches always runs: if/else, switch with default, select
+}
+
+// flowShapeOf returns the shape of a compound statement. An else if is a branch
+// consisting of the nested if statement.
+func flowShapeOf(stmt ast.Stmt) (*flowShape, bool) {
+	shape := &flowShape{stmt: stmt}
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		shape.kind = flowBlock
+		shape.branches = []flowBranch{{body: s.List, present: true}}
+	case *ast.IfStmt:
+		shape.kind = flowIf
+		shape.cond = s.Cond
+		shape.exhaustive = s.Else != nil
+		shape.branches = []flowBranch{{body: blockList(s.Body), present: true}, {present: s.Else != nil}}
+		switch e := s.Else.(type) {
+		case *ast.BlockStmt:
+			shape.branches[1].body = e.List
+		case *ast.IfStmt:
+			shape.branches[1].body = []ast.Stmt{e}
+		}
+	case *ast.ForStmt:
+		shape.kind = flowLoop
+		shape.post = s.Post
+		shape.branches = []flowBranch{{body: blockList(s.Body), present: true}}
+	case *ast.RangeStmt:
+		shape.kind = flowLoop
+		shape.branches = []flowBranch{{body: blockList(s.Body), present: true}}
+	case *ast.SwitchStmt:
+		shape.kind = flowSwitch
+		shape.branches, shape.exhaustive = caseBranches(s.Body)
+	case *ast.TypeSwitchStmt:
+		shape.kind = flowSwitch
+		shape.branches, shape.exhaustive = caseBranches(s.Body)
+	case *ast.SelectStmt:
+		// Either one of the communication cases or the default one runs
+		shape.kind = flowSelect
+		shape.exhaustive = true
+		if s.Body != nil {
+			for _, clause := range s.Body.List {
+				if cc, ok := clause.(*ast.CommClause); ok {
+					shape.branches = append(shape.branches, flowBranch{body: cc.Body, present: true})
+				}
+			}
+		}
+	default:
+		return nil, false
+	}
+	return shape, true
+}
+
+// caseBranches returns the branches of the switch case clauses and whether the default case is present.
+func caseBranches(body *ast.BlockStmt) ([]flowBranch, bool) {
+	if body == nil {
+		return nil, false
+	}
+	var branches []flowBranch
+	for _, clause := range body.List {
+		if cc, ok := clause.(*ast.CaseClause); ok {
+			branches = append(branches, flowBranch{body: cc.Body, fallsThrough: endsWithFallthrough(cc.Body), present: true})
+		}
+	}
+	return branches, hasDefaultClause(body)
+}
+
+func blockList(block *ast.BlockStmt) []ast.Stmt {
+	if block == nil {
+		return nil
+	}
+	return block.List
+}
+
+// flowEnd is the state at the end of a branch walked by walkFlow.
+type flowEnd[T any] struct {
+	state  T
+	branch int
+}
+
+// flowTracker is the lock state walked through compound statements by walkFlow.
+type flowTracker[T any] interface {
+	// walkBranch walks the statements of a branch.
+	walkBranch(stmts []ast.Stmt)
+	// enterFlow handles the parts of the statement executed before its branches (init statements, conditions).
+	enterFlow(shape *flowShape)
+	// forkFlow returns the state to walk the branch with (the tracker itself to walk it in place),
+	// or false to skip the branch.
+	forkFlow(shape *flowShape, branch int) (T, bool)
+	// joinFlow continues after the statement with the states at the ends of the walked branches.
+	// The branches falling through end with the branches they fall into.
+	joinFlow(shape *flowShape, ends []flowEnd[T])
+}
+
+// walkFlow walks the branches of a compound statement (if any) with the tracker's states.
+// A branch falling through continues into the next one with its state, so the next branch
+// is walked both on its own and as a continuation.
+func walkFlow[T flowTracker[T]](t T, stmt ast.Stmt) {
+	shape, ok := flowShapeOf(stmt)
+	if !ok {
+		return
+	}
+	t.enterFlow(shape)
+
+	var ends []flowEnd[T]
+	var fallen []T
+	for i, branch := range shape.branches {
+		states := fallen
+		fallen = nil
+		if state, ok := t.forkFlow(shape, i); ok {
+			states = append([]T{state}, states...)
+		}
+		for _, state := range states {
+			state.walkBranch(branch.body)
+			if branch.fallsThrough {
+				fallen = append(fallen, state)
+			} else {
+				ends = append(ends, flowEnd[T]{state: state, branch: i})
+			}
+		}
+	}
+	t.joinFlow(shape, ends)
+}
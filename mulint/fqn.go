@@ -1,6 +1,7 @@
 package mulint
 
 import (
+	"go/types"
 	"strings"
 )
 
@@ -22,3 +23,31 @@ func (f FQN) ShortName() string {
 	}
 	return s
 }
+
+// FromFunc returns the fully qualified name for a function or method object.
+func FromFunc(fn *types.Func) FQN {
+	pkg := ""
+	if fn.Pkg() != nil {
+		pkg = fn.Pkg().Path()
+	}
+	name := fn.Name()
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		var typeName string
+		pkg, typeName = qualifiedTypeName(sig.Recv().Type(), pkg)
+		name = typeName + ":" + name
+	}
+	return FromCallInfo(pkg, name)
+}
+
+// qualifiedTypeName returns the path of the package defining the (pointer to) named type
+// and the type name, so the same-named types of different packages (dot-imported ones included)
+// are told apart. The given package path is used for the types without a package.
+func qualifiedTypeName(t types.Type, pkg string) (string, string) {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil {
+		pkg = named.Obj().Pkg().Path()
+	}
+	return pkg, getTypeName(t)
+}
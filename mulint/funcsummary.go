@@ -0,0 +1,183 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+)
+
+// FunctionSummary is the per-function information shared between the checks. It's derived once
+// from the lock scopes, the call graph and the declaration of the function (see summaryOf)
+// instead of each check walking the function again.
+type FunctionSummary struct {
+	FQN             FQN
+	Decl            *ast.FuncDecl     // nil for the functions not declared in the package
+	Scopes          []*MutexScope     // lock scopes of the function
+	Acquires        []string          // mutexes acquired directly (see MutexScope.Key), except those of the values created by the function
+	Releases        []string          // mutexes acquired and released directly
+	ReturnsHolding  []string          // mutexes that may still be held when the function returns
+	Conditional     []ConditionalLock // locks acquired depending on the parameters
+	CallbackParams  []int             // indices of the function parameters called synchronously
+	LockedCallbacks []LockedCallback  // function parameters called while holding a mutex parameter's lock
+	BlockingCalls   []FQN             // blocking functions reached by the direct calls (see isBlockingFunc and MayBlock)
+	BlockingOp      string            // channel operation that may block (see inspectBlockingOps)
+
+	reachable *reachableSummary // built lazily by reachableSummary()
+}
+
+// reachableSummary is the information of the functions reachable from a function (including itself).
+type reachableSummary struct {
+	locks    []string // mutexes acquired
+	blocking []FQN    // blocking functions (see FunctionSummary.BlockingCalls)
+}
+
+// lockScope returns the scope of the mutex the function acquires, unless the mutex
+// belongs to a value created by the function (callers can't hold it).
+func (s *FunctionSummary) lockScope(selector string) *MutexScope {
+	for _, scope := range s.Scopes {
+		if scope.Selector() == selector && !scope.IsFresh() {
+			return scope
+		}
+	}
+	return nil
+}
+
+// locks checks if the function directly acquires the mutex identified by key.
+func (s *FunctionSummary) locks(key string) bool {
+	for _, scope := range s.Scopes {
+		if scope.Key(s.FQN) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// summaryOf returns the summary of the function, building it on first use.
+func (a *Analyzer) summaryOf(fqn FQN) *FunctionSummary {
+	if summary, ok := a.funcSummaries[fqn]; ok {
+		return summary
+	}
+	if a.funcSummaries == nil {
+		a.funcSummaries = make(map[FQN]*FunctionSummary)
+		a.decls = make(map[FQN]*ast.FuncDecl, len(a.funcs))
+		for _, decl := range a.funcs {
+			a.decls[a.declFQN(decl)] = decl
+		}
+	}
+
+	summary := &FunctionSummary{
+		FQN:         fqn,
+		Decl:        a.decls[fqn],
+		Conditional: a.conditionals.Get(fqn),
+	}
+
+	acquires := make(map[string]bool)
+	releases := make(map[string]bool)
+	held := make(map[string]bool)
+	if tracker, ok := a.scopes[fqn]; ok {
+		summary.Scopes = tracker.Scopes()
+		for _, scope := range summary.Scopes {
+			key := scope.Key(fqn)
+			if !scope.IsFresh() {
+				acquires[key] = true
+			}
+			if scope.IsUnlocked() {
+				releases[key] = true
+			} else {
+				held[key] = true
+			}
+		}
+	}
+	summary.Acquires = sortedKeys(acquires)
+	summary.Releases = sortedKeys(releases)
+	summary.ReturnsHolding = sortedKeys(held)
+
+	for _, callee := range a.calls[fqn] {
+		blocking := callee
+		if fact := a.importedMayBlock(callee); fact != nil {
+			blocking = fact.Via
+		} else if !a.isBlockingFunc(callee) {
+			continue
+		}
+		if !slices.Contains(summary.BlockingCalls, blocking) {
+			summary.BlockingCalls = append(summary.BlockingCalls, blocking)
+		}
+	}
+	summary.BlockingOp = a.blockingOp(summary.Decl)
+	if summary.Decl != nil {
+		summary.CallbackParams = callbackParams(summary.Decl, a.info)
+		summary.LockedCallbacks = lockedCallbacks(summary.Decl, a.info)
+	}
+
+	a.funcSummaries[fqn] = summary
+	return summary
+}
+
+// reachableSummary returns the information of the functions reachable from the function
+// through the call graph, building it on first use.
+func (a *Analyzer) reachableSummary(fqn FQN) *reachableSummary {
+	summary := a.summaryOf(fqn)
+	if summary.reachable != nil {
+		return summary.reachable
+	}
+
+	locks := make(map[string]bool)
+	var blocking []FQN
+	if a.isBlockingFunc(fqn) {
+		blocking = append(blocking, fqn)
+	}
+	for callee := range a.reachableFrom(fqn) {
+		calleeSummary := a.summaryOf(callee)
+		for _, key := range calleeSummary.Acquires {
+			locks[key] = true
+		}
+		for _, b := range calleeSummary.BlockingCalls {
+			if !slices.Contains(blocking, b) {
+				blocking = append(blocking, b)
+			}
+		}
+		if calleeSummary.BlockingOp != "" && !slices.Contains(blocking, callee) {
+			blocking = append(blocking, callee)
+		}
+	}
+	slices.Sort(blocking)
+
+	summary.reachable = &reachableSummary{locks: sortedKeys(locks), blocking: blocking}
+	return summary.reachable
+}
+
+// callbackParams returns the indices of the function-typed parameters the function calls
+// synchronously (e.g., fn in func each(items []string, fn func(string))).
+func callbackParams(decl *ast.FuncDecl, info *types.Info) []int {
+	params := make(map[types.Object]int)
+	index := 0
+	for _, field := range decl.Type.Params.List {
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil {
+				if _, ok := obj.Type().Underlying().(*types.Signature); ok {
+					params[obj] = index
+				}
+			}
+			index++
+		}
+		if len(field.Names) == 0 {
+			index++
+		}
+	}
+	if len(params) == 0 || decl.Body == nil {
+		return nil
+	}
+
+	var indices []int
+	inspectScopeCalls(decl.Body, info, func(call *ast.CallExpr) {
+		ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		if !ok {
+			return
+		}
+		if i, ok := params[info.Uses[ident]]; ok && !slices.Contains(indices, i) {
+			indices = append(indices, i)
+		}
+	})
+	slices.Sort(indices)
+	return indices
+}
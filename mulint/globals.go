@@ -0,0 +1,54 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// packageVar returns the package-level variable at the root of the expression
+// (state for state.mu), if any.
+func packageVar(expr ast.Expr, info *types.Info) *types.Var {
+	if info == nil {
+		return nil
+	}
+
+	var root *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		root = e
+	case *ast.SelectorExpr:
+		root = RootSelector(e)
+	}
+	if root == nil {
+		return nil
+	}
+
+	v, ok := info.ObjectOf(root).(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
+
+// anonymousFieldKey returns the key of a field of a package-level variable of an anonymous
+// struct type, e.g. "state.n" for:
+//
+//	var state = struct {
+//		mu sync.Mutex
+//		n  int
+//	}{}
+//
+// Such fields have no type name to be keyed by, so the variable is used instead.
+func anonymousFieldKey(sel *ast.SelectorExpr, info *types.Info) string {
+	selection, ok := info.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return ""
+	}
+	if _, isAnonymous := derefType(selection.Recv()).(*types.Struct); !isAnonymous {
+		return ""
+	}
+	if packageVar(sel, info) == nil {
+		return ""
+	}
+	return StrExpr(sel)
+}
@@ -0,0 +1,131 @@
+package mulint
+
+import (
+	"maps"
+	"slices"
+)
+
+// LockGraph is the lock structure of an analyzed package: the mutexes acquired by its functions,
+// the calls between them and the lock wrappers. It's the result of the Mulint analyzer,
+// so other analyzers requiring it can build custom checks and exports on top of it.
+//
+// Mutexes are identified by their keys (see MutexScope.Key): "Server.mu" for the mu field
+// of Server values, whatever the receiver name, and "state.mu" for package-level variables.
+// The graph is immutable and safe for concurrent use.
+type LockGraph struct {
+	funcs     []FQN
+	scopes    map[FQN][]*MutexScope
+	calls     map[FQN][]FQN
+	acquires  map[FQN][]string // mutexes acquired directly
+	reachable map[FQN][]string // mutexes acquired directly or transitively
+	holders   map[string][]FQN // functions acquiring the mutexes directly
+	wrappers  map[FQN]WrapperMethod
+}
+
+// LockGraph builds the lock graph of the package functions (see LockGraph).
+func (a *Analyzer) LockGraph() *LockGraph {
+	g := &LockGraph{
+		scopes:    make(map[FQN][]*MutexScope),
+		calls:     make(map[FQN][]FQN),
+		acquires:  make(map[FQN][]string),
+		reachable: make(map[FQN][]string),
+		holders:   make(map[string][]FQN),
+		wrappers:  make(map[FQN]WrapperMethod),
+	}
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if fqn == "" || slices.Contains(g.funcs, fqn) {
+			continue
+		}
+		g.funcs = append(g.funcs, fqn)
+
+		summary := a.summaryOf(fqn)
+		g.scopes[fqn] = summary.Scopes
+		g.acquires[fqn] = slices.Sorted(slices.Values(summary.Acquires))
+		g.reachable[fqn] = a.LocksReachable(fqn)
+		for _, key := range summary.Acquires {
+			g.holders[key] = append(g.holders[key], fqn)
+		}
+		if callees := a.calls[fqn]; len(callees) > 0 {
+			g.calls[fqn] = slices.Sorted(slices.Values(callees))
+		}
+		if wrapper, ok := a.wrappers.Get(fqn); ok {
+			g.wrappers[fqn] = wrapper
+		}
+	}
+	slices.Sort(g.funcs)
+	for key := range g.holders {
+		slices.Sort(g.holders[key])
+	}
+	return g
+}
+
+// Functions returns the functions of the package, sorted.
+func (g *LockGraph) Functions() []FQN {
+	return g.funcs
+}
+
+// Scopes returns the lock scopes of the function.
+func (g *LockGraph) Scopes(fqn FQN) []*MutexScope {
+	return g.scopes[fqn]
+}
+
+// Calls returns the functions the function calls, sorted (including the ones of other packages
+// and the possible callees of dynamic calls, see -callgraph).
+func (g *LockGraph) Calls(fqn FQN) []FQN {
+	return g.calls[fqn]
+}
+
+// Acquires returns the mutexes the function acquires directly (or via lock wrappers), sorted.
+func (g *LockGraph) Acquires(fqn FQN) []string {
+	return g.acquires[fqn]
+}
+
+// LocksOf returns the mutexes acquired by the function or any function reachable from it
+// through the call graph, sorted.
+func (g *LockGraph) LocksOf(fqn FQN) []string {
+	return g.reachable[fqn]
+}
+
+// HoldersOf returns the functions acquiring the mutex with the key directly, sorted.
+func (g *LockGraph) HoldersOf(mutex string) []FQN {
+	return g.holders[mutex]
+}
+
+// Mutexes returns the keys of the mutexes acquired within the package, sorted.
+func (g *LockGraph) Mutexes() []string {
+	return slices.Sorted(maps.Keys(g.holders))
+}
+
+// Wrapper returns the wrapper info of the function if it's a lock or unlock wrapper.
+func (g *LockGraph) Wrapper(fqn FQN) (WrapperMethod, bool) {
+	w, ok := g.wrappers[fqn]
+	return w, ok
+}
+
+// PathsBetween returns the call paths from one function to another, each starting with from
+// and ending with to, shortest first. Paths don't visit a function twice, except for ending
+// where they start (recursive calls of from when to is the same function).
+func (g *LockGraph) PathsBetween(from, to FQN) [][]FQN {
+	var paths [][]FQN
+	onPath := make(map[FQN]bool)
+	var walk func(path []FQN)
+	walk = func(path []FQN) {
+		current := path[len(path)-1]
+		onPath[current] = true
+		for _, callee := range g.calls[current] {
+			switch {
+			case callee == to:
+				paths = append(paths, append(slices.Clone(path), callee))
+			case !onPath[callee]:
+				walk(append(path, callee))
+			}
+		}
+		onPath[current] = false
+	}
+	walk([]FQN{from})
+
+	slices.SortStableFunc(paths, func(a, b []FQN) int { return len(a) - len(b) })
+	return paths
+}
@@ -0,0 +1,116 @@
+package mulint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// LintErrorGroup clusters reentrant lock errors for the same mutex within the same function.
+type LintErrorGroup struct {
+	fqn      FQN
+	selector string
+	errors   []LintError // sorted by the second lock position
+}
+
+// GroupLintErrors clusters errors by function and mutex selector.
+// Read lock upgrades are grouped separately from the other reentrant locks.
+// Groups are returned in the order of their first error position.
+func GroupLintErrors(errors []LintError) []LintErrorGroup {
+	sorted := make([]LintError, len(errors))
+	copy(sorted, errors)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].secondLock.pos < sorted[j].secondLock.pos
+	})
+
+	type groupKey struct {
+		fqn      FQN
+		selector string
+		upgrade  bool
+	}
+
+	index := make(map[groupKey]int)
+	var groups []LintErrorGroup
+	for _, err := range sorted {
+		key := groupKey{fqn: err.fqn, selector: err.selector, upgrade: err.upgrade}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, LintErrorGroup{fqn: err.fqn, selector: err.selector})
+		}
+		groups[i].errors = append(groups[i].errors, err)
+	}
+	return groups
+}
+
+// Report emits a single diagnostic for the group: the first error is the primary one,
+// the other sites are listed in the message and as related locations.
+func (g LintErrorGroup) Report(pass *analysis.Pass) {
+	if len(g.errors) == 1 {
+		g.errors[0].Report(pass)
+		return
+	}
+
+	primary := g.errors[0]
+	primaryPosition := pass.Fset.Position(primary.secondLock.pos)
+	originPosition := pass.Fset.Position(primary.origin.pos)
+
+	_, originLabel := primary.labels()
+	summary := fmt.Sprintf("Mutex lock %s is acquired again at %d places in %s", g.selector, len(g.errors), g.fqn.ShortName())
+	if primary.upgrade {
+		summary = fmt.Sprintf("Mutex write lock %s is acquired under the read lock at %d places in %s", g.selector, len(g.errors), g.fqn.ShortName())
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%s: %s\n\t%s:%d: But %s: %s\n",
+		summary,
+		strings.TrimSpace(sourceLine(primaryPosition)),
+		relativePath(originPosition.Filename),
+		originPosition.Line,
+		originLabel,
+		strings.TrimSpace(sourceLine(originPosition)),
+	)
+
+	related := []analysis.RelatedInformation{
+		{Pos: primary.origin.pos, Message: originLabel},
+	}
+	for _, err := range g.errors[1:] {
+		position := pass.Fset.Position(err.secondLock.pos)
+		fmt.Fprintf(&msg, "\t%s:%d: Also acquired on this line: %s\n",
+			relativePath(position.Filename),
+			position.Line,
+			strings.TrimSpace(sourceLine(position)),
+		)
+		related = append(related, analysis.RelatedInformation{
+			Pos:     err.secondLock.pos,
+			Message: "lock is acquired again here",
+		})
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:            primary.secondLock.Pos(),
+		Message:        msg.String(),
+		Related:        related,
+		SuggestedFixes: g.suggestedFixes(),
+	}, CodeReentrantLock, fmt.Sprintf("%s (acquired at %s)", summary, shortPosition(pass, primary.origin.pos)))
+}
+
+// suggestedFixes combines the suggested fixes of the group errors into a single fix.
+func (g LintErrorGroup) suggestedFixes() []analysis.SuggestedFix {
+	var fixes []analysis.SuggestedFix
+	for _, err := range g.errors {
+		fixes = append(fixes, err.suggestedFixes()...)
+	}
+	if len(fixes) <= 1 {
+		return fixes
+	}
+
+	combined := analysis.SuggestedFix{Message: "Call the Locked variants instead"}
+	for _, fix := range fixes {
+		combined.TextEdits = append(combined.TextEdits, fix.TextEdits...)
+	}
+	return []analysis.SuggestedFix{combined}
+}
@@ -0,0 +1,95 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// checkGuardedReturns reports returns of guarded data of reference types (maps, slices and pointers)
+// made while holding the mutex guarding it (see GuardIndex), e.g. return s.items: callers get
+// access to the data and may read or mutate it after the lock is released.
+// Only the fields of the value owning the mutex are considered.
+func (a *Analyzer) checkGuardedReturns() {
+	guards := a.guardIndex()
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			if scope.IsFresh() || scope.IsGlobal() {
+				continue
+			}
+			mutex := scope.Key(fqn)
+
+			for _, node := range scope.Nodes() {
+				ast.Inspect(node, func(n ast.Node) bool {
+					if _, ok := n.(*ast.FuncLit); ok {
+						return false
+					}
+					ret, ok := n.(*ast.ReturnStmt)
+					if !ok {
+						return true
+					}
+					for _, result := range ret.Results {
+						sel := guardedReference(result, a.info)
+						if sel == nil || reported[result.Pos()] {
+							continue
+						}
+						field := guardableField(sel, a.info)
+						if !guards.IsGuardedBy(field, mutex) || !strings.HasPrefix(scope.Selector(), LockSelector(sel.X, a.info)+".") {
+							continue
+						}
+						reported[result.Pos()] = true
+						a.diagnostics = append(a.diagnostics, NewGuardedReturnError(
+							NewLocation(result.Pos()),
+							NewLocation(scope.Pos()),
+							StrExpr(result),
+							scope.Selector(),
+							copyHint(result, a.info),
+						))
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+// guardedReference returns the field access if the expression shares the field's data with the caller:
+// a field of a map, slice or pointer type (s.items), its slice (s.items[1:]) or its address (&s.config).
+func guardedReference(expr ast.Expr, info *types.Info) *ast.SelectorExpr {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		switch info.TypeOf(e).Underlying().(type) {
+		case *types.Map, *types.Slice, *types.Pointer:
+			return e
+		}
+	case *ast.SliceExpr:
+		if sel, ok := ast.Unparen(e.X).(*ast.SelectorExpr); ok {
+			if _, isSlice := info.TypeOf(sel).Underlying().(*types.Slice); isSlice {
+				return sel
+			}
+		}
+	case *ast.UnaryExpr:
+		if sel, ok := ast.Unparen(e.X).(*ast.SelectorExpr); ok && e.Op == token.AND {
+			return sel
+		}
+	}
+	return nil
+}
+
+// copyHint returns the suggested way of copying the returned value (e.g., "slices.Clone(s.items)").
+func copyHint(expr ast.Expr, info *types.Info) string {
+	switch info.TypeOf(expr).Underlying().(type) {
+	case *types.Map:
+		return "maps.Clone(" + StrExpr(expr) + ")"
+	case *types.Slice:
+		return "slices.Clone(" + StrExpr(expr) + ")"
+	}
+	return "a copy"
+}
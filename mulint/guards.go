@@ -0,0 +1,118 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// GuardIndex infers which mutexes guard which fields: a field is considered
+// guarded by a mutex if it is accessed within a scope holding that mutex.
+// Fields are identified by their typed key ("Cache.items"), mutexes by their mutex key ("Cache.mu").
+type GuardIndex struct {
+	guards map[string]map[string]bool
+}
+
+// BuildGuardIndex collects field accesses from all lock scopes.
+func BuildGuardIndex(scopes map[FQN]*LockTracker, info *types.Info) *GuardIndex {
+	idx := &GuardIndex{guards: make(map[string]map[string]bool)}
+
+	for fqn, tracker := range scopes {
+		for _, scope := range tracker.Scopes() {
+			mutex := scope.Key(fqn)
+			for _, node := range scope.Nodes() {
+				ast.Inspect(node, func(n ast.Node) bool {
+					if _, ok := n.(*ast.FuncLit); ok {
+						return false
+					}
+					if field := guardableField(n, info); field != "" && field != mutex {
+						if idx.guards[field] == nil {
+							idx.guards[field] = make(map[string]bool)
+						}
+						idx.guards[field][mutex] = true
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	return idx
+}
+
+// GuardedBy returns the mutexes guarding the field.
+func (idx *GuardIndex) GuardedBy(field string) []string {
+	return sortedKeys(idx.guards[field])
+}
+
+// IsGuardedBy checks if the field is guarded by the mutex.
+func (idx *GuardIndex) IsGuardedBy(field, mutex string) bool {
+	return idx.guards[field][mutex]
+}
+
+// guardableField returns the typed key of a struct field access (e.g. "Cache.items"),
+// or an empty string if the node is not a field access or the field is a sync primitive.
+func guardableField(n ast.Node, info *types.Info) string {
+	sel, ok := n.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	selection, ok := info.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return ""
+	}
+	if isSyncType(selection.Type()) {
+		return ""
+	}
+	if key := anonymousFieldKey(sel, info); key != "" {
+		return key
+	}
+	return getTypeName(selection.Recv()) + "." + sel.Sel.Name
+}
+
+// isSyncType checks if a type is declared in the sync package (or is a configured mutex type).
+func isSyncType(t types.Type) bool {
+	if isMutexTypeName(t) {
+		return true
+	}
+	named, ok := derefType(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "sync"
+}
+
+// guardIndex returns the guard index, building it on first use.
+func (a *Analyzer) guardIndex() *GuardIndex {
+	if a.guards == nil {
+		a.guards = BuildGuardIndex(a.scopes, a.info)
+	}
+	return a.guards
+}
+
+// fieldsIn returns the typed keys of the fields accessed in the node, sorted.
+func fieldsIn(node ast.Node, info *types.Info) []string {
+	fields := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if field := guardableField(n, info); field != "" {
+			fields[field] = true
+		}
+		return true
+	})
+	return sortedKeys(fields)
+}
+
+// isHeldAt checks if the node is located within a scope of the function holding the mutex.
+func (a *Analyzer) isHeldAt(fqn FQN, node ast.Node, mutex string) bool {
+	tracker, ok := a.scopes[fqn]
+	if !ok {
+		return false
+	}
+	for _, scope := range tracker.Scopes() {
+		if scope.Key(fqn) != mutex {
+			continue
+		}
+		for _, n := range scope.Nodes() {
+			if n.Pos() <= node.Pos() && node.End() <= n.End() {
+				return true
+			}
+		}
+	}
+	return false
+}
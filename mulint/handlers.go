@@ -0,0 +1,197 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// handlerRegistrationMethods are the mux methods used to register HTTP handlers,
+// both on *http.ServeMux and as package-level net/http functions.
+var handlerRegistrationMethods = []string{"Handle", "HandleFunc"}
+
+// FindHTTPHandlers returns the functions registered as HTTP handlers within the
+// given function bodies, mapped to the position of their registration call.
+// Handlers wrapped in http.HandlerFunc conversions or middleware calls are unwrapped.
+func FindHTTPHandlers(funcs []*ast.FuncDecl, info *types.Info) map[FQN]token.Pos {
+	handlers := make(map[FQN]token.Pos)
+
+	for _, fn := range funcs {
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isHandlerRegistration(call) {
+				return true
+			}
+
+			for _, arg := range call.Args {
+				if t := info.TypeOf(arg); t == nil || !isHTTPHandlerType(t) {
+					continue
+				}
+				for _, fqn := range handlerFuncs(arg, info) {
+					if _, exists := handlers[fqn]; !exists {
+						handlers[fqn] = call.Pos()
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return handlers
+}
+
+// isHandlerRegistration checks if a call looks like mux.Handle(...) or http.HandleFunc(...).
+func isHandlerRegistration(call *ast.CallExpr) bool {
+	selector := SelectorExpr(call)
+	if selector == nil {
+		return false
+	}
+	for _, name := range handlerRegistrationMethods {
+		if selector.Sel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTTPHandlerType checks if a type is an http.Handler implementation or
+// a func(http.ResponseWriter, *http.Request).
+func isHTTPHandlerType(t types.Type) bool {
+	if sig, ok := t.Underlying().(*types.Signature); ok {
+		params := sig.Params()
+		return params.Len() == 2 &&
+			isNetHTTPType(params.At(0).Type(), "ResponseWriter") &&
+			isNetHTTPType(params.At(1).Type(), "Request")
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, "ServeHTTP")
+	_, isMethod := obj.(*types.Func)
+	return isMethod
+}
+
+// isNetHTTPType checks if a type (or the type it points to) is net/http.<name>.
+func isNetHTTPType(t types.Type, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == name
+}
+
+// handlerFuncs resolves a handler expression to the functions it refers to.
+// Conversions (http.HandlerFunc(h)) and middleware calls (mw(h)) are unwrapped.
+func handlerFuncs(expr ast.Expr, info *types.Info) []FQN {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return handlerFuncs(e.X, info)
+	case *ast.SelectorExpr:
+		// Method value: s.handleIndex
+		if sel, ok := info.Selections[e]; ok {
+			if fn, ok := sel.Obj().(*types.Func); ok && sel.Kind() == types.MethodVal {
+				return []FQN{FromFunc(fn)}
+			}
+			return nil
+		}
+		// Package-qualified function: handlers.Index
+		if fn, ok := info.Uses[e.Sel].(*types.Func); ok {
+			return []FQN{FromFunc(fn)}
+		}
+	case *ast.Ident:
+		if fn, ok := info.Uses[e].(*types.Func); ok {
+			return []FQN{FromFunc(fn)}
+		}
+	case *ast.CallExpr:
+		var fqns []FQN
+		for _, arg := range e.Args {
+			fqns = append(fqns, handlerFuncs(arg, info)...)
+		}
+		return fqns
+	}
+	return nil
+}
+
+// checkSharedHandlerLocks detects helpers called by an HTTP handler while holding
+// a mutex, when the same helper is also used by another handler locking that mutex.
+func (a *Analyzer) checkSharedHandlerLocks() {
+	handlers := FindHTTPHandlers(a.funcs, a.info)
+	if len(handlers) == 0 {
+		return
+	}
+
+	entries := make([]FQN, 0, len(handlers))
+	for fqn := range handlers {
+		entries = append(entries, fqn)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i] < entries[j] })
+
+	reachable := make(map[FQN]map[FQN]bool, len(entries))
+	for _, fqn := range entries {
+		reachable[fqn] = a.reachableFrom(fqn)
+	}
+
+	reported := make(map[token.Pos]bool)
+	for _, handler := range entries {
+		tracker, ok := a.scopes[handler]
+		if !ok {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			key := scope.Key(handler)
+
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if reported[call.Pos()] || a.isCallOnDifferentReceiver(call, scope) {
+						return
+					}
+					pkg, name, ok := GetCallInfo(call, a.info)
+					if !ok || pkg != a.pass.Pkg.Path() {
+						return
+					}
+					helper := FromCallInfo(pkg, name)
+
+					for _, other := range entries {
+						if other == handler || !reachable[other][helper] || !a.locksMutex(other, key) {
+							continue
+						}
+						reported[call.Pos()] = true
+						a.diagnostics = append(a.diagnostics, NewSharedHandlerLockError(
+							NewLocation(scope.Pos()),
+							NewLocation(call.Pos()),
+							helper,
+							other,
+						))
+						return
+					}
+				})
+			}
+		}
+	}
+}
+
+// locksMutex checks if a function directly acquires the mutex identified by key.
+func (a *Analyzer) locksMutex(fqn FQN, key string) bool {
+	return a.summaryOf(fqn).locks(key)
+}
+
+// reachableFrom returns all functions reachable from fqn (including itself)
+// through the collected call graph.
+func (a *Analyzer) reachableFrom(fqn FQN) map[FQN]bool {
+	visited := map[FQN]bool{fqn: true}
+	queue := []FQN{fqn}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, callee := range a.calls[current] {
+			if !visited[callee] {
+				visited[callee] = true
+				queue = append(queue, callee)
+			}
+		}
+	}
+	return visited
+}
@@ -2,10 +2,12 @@ package mulint
 
 import (
 	"bytes"
+	"fmt"
 	"go/ast"
 	"go/printer"
 	"go/token"
 	"go/types"
+	"slices"
 )
 
 // StrExpr converts an AST expression to its string representation.
@@ -15,6 +17,51 @@ func StrExpr(e ast.Expr) string {
 	return buf.String()
 }
 
+// LockSelector returns the selector identifying a mutex expression within a function.
+// Variables shadowing other local variables of the same name (e.g., if s := other; ...)
+// get a "#N" suffix (N is the number of shadowed declarations), so that their mutexes
+// aren't conflated with the ones of the outer variables: "s#1.mu".
+func LockSelector(e ast.Expr, info *types.Info) string {
+	selector := StrExpr(e)
+	if info == nil {
+		return selector
+	}
+
+	var root *ast.Ident
+	switch x := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		root = x
+	case *ast.SelectorExpr:
+		root = RootSelector(x)
+	}
+	if root == nil {
+		return selector
+	}
+
+	if depth := shadowDepth(info.ObjectOf(root)); depth > 0 {
+		return fmt.Sprintf("%s#%d%s", root.Name, depth, selector[len(root.Name):])
+	}
+	return selector
+}
+
+// shadowDepth returns the number of local variables of the same name the object shadows.
+func shadowDepth(obj types.Object) int {
+	if obj == nil || obj.Pkg() == nil || obj.Parent() == nil {
+		return 0
+	}
+
+	depth := 0
+	for scope := obj.Parent().Parent(); scope != nil; {
+		_, outer := scope.LookupParent(obj.Name(), token.NoPos)
+		if outer == nil || outer.Parent() == nil || outer.Parent() == outer.Pkg().Scope() || outer.Parent() == types.Universe {
+			break
+		}
+		depth++
+		scope = outer.Parent().Parent()
+	}
+	return depth
+}
+
 // SplitSelector splits a selector string into root and field parts.
 // For example, "w.m" returns ("w", "m"), "s.mu" returns ("s", "mu").
 func SplitSelector(selector string) (root, field string) {
@@ -26,53 +73,49 @@ func SplitSelector(selector string) (root, field string) {
 	return selector, ""
 }
 
-// CallExpr extracts a CallExpr from a node if present.
+// CallExpr extracts a CallExpr from a node if present: the call itself, the call
+// of an expression statement or the first call assigned by an assignment (see CallExprs).
 func CallExpr(node ast.Node) *ast.CallExpr {
-	switch n := node.(type) {
-	case *ast.CallExpr:
-		return n
-	case *ast.ExprStmt:
-		if call, ok := n.X.(*ast.CallExpr); ok {
-			return call
-		}
-	case *ast.AssignStmt:
-		// Handle: v := foo() or v = foo()
-		for _, rhs := range n.Rhs {
-			if call, ok := rhs.(*ast.CallExpr); ok {
-				return call
-			}
-		}
+	if calls := CallExprs(node); len(calls) > 0 {
+		return calls[0]
 	}
 	return nil
 }
 
-// SubjectForCall returns the receiver expression if the node is a call
-// to one of the named methods. For example, for "m.Lock()" with names=["Lock"],
-// it returns the expression "m".
-func SubjectForCall(node ast.Node, names []string) ast.Expr {
-	var call *ast.CallExpr
-
+// CallExprs returns the calls made by a node at its top level: the call itself, the call
+// of an expression statement or the calls assigned by an assignment, e.g. the single call
+// of v, err := foo() and both calls of a, b := foo(), bar() (or _ = foo(), bar()).
+func CallExprs(node ast.Node) []*ast.CallExpr {
+	var exprs []ast.Expr
 	switch n := node.(type) {
 	case *ast.CallExpr:
-		call = n
+		return []*ast.CallExpr{n}
 	case *ast.ExprStmt:
-		var ok bool
-		call, ok = n.X.(*ast.CallExpr)
-		if !ok {
-			return nil
-		}
-	default:
-		return nil
+		exprs = []ast.Expr{n.X}
+	case *ast.AssignStmt:
+		exprs = n.Rhs
 	}
 
-	selector := SelectorExpr(call)
-	if selector == nil {
-		return nil
+	var calls []*ast.CallExpr
+	for _, expr := range exprs {
+		if call, ok := ast.Unparen(expr).(*ast.CallExpr); ok {
+			calls = append(calls, call)
+		}
 	}
+	return calls
+}
 
-	fnName := selector.Sel.Name
-	for _, name := range names {
-		if name == fnName {
+// SubjectForCall returns the receiver expression if the node is a call
+// to one of the named methods or makes one at its top level (see CallExprs).
+// For example, for "m.Lock()" with names=["Lock"], it returns the expression "m",
+// and so it does for "wait := m.Lock()" of a mutex type reporting the wait time.
+func SubjectForCall(node ast.Node, names []string) ast.Expr {
+	for _, call := range CallExprs(node) {
+		selector := SelectorExpr(call)
+		if selector == nil {
+			continue
+		}
+		if slices.Contains(names, selector.Sel.Name) {
 			return selector.X
 		}
 	}
@@ -113,7 +156,8 @@ func IsMutexType(expr ast.Expr, info *types.Info) bool {
 	return isMutexTypeName(t)
 }
 
-// isMutexTypeName checks if a type is sync.Mutex or sync.RWMutex.
+// isMutexTypeName checks if a type is sync.Mutex or sync.RWMutex
+// (or one of the types configured via -mutex-types).
 func isMutexTypeName(t types.Type) bool {
 	// Handle pointer types
 	if ptr, ok := t.(*types.Pointer); ok {
@@ -134,5 +178,9 @@ func isMutexTypeName(t types.Type) bool {
 	pkgPath := obj.Pkg().Path()
 	typeName := obj.Name()
 
-	return pkgPath == "sync" && (typeName == "Mutex" || typeName == "RWMutex")
+	if pkgPath == "sync" && (typeName == "Mutex" || typeName == "RWMutex") {
+		return true
+	}
+
+	return config.isCustomMutexType(pkgPath, typeName)
 }
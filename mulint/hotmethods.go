@@ -0,0 +1,66 @@
+package mulint
+
+import (
+	"go/ast"
+	"slices"
+)
+
+// hotMethods are the methods commonly called implicitly (by fmt, errors, sort, hash-based containers)
+// or on hot paths, so acquiring locks in them is a performance hazard and a reentrancy trap:
+// fmt.Sprintf("%v", s) or log.Println(err) under the lock deadlocks.
+var hotMethods = []string{"String", "Error", "Hash", "Less"}
+
+// lockSite is a lock acquired by a function or one of its callees.
+type lockSite struct {
+	scope *MutexScope
+	fqn   FQN // the function acquiring the lock
+}
+
+// checkHotMethodLocks reports String, Error, Hash and Less methods acquiring locks,
+// directly or via their callees, along with the lock positions. So are the other sort.Interface
+// methods of the values passed to sort.Sort, heap.Push, etc. (see sortInterfaceCallers).
+func (a *Analyzer) checkHotMethodLocks() {
+	sorted := a.sortedMethods()
+	for _, fn := range a.funcs {
+		if fn.Body == nil {
+			continue
+		}
+		fqn := a.declFQN(fn)
+		if !isHotMethod(fn) && !sorted[fqn] {
+			continue
+		}
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		sites := a.reachableLockSites(fqn)
+		if len(sites) == 0 {
+			continue
+		}
+		a.diagnostics = append(a.diagnostics, NewHotMethodLockError(
+			NewLocation(fn.Name.Pos()),
+			fqn,
+			sites,
+		))
+	}
+}
+
+// reachableLockSites returns the locks acquired by the function or the functions reachable from it
+// (except those of the values created by them), ordered by position.
+func (a *Analyzer) reachableLockSites(fqn FQN) []lockSite {
+	var sites []lockSite
+	for callee := range a.reachableFrom(fqn) {
+		for _, scope := range a.summaryOf(callee).Scopes {
+			if !scope.IsFresh() && !slices.ContainsFunc(sites, func(s lockSite) bool { return s.scope.Pos() == scope.Pos() }) {
+				sites = append(sites, lockSite{scope: scope, fqn: callee})
+			}
+		}
+	}
+	slices.SortFunc(sites, func(x, y lockSite) int { return int(x.scope.Pos() - y.scope.Pos()) })
+	return sites
+}
+
+// isHotMethod checks if the declaration is one of the hot methods (see hotMethods).
+func isHotMethod(fn *ast.FuncDecl) bool {
+	return fn.Recv != nil && slices.Contains(hotMethods, fn.Name.Name)
+}
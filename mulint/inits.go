@@ -0,0 +1,72 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// forEachInit calls fn for every value assigned to a struct field or package-level
+// variable within the node:
+//
+//	var pool = sync.Pool{...}            // key: "pool"
+//	s.bufs = sync.Pool{...}              // key: "Server.bufs"
+//	s := &Server{bufs: sync.Pool{...}}   // key: "Server.bufs"
+//
+// The target is the assigned expression (or the field name for composite literals).
+// Assignments to local variables are reported with an empty key.
+func forEachInit(node ast.Node, info *types.Info, fn func(target ast.Expr, key string, value ast.Expr)) {
+	switch n := node.(type) {
+	case *ast.ValueSpec:
+		for i, name := range n.Names {
+			if i < len(n.Values) {
+				fn(name, fieldOrVarKey(name, info), n.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		if len(n.Lhs) != len(n.Rhs) {
+			return
+		}
+		for i, lhs := range n.Lhs {
+			fn(lhs, fieldOrVarKey(lhs, info), n.Rhs[i])
+		}
+	case *ast.CompositeLit:
+		named, ok := derefType(info.TypeOf(n)).(*types.Named)
+		if !ok {
+			return
+		}
+		if _, isStruct := named.Underlying().(*types.Struct); !isStruct {
+			return
+		}
+		for _, elt := range n.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if field, ok := kv.Key.(*ast.Ident); ok {
+				fn(field, named.Obj().Name()+"."+field.Name, kv.Value)
+			}
+		}
+	}
+}
+
+// fieldOrVarKey identifies a struct field by its type and name ("Queue.cond"),
+// or a package-level variable by its name.
+func fieldOrVarKey(expr ast.Expr, info *types.Info) string {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if key := anonymousFieldKey(e, info); key != "" {
+			return key
+		}
+		if sel, ok := info.Selections[e]; ok && sel.Kind() == types.FieldVal {
+			return getTypeName(sel.Recv()) + "." + e.Sel.Name
+		}
+	case *ast.Ident:
+		obj := info.ObjectOf(e)
+		if v, ok := obj.(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+			return v.Name()
+		}
+	case *ast.UnaryExpr:
+		return fieldOrVarKey(e.X, info)
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// inventoryCriticalSections lists, for each lock scope, the calls to other packages made while
+// holding the lock, including the methods of the protected values of external types
+// (e.g., c.items.PushBack() on a container/list.List). Calls of the analyzed package
+// are covered by the checks themselves, so they're left out, as are the mutex operations
+// and scopes without external calls.
+func (a *Analyzer) inventoryCriticalSections() {
+	reported := make(map[token.Pos]bool)
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		tracker, ok := a.scopes[fqn]
+		if !ok || !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			if reported[scope.Pos()] {
+				continue
+			}
+			reported[scope.Pos()] = true
+
+			calls := a.externalCalls(scope)
+			if len(calls) == 0 {
+				continue
+			}
+			a.diagnostics = append(a.diagnostics, NewCriticalSectionReport(NewLocation(scope.Pos()), scope.Selector(), calls))
+		}
+	}
+}
+
+// externalCalls returns the sorted and deduplicated calls to other packages within the scope.
+func (a *Analyzer) externalCalls(scope *MutexScope) []FQN {
+	seen := make(map[FQN]bool)
+	var calls []FQN
+	for _, node := range scope.Nodes() {
+		inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+			// Mutex operations make the critical section rather than extend it
+			if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && IsMutexType(sel.X, a.info) {
+				return
+			}
+			pkg, name, ok := GetCallInfo(call, a.info)
+			if !ok || pkg == "" || pkg == a.pass.Pkg.Path() {
+				return
+			}
+			callee := FromCallInfo(pkg, name)
+			if !seen[callee] {
+				seen[callee] = true
+				calls = append(calls, callee)
+			}
+		})
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i] < calls[j] })
+	return calls
+}
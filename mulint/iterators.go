@@ -0,0 +1,153 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// isIterator checks if the type is a range-over-func iterator,
+// func(yield func(...) bool) (e.g., iter.Seq or iter.Seq2).
+func isIterator(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() != 1 || sig.Results().Len() != 0 {
+		return false
+	}
+	yield, ok := sig.Params().At(0).Type().Underlying().(*types.Signature)
+	if !ok || yield.Results().Len() != 1 {
+		return false
+	}
+	basic, ok := yield.Results().At(0).Type().Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Bool
+}
+
+// iteratorCall returns the implicit call of the iterator made by a range-over-func loop
+// ("for x := range s.All" calls s.All with the loop body as the yield callback),
+// or nil if the ranged expression is not an iterator.
+// The iterator runs while the locks held by the loop are still held.
+func iteratorCall(expr ast.Expr, info *types.Info) *ast.CallExpr {
+	if info == nil || !isIterator(info.TypeOf(expr)) {
+		return nil
+	}
+	return &ast.CallExpr{Fun: expr, Lparen: expr.End(), Rparen: expr.End()}
+}
+
+// IteratorIndex holds the lock scopes of the iterators returned by package functions
+// (e.g., "func (s *Store) All() iter.Seq[int]" returning a func literal locking s.mu)
+// and the functions ranging over the results of such calls, so that ranging over
+// an iterator is checked like calling it.
+type IteratorIndex struct {
+	scopes map[FQN]*LockTracker // function -> lock scopes of the iterators it returns
+	ranges map[FQN][]FQN        // function -> functions whose iterators it ranges over
+}
+
+// BuildIteratorIndex collects the iterators returned by the functions and the range loops over them.
+func BuildIteratorIndex(funcs []*ast.FuncDecl, info *types.Info) *IteratorIndex {
+	idx := &IteratorIndex{
+		scopes: make(map[FQN]*LockTracker),
+		ranges: make(map[FQN][]FQN),
+	}
+
+	for _, decl := range funcs {
+		obj, ok := info.Defs[decl.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		fqn := FromFunc(obj)
+
+		if sig := obj.Type().(*types.Signature); sig.Results().Len() == 1 && isIterator(sig.Results().At(0).Type()) {
+			tracker := NewLockTrackerWithInfo(info)
+			for _, lit := range returnedFuncLits(decl.Body) {
+				iterTracker := NewLockTrackerWithInfo(info)
+				for _, stmt := range lit.Body.List {
+					iterTracker.Track(stmt)
+				}
+				iterTracker.EndBlock()
+				tracker.finished = append(tracker.finished, iterTracker.Scopes()...)
+			}
+			if tracker.HasScopes() {
+				idx.scopes[fqn] = tracker
+			}
+		}
+
+		// Like calls, only the loops at the top level of the body are recorded
+		for _, stmt := range decl.Body.List {
+			loop, ok := stmt.(*ast.RangeStmt)
+			if !ok || iteratorCall(loop.X, info) == nil {
+				continue
+			}
+			if call, ok := ast.Unparen(loop.X).(*ast.CallExpr); ok {
+				if pkg, name, ok := GetCallInfo(call, info); ok {
+					idx.ranges[fqn] = append(idx.ranges[fqn], FromCallInfo(pkg, name))
+				}
+			}
+		}
+	}
+
+	return idx
+}
+
+// returnedFuncLits returns the func literals returned by the function body
+// (not by the nested func literals).
+func returnedFuncLits(body *ast.BlockStmt) []*ast.FuncLit {
+	var lits []*ast.FuncLit
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if lit, ok := ast.Unparen(result).(*ast.FuncLit); ok {
+					lits = append(lits, lit)
+				}
+			}
+		}
+		return true
+	})
+	return lits
+}
+
+// LockSite returns the lock of the scope's mutex acquired by the iterators the function returns.
+func (idx *IteratorIndex) LockSite(fqn FQN, scope *MutexScope) *LockSite {
+	tracker, ok := idx.scopes[fqn]
+	if !ok {
+		return nil
+	}
+	for _, s := range tracker.Scopes() {
+		if s.HasSameSelector(scope) {
+			return &LockSite{FQN: fqn, Pos: s.Pos(), Write: !s.IsRead()}
+		}
+	}
+	return nil
+}
+
+// Ranges returns the functions whose returned iterators the function ranges over.
+func (idx *IteratorIndex) Ranges(fqn FQN) []FQN {
+	return idx.ranges[fqn]
+}
+
+func (a *Analyzer) iteratorIndex() *IteratorIndex {
+	if a.iterators == nil {
+		a.iterators = BuildIteratorIndex(a.funcs, a.info)
+	}
+	return a.iterators
+}
+
+// checkIteratorLock checks if ranging over the iterator returned by a call
+// (for x := range s.All()) acquires the mutex held by the scope.
+// The call is the implicit call of the iterator (see iteratorCall).
+func (a *Analyzer) checkIteratorLock(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
+	inner, ok := ast.Unparen(call.Fun).(*ast.CallExpr)
+	if !ok || a.isCallOnDifferentReceiver(inner, scope) {
+		return
+	}
+	pkg, name, ok := GetCallInfo(inner, a.info)
+	if !ok {
+		return
+	}
+	if site := a.iteratorIndex().LockSite(FromCallInfo(pkg, name), scope); site != nil {
+		a.recordErrorVia(currentFQN, scope, call, site)
+	}
+}
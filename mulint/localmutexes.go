@@ -0,0 +1,117 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+)
+
+// checkLocalMutexes reports the mutex fields locked (and unlocked) within a single function only,
+// which guard no fields accessed by the other functions: the mutex then only serializes the calls
+// of the function, so it may not need to be a struct field, or the other accesses to the state
+// it's meant to guard miss the lock.
+// Exported fields (lockable by other packages) and mutexes having their address taken
+// (sync.NewCond(&s.mu), lockers) aren't considered.
+func (a *Analyzer) checkLocalMutexes() {
+	escaped := a.addressedMutexes()
+	keys := a.lockKeys()
+
+	users := make(map[string][]FQN) // functions locking or unlocking the mutex
+	firstLocks := make(map[string]token.Pos)
+	fields := make(map[FQN][]string) // fields accessed by the functions
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if fn.Body == nil || fqn == "" {
+			continue
+		}
+		fields[fqn] = append(fields[fqn], fieldsIn(fn.Body, a.info)...)
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			subject := SubjectForCall(call, lockMethods)
+			locks := subject != nil
+			if !locks {
+				subject = SubjectForCall(call, unlockMethods)
+			}
+			key, ok := a.localMutexKey(subject)
+			if !ok {
+				return true
+			}
+			if !slices.Contains(users[key], fqn) {
+				users[key] = append(users[key], fqn)
+			}
+			if prev, seen := firstLocks[key]; locks && (!seen || call.Pos() < prev) {
+				firstLocks[key] = call.Pos()
+			}
+			return true
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(users)) {
+		pos, locked := firstLocks[key]
+		if len(users[key]) != 1 || !locked || escaped[key] {
+			continue
+		}
+		fqn := users[key][0]
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		guarded := a.fieldsGuardedIn(fqn, key, keys)
+		shared := slices.ContainsFunc(guarded, func(field string) bool {
+			for other, accessed := range fields {
+				if other != fqn && slices.Contains(accessed, field) {
+					return true
+				}
+			}
+			return false
+		})
+		if shared {
+			continue
+		}
+		a.diagnostics = append(a.diagnostics, NewLocalMutexError(NewLocation(pos), key, fqn, guarded))
+	}
+}
+
+// localMutexKey returns the typed key of the mutex if it's an unexported field of a type
+// declared in the package.
+func (a *Analyzer) localMutexKey(subject ast.Expr) (string, bool) {
+	if subject == nil || !IsMutexType(subject, a.info) {
+		return "", false
+	}
+	sel, ok := ast.Unparen(subject).(*ast.SelectorExpr)
+	if !ok || ast.IsExported(sel.Sel.Name) || packageVar(sel, a.info) != nil {
+		return "", false
+	}
+	selection, ok := a.info.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal || selection.Obj().Pkg() != a.pass.Pkg {
+		return "", false
+	}
+	return typedMutexKey(subject, a.info), true
+}
+
+// fieldsGuardedIn returns the fields the function accesses while holding the mutex, sorted;
+// keys are the typed keys of the locked mutexes by the position of the locks (see lockKeys).
+func (a *Analyzer) fieldsGuardedIn(fqn FQN, mutex string, keys map[token.Pos]string) []string {
+	tracker, ok := a.scopes[fqn]
+	if !ok {
+		return nil
+	}
+	guarded := make(map[string]bool)
+	for _, scope := range tracker.Scopes() {
+		if keys[scope.Pos()] != mutex {
+			continue
+		}
+		for _, node := range scope.Nodes() {
+			for _, field := range fieldsIn(node, a.info) {
+				guarded[field] = true
+			}
+		}
+	}
+	return sortedKeys(guarded)
+}
@@ -0,0 +1,246 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+)
+
+// checkLockerArgs checks if a call passes the held mutex (&s.mu) to a function
+// locking it through the parameter (mu.Lock()), directly or by passing it further.
+// Generic functions are followed whatever the type arguments (With[int](&s.mu, f)).
+func (a *Analyzer) checkLockerArgs(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
+	pkg, name, ok := GetCallInfo(call, a.info)
+	if !ok {
+		return
+	}
+
+	for i, arg := range call.Args {
+		if lockerArgSelector(arg, a.info) != scope.Selector() {
+			continue
+		}
+		if site := a.paramLockSite(FromCallInfo(pkg, name), i, make(map[FQN]bool)); site != nil {
+			a.recordErrorVia(currentFQN, scope, call, site)
+			return
+		}
+	}
+}
+
+// lockerArgSelector returns the selector of the mutex passed as an argument:
+// &s.mu, or s.mu for pointer fields.
+func lockerArgSelector(arg ast.Expr, info *types.Info) string {
+	arg = ast.Unparen(arg)
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		if IsMutexType(unary.X, info) {
+			return LockSelector(unary.X, info)
+		}
+		return ""
+	}
+	if _, isPtr := info.TypeOf(arg).(*types.Pointer); isPtr && IsMutexType(arg, info) {
+		return LockSelector(arg, info)
+	}
+	return ""
+}
+
+// lockerArgMutex returns the mutex expression of a mutex argument (s.mu for &s.mu).
+func lockerArgMutex(arg ast.Expr) ast.Expr {
+	arg = ast.Unparen(arg)
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		return ast.Unparen(unary.X)
+	}
+	return arg
+}
+
+// paramLockSite returns the lock acquired by the function through its parameter at the index
+// (a mutex pointer or a sync.Locker), if any.
+func (a *Analyzer) paramLockSite(fqn FQN, index int, visited map[FQN]bool) *LockSite {
+	if visited[fqn] {
+		return nil
+	}
+	visited[fqn] = true
+
+	decl := a.funcDecl(fqn)
+	if decl == nil {
+		return nil
+	}
+	param := paramAt(decl, index, a.info)
+	if param == nil {
+		return nil
+	}
+
+	var site *LockSite
+	inspectScopeCalls(decl.Body, a.info, func(call *ast.CallExpr) {
+		if site != nil {
+			return
+		}
+		if subject, ok := ast.Unparen(SubjectForCall(call, lockMethods)).(*ast.Ident); ok && a.info.Uses[subject] == param {
+			site = &LockSite{FQN: fqn, Pos: call.Pos(), Write: isWriteLockCall(call)}
+			return
+		}
+
+		// The parameter is passed further
+		pkg, name, ok := GetCallInfo(call, a.info)
+		if !ok {
+			return
+		}
+		for i, arg := range call.Args {
+			if argIdent, ok := ast.Unparen(arg).(*ast.Ident); ok && a.info.Uses[argIdent] == param {
+				if site = a.paramLockSite(FromCallInfo(pkg, name), i, visited); site != nil {
+					return
+				}
+			}
+		}
+	})
+	return site
+}
+
+// paramAt returns the parameter of the function at the index.
+func paramAt(decl *ast.FuncDecl, index int, info *types.Info) types.Object {
+	i := 0
+	for _, field := range decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			i++
+			continue
+		}
+		for _, name := range field.Names {
+			if i == index {
+				return info.Defs[name]
+			}
+			i++
+		}
+	}
+	return nil
+}
+
+// funcDecl returns the declaration of the package function, if any.
+func (a *Analyzer) funcDecl(fqn FQN) *ast.FuncDecl {
+	return a.summaryOf(fqn).Decl
+}
+
+// LockedCallback is a function parameter a lock helper calls while holding the lock it acquires
+// through a mutex parameter, e.g. f in
+//
+//	func With[T any](mu *sync.Mutex, f func() T) T {
+//	    mu.Lock()
+//	    defer mu.Unlock()
+//	    return f()
+//	}
+type LockedCallback struct {
+	Locker   int       // index of the mutex (or sync.Locker) parameter
+	Callback int       // index of the function parameter
+	LockPos  token.Pos // position of the lock inside the helper
+	Read     bool      // whether the read lock is held
+}
+
+// lockedCallbacks returns the function parameters called while holding the lock acquired through
+// a mutex parameter. Only the locks and unlocks made by the top-level statements of the body
+// are followed; deferred unlocks hold the lock up to the end.
+func lockedCallbacks(decl *ast.FuncDecl, info *types.Info) []LockedCallback {
+	callbacks := callbackParams(decl, info)
+	if len(callbacks) == 0 {
+		return nil
+	}
+	params := make(map[types.Object]int)
+	for i := range decl.Type.Params.NumFields() {
+		if param := paramAt(decl, i, info); param != nil {
+			params[param] = i
+		}
+	}
+
+	var locked []LockedCallback
+	held := make(map[int]LockedCallback) // by the locker parameter
+	paramOf := func(subject ast.Expr) (int, bool) {
+		ident, ok := ast.Unparen(subject).(*ast.Ident)
+		if !ok {
+			return 0, false
+		}
+		i, ok := params[info.Uses[ident]]
+		return i, ok
+	}
+	for _, stmt := range decl.Body.List {
+		if i, ok := paramOf(subjectForLockCall(stmt)); ok {
+			held[i] = LockedCallback{Locker: i, LockPos: stmt.Pos(), Read: isReadLockCall(stmt)}
+			continue
+		}
+		if i, ok := paramOf(subjectForUnlockCall(stmt)); ok {
+			delete(held, i)
+			continue
+		}
+		if len(held) == 0 {
+			continue
+		}
+		inspectScopeCalls(stmt, info, func(call *ast.CallExpr) {
+			i, ok := paramOf(call.Fun)
+			if !ok || !slices.Contains(callbacks, i) {
+				return
+			}
+			for _, lock := range held {
+				lock.Callback = i
+				if !slices.Contains(locked, lock) {
+					locked = append(locked, lock)
+				}
+			}
+		})
+	}
+	return locked
+}
+
+// checkLockHelperCalls analyzes the callbacks passed to lock helpers (see LockedCallback) as running
+// under the lock of the mutex passed along (With(&s.mu, func() int { ... })), the way the callbacks
+// of the known synchronous callback-takers are analyzed under the held locks: acquiring the mutex
+// within the callback (directly or via the functions it calls) deadlocks.
+func (a *Analyzer) checkLockHelperCalls() {
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if fn.Body == nil || !a.isLive(fqn) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			pkg, name, ok := GetCallInfo(call, a.info)
+			if !ok {
+				return true
+			}
+			helper := FromCallInfo(pkg, name)
+			for _, locked := range a.summaryOf(helper).LockedCallbacks {
+				if locked.Locker >= len(call.Args) || locked.Callback >= len(call.Args) {
+					continue
+				}
+				selector := lockerArgSelector(call.Args[locked.Locker], a.info)
+				if selector == "" {
+					continue
+				}
+				scope := NewMutexScopeWithWrapper(selector, call.Pos(), &WrapperInfo{FQN: helper, LockPos: locked.LockPos, Read: locked.Read})
+				scope.global = packageVar(lockerArgMutex(call.Args[locked.Locker]), a.info) != nil
+				a.checkLockedCallback(scope, call, call.Args[locked.Callback], fqn)
+			}
+			return true
+		})
+	}
+}
+
+// checkLockedCallback checks the callback passed to the lock helper call under the scope of the lock
+// the helper acquires: func literals are analyzed in place, while function values must not acquire
+// the mutex (directly or transitively).
+func (a *Analyzer) checkLockedCallback(scope *MutexScope, call *ast.CallExpr, callback ast.Expr, currentFQN FQN) {
+	if funcLit, ok := ast.Unparen(callback).(*ast.FuncLit); ok {
+		a.checkNodeForReentrantLock(funcLit.Body, scope, currentFQN)
+		return
+	}
+	if !a.callbackLocks(ast.Unparen(callback), scope.Key(currentFQN)) {
+		return
+	}
+
+	// The method value is called the way a method call is (see calleeScope)
+	var site *LockSite
+	called := &ast.CallExpr{Fun: ast.Unparen(callback)}
+	if obj, ok := a.info.ObjectOf(calleeIdent(called)).(*types.Func); ok {
+		site = a.findTransitiveLock(FromFunc(obj), a.calleeScope(called, scope), make(map[FQN]*LockSite))
+	}
+	a.recordErrorVia(currentFQN, scope, call, site)
+}
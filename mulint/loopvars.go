@@ -0,0 +1,132 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// checkLoopVarLocks detects goroutines locking the mutex of a loop variable they capture
+// in files compiled with the pre-Go 1.22 semantics, where the variable is shared by all iterations:
+//
+//	for _, item := range items {
+//		go func() { item.mu.Lock() }() // locks the mutex of whatever item holds by then
+//	}
+func (a *Analyzer) checkLoopVarLocks() {
+	reported := make(map[token.Pos]bool)
+
+	for _, fn := range a.funcs {
+		if fn.Body == nil || !a.isLive(a.declFQN(fn)) || a.goVersionAtLeast(fn.Pos(), goPerIterationLoopVars) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			var body *ast.BlockStmt
+			var vars []*types.Var
+			switch loop := n.(type) {
+			case *ast.RangeStmt:
+				body, vars = loop.Body, a.rangeLoopVars(loop)
+			case *ast.ForStmt:
+				body, vars = loop.Body, a.forLoopVars(loop)
+			}
+			if len(vars) == 0 {
+				return true
+			}
+
+			ast.Inspect(body, func(inner ast.Node) bool {
+				stmt, ok := inner.(*ast.GoStmt)
+				if !ok {
+					return true
+				}
+				lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit)
+				if !ok {
+					return true
+				}
+				for _, lock := range a.capturedLoopVarLocks(lit, vars) {
+					if reported[lock.call.Pos()] {
+						continue
+					}
+					reported[lock.call.Pos()] = true
+					a.diagnostics = append(a.diagnostics, NewLoopVarLockError(
+						NewLocation(lock.call.Pos()),
+						NewLocation(lock.loopVar.Pos()),
+						lock.loopVar.Name(),
+					))
+				}
+				return true
+			})
+			return true
+		})
+	}
+}
+
+// loopVarLock is a lock acquired on the mutex of a loop variable.
+type loopVarLock struct {
+	call    *ast.CallExpr
+	loopVar *types.Var
+}
+
+// capturedLoopVarLocks returns the locks the func literal acquires on mutexes of the loop variables.
+func (a *Analyzer) capturedLoopVarLocks(lit *ast.FuncLit, vars []*types.Var) []loopVarLock {
+	var locks []loopVarLock
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		subject := SubjectForCall(call, lockMethods)
+		if subject == nil || !IsMutexType(subject, a.info) {
+			return true
+		}
+
+		var root *ast.Ident
+		switch e := ast.Unparen(subject).(type) {
+		case *ast.Ident:
+			root = e
+		case *ast.SelectorExpr:
+			root = RootSelector(e)
+		}
+		if root == nil {
+			return true
+		}
+		for _, v := range vars {
+			if a.info.Uses[root] == v {
+				locks = append(locks, loopVarLock{call: call, loopVar: v})
+			}
+		}
+		return true
+	})
+	return locks
+}
+
+// rangeLoopVars returns the variables declared by a range loop (for k, v := range ...).
+func (a *Analyzer) rangeLoopVars(loop *ast.RangeStmt) []*types.Var {
+	if loop.Tok != token.DEFINE {
+		return nil
+	}
+	return a.definedVars(loop.Key, loop.Value)
+}
+
+// forLoopVars returns the variables declared by the init statement of a for loop (for i := 0; ...).
+func (a *Analyzer) forLoopVars(loop *ast.ForStmt) []*types.Var {
+	init, ok := loop.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE {
+		return nil
+	}
+	return a.definedVars(init.Lhs...)
+}
+
+// definedVars returns the variables defined by the identifiers.
+func (a *Analyzer) definedVars(exprs ...ast.Expr) []*types.Var {
+	var vars []*types.Var
+	for _, expr := range exprs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if v, ok := a.info.Defs[ident].(*types.Var); ok {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
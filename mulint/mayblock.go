@@ -0,0 +1,87 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+)
+
+// MayBlock is the fact of the functions that may block: the ones calling blocking functions
+// (see isBlockingFunc), performing channel operations that may block (except for the standard library),
+// or calling other functions that may block, including the ones of the imported packages. It's computed the same way
+// in every profile and exported for the package functions, so the blocking calls under lock
+// are found through the functions of the dependencies, too.
+type MayBlock struct {
+	Via      FQN  // the blocking function reached (the function itself if it blocks on channel operations)
+	Declared bool // Via is declared as blocking (see EffectBlocks), so calls are reported in every profile
+}
+
+func (*MayBlock) AFact() {}
+
+func (f *MayBlock) String() string {
+	return "mayBlock(" + string(f.Via) + ")"
+}
+
+// importedMayBlock returns the MayBlock fact of a function of the imported packages, if any.
+func (a *Analyzer) importedMayBlock(fqn FQN) *MayBlock {
+	if a.blockingFacts == nil {
+		a.blockingFacts = make(map[FQN]*MayBlock)
+		a.declaredBlocking = make(map[FQN]bool)
+		for _, f := range a.pass.AllObjectFacts() {
+			fn, ok := f.Object.(*types.Func)
+			fact, isMayBlock := f.Fact.(*MayBlock)
+			if ok && isMayBlock && fn.Pkg() != a.pass.Pkg {
+				a.blockingFacts[FromFunc(fn)] = fact
+				if fact.Declared {
+					a.declaredBlocking[fact.Via] = true
+				}
+			}
+		}
+	}
+	return a.blockingFacts[fqn]
+}
+
+// isDeclaredBlocking checks if the function is declared as blocking, either in the package
+// (see externs) or in the imported one it belongs to (see MayBlock.Declared).
+func (a *Analyzer) isDeclaredBlocking(fqn FQN) bool {
+	a.importedMayBlock(fqn)
+	return a.externs().Has(fqn, EffectBlocks) || a.declaredBlocking[fqn]
+}
+
+// blockingOp returns the kind of the first channel operation of the function that may block, if any.
+// The standard library is trusted: its internal channel operations (e.g., the ones of the runtime
+// reached by fmt.Errorf) aren't considered, only the blocking functions (see isBlockingFunc).
+func (a *Analyzer) blockingOp(decl *ast.FuncDecl) string {
+	if decl == nil || decl.Body == nil || a.std {
+		return ""
+	}
+	op := ""
+	inspectBlockingOps(decl.Body, a.info, func(_ ast.Node, _ ast.Node, kind string) {
+		if op == "" {
+			op = kind
+		}
+	})
+	return op
+}
+
+// exportBlockingFacts exports the MayBlock facts of the package functions. Without the strict
+// profile, only the calls reaching functions declared as blocking are reported (see findBlockingCall),
+// so the facts of the other functions are only exported if the profile is enabled for any package.
+func (a *Analyzer) exportBlockingFacts() {
+	if a.pass.ExportObjectFact == nil {
+		return
+	}
+	strict := a.config.Strict || a.config.StrictPackages != ""
+	for _, decl := range a.funcs {
+		fn, ok := a.info.Defs[decl.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		blocking := a.blockingCalls(a.declFQN(decl))
+		if i := slices.IndexFunc(blocking, a.isDeclaredBlocking); i >= 0 {
+			a.pass.ExportObjectFact(fn, &MayBlock{Via: blocking[i], Declared: true})
+		} else if strict && len(blocking) > 0 {
+			a.pass.ExportObjectFact(fn, &MayBlock{Via: blocking[0]})
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+)
+
+// lockMetrics are the usage metrics of a mutex (see reportLockMetrics).
+type lockMetrics struct {
+	mutex   string
+	first   *MutexScope // the first lock of the mutex
+	funcs   int         // the number of distinct functions acquiring the mutex
+	maxHeld int         // the maximum number of mutexes held when acquiring it (including itself)
+}
+
+// reportLockMetrics reports, for each mutex, the number of distinct functions acquiring it (the fan-in)
+// and the maximum held set observed when acquiring it: the mutexes held by the enclosing scopes
+// and by the callers along the call graph (see heldAtEntry). Mutexes with the fan-in reaching
+// the -lock-metrics-fanin threshold are flagged as refactoring candidates.
+func (a *Analyzer) reportLockMetrics() {
+	keys := a.lockKeys()
+	keyOf := func(fqn FQN, scope *MutexScope) string {
+		if key, ok := keys[scope.Pos()]; ok {
+			return key
+		}
+		return scope.Key(fqn)
+	}
+	entry := a.heldAtEntry(keyOf)
+
+	metrics := make(map[string]*lockMetrics)
+	funcs := make(map[string]map[FQN]bool)
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if !a.isLive(fqn) {
+			continue
+		}
+		scopes := a.summaryOf(fqn).Scopes
+		for _, scope := range scopes {
+			if scope.IsFresh() {
+				continue
+			}
+			key := keyOf(fqn, scope)
+			m, ok := metrics[key]
+			if !ok {
+				m = &lockMetrics{mutex: key}
+				metrics[key] = m
+				funcs[key] = make(map[FQN]bool)
+			}
+			if m.first == nil || scope.Pos() < m.first.Pos() {
+				m.first = scope
+			}
+			funcs[key][fqn] = true
+
+			held := heldAt(fqn, scopes, scope.Pos(), entry[fqn], keyOf)
+			if !slices.Contains(held, key) {
+				held = append(held, key)
+			}
+			m.maxHeld = max(m.maxHeld, len(held))
+		}
+	}
+
+	mutexes := make([]string, 0, len(metrics))
+	for key := range metrics {
+		mutexes = append(mutexes, key)
+	}
+	slices.Sort(mutexes)
+	for _, key := range mutexes {
+		m := metrics[key]
+		m.funcs = len(funcs[key])
+		a.diagnostics = append(a.diagnostics, NewLockMetricsReport(
+			NewLocation(m.first.Pos()),
+			*m,
+			a.config.LockMetricsFanIn > 0 && m.funcs >= a.config.LockMetricsFanIn,
+		))
+	}
+}
+
+// heldAtEntry returns the largest set of mutexes held by the callers when calling each function
+// of the package, propagated along the call graph until no set grows.
+func (a *Analyzer) heldAtEntry(keyOf func(FQN, *MutexScope) string) map[FQN][]string {
+	entry := make(map[FQN][]string)
+	pkg := a.pass.Pkg.Path()
+	// Every round extends the call chains by one call, so the number of functions bounds the rounds
+	for range len(a.funcs) {
+		changed := false
+		for _, fn := range a.funcs {
+			if fn.Body == nil {
+				continue
+			}
+			fqn := a.declFQN(fn)
+			scopes := a.summaryOf(fqn).Scopes
+			inspectScopeCalls(fn.Body, a.info, func(call *ast.CallExpr) {
+				calleePkg, name, ok := GetCallInfo(call, a.info)
+				if !ok || calleePkg != pkg {
+					return
+				}
+				callee := FromCallInfo(calleePkg, name)
+				if held := heldAt(fqn, scopes, call.Pos(), entry[fqn], keyOf); len(held) > len(entry[callee]) {
+					entry[callee] = held
+					changed = true
+				}
+			})
+		}
+		if !changed {
+			break
+		}
+	}
+	return entry
+}
+
+// heldAt returns the mutexes held at the position of the function: the ones held on entry
+// and the ones of the scopes covering the position.
+func heldAt(fqn FQN, scopes []*MutexScope, pos token.Pos, entry []string, keyOf func(FQN, *MutexScope) string) []string {
+	held := slices.Clone(entry)
+	for _, scope := range scopes {
+		if scope.IsFresh() || !scopeCovers(scope, pos, pos) {
+			continue
+		}
+		if key := keyOf(fqn, scope); !slices.Contains(held, key) {
+			held = append(held, key)
+		}
+	}
+	return held
+}
@@ -0,0 +1,249 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// functionalOptions is the variadic functional options parameter of a function
+// (opts ...Option, where Option is a func(*T) applied to the options struct T).
+// Example:
+//
+//	func WithoutLocking() Option {
+//	    return func(o *options) { o.noLock = true }
+//	}
+//
+//	func (a *Some) helper(opts ...Option) {
+//	    o := options{}
+//	    for _, opt := range opts {
+//	        opt(&o)
+//	    }
+//	    if !o.noLock {
+//	        a.mu.Lock()
+//	        defer a.mu.Unlock()
+//	    }
+//	}
+type functionalOptions struct {
+	index  int        // Index of the variadic parameter
+	name   string     // Name of the parameter
+	target types.Type // The options struct type the options are applied to
+}
+
+// functionalOptions returns the variadic functional options parameter of the function, if any.
+func (r *ConditionalLockRegistry) functionalOptions(fn *ast.FuncDecl) *functionalOptions {
+	list := fn.Type.Params.List
+	if len(list) == 0 {
+		return nil
+	}
+	last := list[len(list)-1]
+	if _, ok := last.Type.(*ast.Ellipsis); !ok || len(last.Names) != 1 {
+		return nil
+	}
+
+	param := r.info.Defs[last.Names[0]]
+	if param == nil {
+		return nil
+	}
+	slice, ok := param.Type().(*types.Slice)
+	if !ok {
+		return nil
+	}
+	sig, ok := slice.Elem().Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() != 1 {
+		return nil
+	}
+	ptr, ok := sig.Params().At(0).Type().Underlying().(*types.Pointer)
+	if !ok || optionsStruct(ptr.Elem()) == nil {
+		return nil
+	}
+
+	index := 0
+	for _, field := range list[:len(list)-1] {
+		index += max(len(field.Names), 1)
+	}
+	return &functionalOptions{index: index, name: last.Names[0].Name, target: ptr.Elem()}
+}
+
+// extractOptionsCondition checks if the condition is a check of a bool field of a local options struct
+// the functional options are applied to (o.noLock). Returns the conditional lock without the selector.
+func (r *ConditionalLockRegistry) extractOptionsCondition(fn *ast.FuncDecl, cond ast.Expr, opts *functionalOptions) (ConditionalLock, bool) {
+	negated := false
+	if unary, ok := cond.(*ast.UnaryExpr); ok && unary.Op == token.NOT {
+		cond, negated = unary.X, true
+	}
+
+	sel, ok := cond.(*ast.SelectorExpr)
+	if !ok {
+		return ConditionalLock{}, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ConditionalLock{}, false
+	}
+	local, ok := r.info.Uses[ident].(*types.Var)
+	if !ok || !types.Identical(local.Type(), opts.target) {
+		return ConditionalLock{}, false
+	}
+	if basic, ok := r.info.TypeOf(sel).Underlying().(*types.Basic); !ok || basic.Kind() != types.Bool {
+		return ConditionalLock{}, false
+	}
+
+	value, ok := r.optionsDefault(fn, local, sel.Sel.Name)
+	if !ok {
+		return ConditionalLock{}, false
+	}
+	return ConditionalLock{
+		ParamIndex: opts.index,
+		ParamName:  opts.name,
+		Field:      sel.Sel.Name,
+		Variadic:   true,
+		Default:    value,
+		Negated:    negated,
+	}, true
+}
+
+// optionsDefault returns the value of the field of the local options struct before applying
+// the options: set by the struct literal it's initialized with or zero.
+func (r *ConditionalLockRegistry) optionsDefault(fn *ast.FuncDecl, local *types.Var, field string) (bool, bool) {
+	var init ast.Expr
+	found := false
+	for _, stmt := range fn.Body.List {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE || len(s.Lhs) != len(s.Rhs) {
+				continue
+			}
+			for i, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && r.info.Defs[ident] == local {
+					init, found = s.Rhs[i], true
+				}
+			}
+		case *ast.DeclStmt:
+			decl, ok := s.Decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if r.info.Defs[name] != local {
+						continue
+					}
+					found = true
+					if i < len(vs.Values) {
+						init = vs.Values[i]
+					}
+				}
+			}
+		}
+	}
+	if !found {
+		return false, false
+	}
+	if init == nil {
+		return false, true
+	}
+
+	value, ok := r.optionField(init, field)
+	if !ok {
+		return false, false
+	}
+	if value == nil {
+		return false, true // omitted fields are zero
+	}
+	return extractBoolLiteral(value)
+}
+
+// analyzeOptionSetter registers the function as a functional option constructor if it returns
+// a func literal setting bool fields of the options struct to literals (o.noLock = true).
+func (r *ConditionalLockRegistry) analyzeOptionSetter(fqn FQN, fn *ast.FuncDecl) {
+	if fn.Body == nil || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+		return
+	}
+
+	for _, stmt := range fn.Body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		lit, ok := ast.Unparen(ret.Results[0]).(*ast.FuncLit)
+		if !ok || len(lit.Type.Params.List) != 1 || len(lit.Type.Params.List[0].Names) != 1 {
+			return
+		}
+		target := r.info.Defs[lit.Type.Params.List[0].Names[0]]
+		if target == nil {
+			return
+		}
+
+		topLevel := make(map[ast.Stmt]bool, len(lit.Body.List))
+		for _, stmt := range lit.Body.List {
+			topLevel[stmt] = true
+		}
+
+		// Fields set conditionally or to non-literals make the options unknown
+		fields := make(map[string]bool)
+		known := true
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return known
+			}
+			for i, lhs := range assign.Lhs {
+				// *o = options{...}
+				if star, ok := lhs.(*ast.StarExpr); ok {
+					if ident, ok := star.X.(*ast.Ident); ok && r.info.Uses[ident] == target {
+						known = false
+						return false
+					}
+				}
+				sel, ok := lhs.(*ast.SelectorExpr)
+				if !ok {
+					continue
+				}
+				if ident, ok := sel.X.(*ast.Ident); !ok || r.info.Uses[ident] != target {
+					continue
+				}
+				value, ok := false, false
+				if topLevel[assign] && assign.Tok == token.ASSIGN && len(assign.Lhs) == len(assign.Rhs) {
+					value, ok = extractBoolLiteral(assign.Rhs[i])
+				}
+				if !ok {
+					known = false
+					return false
+				}
+				fields[sel.Sel.Name] = value
+			}
+			return known
+		})
+		if !known {
+			return
+		}
+		r.setters[fqn] = fields
+		return
+	}
+}
+
+// functionalOptionsValue returns the value of the field after applying the options passed at the call site.
+// All of them must be calls to known option constructors.
+func (r *ConditionalLockRegistry) functionalOptionsValue(args []ast.Expr, cl ConditionalLock) (bool, bool) {
+	value := cl.Default
+	for _, arg := range args {
+		call, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			return false, false
+		}
+		pkg, name, ok := GetCallInfo(call, r.info)
+		if !ok {
+			return false, false
+		}
+		fields, ok := r.setters[FromCallInfo(pkg, name)]
+		if !ok {
+			return false, false
+		}
+		if v, ok := fields[cl.Field]; ok {
+			value = v
+		}
+	}
+	return value, true
+}
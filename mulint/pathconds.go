@@ -0,0 +1,148 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// pathConditions returns the values of the bool variables implied by the conditions on the path
+// to the call within its function: the conditions of the enclosing if statements (negated within
+// the else branches) and of the preceding guard clauses leaving the block (if cached { return }).
+// Example:
+//
+//	func (s *Store) Load(key string, locked bool) {
+//	    if !locked {
+//	        s.load(key, locked) // locked is false
+//	    }
+//	}
+//
+// Variables assigned anywhere in the function (other than by their declaration) aren't considered.
+func (a *Analyzer) pathConditions(call *ast.CallExpr) map[*types.Var]bool {
+	fn, _ := enclosingFunc(a.pass, call.Pos())
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+
+	var path []ast.Node
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch {
+		case found:
+			return false
+		case n == nil:
+			path = path[:len(path)-1]
+			return false
+		case n.Pos() > call.Pos() || n.End() < call.End():
+			return false
+		}
+		path = append(path, n)
+		found = n == call
+		return !found
+	})
+	if !found {
+		return nil
+	}
+
+	values := make(map[*types.Var]bool)
+	for i := 0; i+1 < len(path); i++ {
+		child := path[i+1]
+		switch parent := path[i].(type) {
+		case *ast.IfStmt:
+			if child == parent.Body {
+				impliedValues(parent.Cond, true, a.info, values)
+			} else if child == parent.Else {
+				impliedValues(parent.Cond, false, a.info, values)
+			}
+		case *ast.BlockStmt:
+			guardValues(parent.List, child, a.info, values)
+		case *ast.CaseClause:
+			guardValues(parent.Body, child, a.info, values)
+		case *ast.CommClause:
+			guardValues(parent.Body, child, a.info, values)
+		}
+	}
+
+	for v := range reassignedVars(fn.Body, a.info) {
+		delete(values, v)
+	}
+	return values
+}
+
+// guardValues adds the values implied by the guard clauses (if statements without else
+// leaving the block) preceding the statement in the list: the statement runs only if
+// their conditions don't hold.
+func guardValues(list []ast.Stmt, stmt ast.Node, info *types.Info, values map[*types.Var]bool) {
+	for _, s := range list {
+		if s == stmt {
+			return
+		}
+		guard, ok := s.(*ast.IfStmt)
+		if ok && guard.Init == nil && guard.Else == nil && leavesBlock(guard.Body.List, info, false) {
+			impliedValues(guard.Cond, false, info, values)
+		}
+	}
+}
+
+// impliedValues adds the values of the bool variables implied by the condition having the value:
+// v, !v, and conjunctions (disjunctions) of them holding (not holding).
+func impliedValues(cond ast.Expr, value bool, info *types.Info, values map[*types.Var]bool) {
+	switch e := ast.Unparen(cond).(type) {
+	case *ast.Ident:
+		if v, ok := info.Uses[e].(*types.Var); ok && isBoolType(v.Type()) {
+			values[v] = value
+		}
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			impliedValues(e.X, !value, info, values)
+		}
+	case *ast.BinaryExpr:
+		if (e.Op == token.LAND && value) || (e.Op == token.LOR && !value) {
+			impliedValues(e.X, value, info, values)
+			impliedValues(e.Y, value, info, values)
+		}
+	}
+}
+
+// reassignedVars returns the variables assigned within the body after their declaration,
+// incremented or having their address taken.
+func reassignedVars(body *ast.BlockStmt, info *types.Info) map[*types.Var]bool {
+	vars := make(map[*types.Var]bool)
+	add := func(expr ast.Expr) {
+		if ident, ok := ast.Unparen(expr).(*ast.Ident); ok && info.Defs[ident] == nil {
+			if v, ok := info.Uses[ident].(*types.Var); ok {
+				vars[v] = true
+			}
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range s.Lhs {
+				add(lhs)
+			}
+		case *ast.RangeStmt:
+			if s.Tok == token.ASSIGN {
+				add(s.Key)
+				if s.Value != nil {
+					add(s.Value)
+				}
+			}
+		case *ast.IncDecStmt:
+			add(s.X)
+		case *ast.UnaryExpr:
+			if s.Op == token.AND {
+				add(s.X)
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// isBoolType checks if the type is a boolean one.
+func isBoolType(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
@@ -0,0 +1,315 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/cfg"
+)
+
+// unlockPath is a path from a lock to a function exit not releasing the lock.
+type unlockPath struct {
+	exit       token.Pos
+	conditions []string // the branch conditions taken along the path
+}
+
+// checkUnlockPaths verifies with the control flow graph that every path from a lock
+// releases it (or defers the release) before the function exits, reporting the first path
+// that doesn't along with its branch conditions. Locks not released on any path are
+// left to the wrapper detection (functions returning with the lock held on purpose).
+// Returns already reported by checkMissingUnlocks aren't reported again.
+func (a *Analyzer) checkUnlockPaths() {
+	for _, fn := range a.funcs {
+		if fn.Body == nil || !a.isLive(a.declFQN(fn)) {
+			continue
+		}
+
+		graph := cfg.New(fn.Body, a.mayReturn)
+		for _, block := range graph.Blocks {
+			if !block.Live {
+				continue
+			}
+			for i, node := range block.Nodes {
+				subject := subjectForLockCall(node)
+				if subject == nil || !IsMutexType(subject, a.info) {
+					continue
+				}
+
+				path, released := a.findUnlockPath(fn.Body, block, i, LockSelector(subject, a.info))
+				if path == nil || !released || a.reported[path.exit] {
+					continue
+				}
+				a.reported[path.exit] = true
+				a.diagnostics = append(a.diagnostics, NewMissingUnlockErrorOnPath(
+					NewLocation(node.Pos()),
+					NewLocation(path.exit),
+					path.conditions,
+				))
+			}
+		}
+	}
+}
+
+// findUnlockPath searches for a path from the lock (the node at index of the block) to a function
+// exit without releasing the mutex. It also reports whether some path does release it.
+// Paths acquiring the same lock again end there (these are reported as reentrant locks).
+// The values of the local bool flags (see boolFlags) are tracked along the paths: branches
+// contradicting them aren't taken, and unlocks deferred under a condition on them
+// (defer func() { if !unlocked { s.mu.Unlock() } }()) release the mutex unless the condition
+// is known not to hold on exit.
+func (a *Analyzer) findUnlockPath(body *ast.BlockStmt, start *cfg.Block, index int, selector string) (*unlockPath, bool) {
+	type visit struct {
+		block *cfg.Block
+		state string // the flag values and the number of guarded deferred unlocks
+	}
+	visited := make(map[visit]bool)
+	released := false
+	var found *unlockPath
+
+	flags := boolFlags(body, a.info)
+	order := sortedFlags(flags)
+	initial := make(flagValues)
+	for _, node := range start.Nodes[:index] {
+		initial.assign(node, flags, a.info)
+	}
+
+	var walk func(block *cfg.Block, from int, conditions []string, values flagValues, guards []ast.Expr)
+	walk = func(block *cfg.Block, from int, conditions []string, values flagValues, guards []ast.Expr) {
+		values = values.clone()
+		for _, node := range block.Nodes[from:] {
+			switch a.lockEffect(node, selector) {
+			case lockEffectRelease:
+				released = true
+				return
+			case lockEffectAcquire:
+				return
+			}
+			if subject, guard := guardedDeferUnlock(node); subject != nil && IsMutexType(subject, a.info) && LockSelector(subject, a.info) == selector {
+				guards = append(guards[:len(guards):len(guards)], guard)
+			}
+			values.assign(node, flags, a.info)
+		}
+
+		if len(block.Succs) == 0 {
+			for _, guard := range guards {
+				if value, known := values.eval(guard, a.info); value || !known {
+					released = true
+					return
+				}
+			}
+			if found == nil && !a.endsWithNoReturn(block) {
+				found = &unlockPath{exit: exitPos(body, block), conditions: conditions}
+			}
+			return
+		}
+		for _, succ := range block.Succs {
+			next := values
+			if cond, value, ok := ifEdge(block, succ); ok && testsFlags(cond, flags, a.info) {
+				if v, known := values.eval(cond, a.info); known && v != value {
+					continue
+				}
+				next = values.clone()
+				next.assume(cond, value, flags, a.info)
+			}
+			key := visit{block: succ, state: next.key(order) + "/" + strconv.Itoa(len(guards))}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+			nextConditions := conditions
+			if cond := branchCondition(block, succ); cond != "" {
+				nextConditions = append(nextConditions[:len(nextConditions):len(nextConditions)], cond)
+			}
+			walk(succ, 0, nextConditions, next, guards)
+		}
+	}
+	walk(start, index+1, nil, initial, nil)
+
+	return found, released
+}
+
+// Effects of a node on the lock state of a mutex along a path.
+const (
+	lockEffectNone = iota
+	lockEffectAcquire
+	lockEffectRelease // unlocks or defers the unlock
+)
+
+// lockEffect returns the effect of the node on the lock of the mutex with the selector,
+// considering direct calls and calls to lock and unlock wrappers.
+func (a *Analyzer) lockEffect(node ast.Node, selector string) int {
+	sameMutex := func(e ast.Expr) bool {
+		return e != nil && IsMutexType(e, a.info) && LockSelector(e, a.info) == selector
+	}
+	if sameMutex(subjectForLockCall(node)) {
+		return lockEffectAcquire
+	}
+	if sameMutex(subjectForUnlockCall(node)) || sameMutex(subjectForDeferUnlockCall(node)) {
+		return lockEffectRelease
+	}
+
+	calls := CallExprs(node)
+	deferStmt, isDefer := node.(*ast.DeferStmt)
+	if isDefer {
+		calls = []*ast.CallExpr{deferStmt.Call}
+	}
+	for _, call := range calls {
+		if s, _, ok := wrapperCallSelector(call, a.wrappers, a.info, WrapperUnlock); ok && s == selector {
+			return lockEffectRelease
+		}
+		if !isDefer {
+			if s, _, ok := wrapperCallSelector(call, a.wrappers, a.info, WrapperLock); ok && s == selector {
+				return lockEffectAcquire
+			}
+		}
+	}
+	return lockEffectNone
+}
+
+// endsWithNoReturn checks if the block ends with a call that never returns (e.g., panic).
+func (a *Analyzer) endsWithNoReturn(block *cfg.Block) bool {
+	if len(block.Nodes) == 0 {
+		return false
+	}
+	stmt, ok := block.Nodes[len(block.Nodes)-1].(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := stmt.X.(*ast.CallExpr)
+	return ok && !a.mayReturn(call)
+}
+
+// exitPos returns the position a path leaves the function at:
+// the return statement ending the block or the closing brace of the body.
+func exitPos(body *ast.BlockStmt, block *cfg.Block) token.Pos {
+	if len(block.Nodes) > 0 {
+		if ret, ok := block.Nodes[len(block.Nodes)-1].(*ast.ReturnStmt); ok {
+			return ret.Pos()
+		}
+	}
+	return body.Rbrace
+}
+
+// branchCondition describes the condition of taking the edge from the block to its successor,
+// if it's a branch of an if or a switch statement (e.g., "err != nil" or "case 1, 2").
+func branchCondition(from, to *cfg.Block) string {
+	if cond, value, ok := ifEdge(from, to); ok {
+		if value {
+			return StrExpr(cond)
+		}
+		return negateCondition(cond)
+	}
+	if to.Kind == cfg.KindSwitchCaseBody {
+		clause := to.Stmt.(*ast.CaseClause)
+		if len(clause.List) == 0 {
+			return "default case"
+		}
+		cases := make([]string, len(clause.List))
+		for i, expr := range clause.List {
+			cases[i] = StrExpr(expr)
+		}
+		return "case " + strings.Join(cases, ", ")
+	}
+	return ""
+}
+
+// ifEdge returns the condition of the if statement the edge from the block to its successor
+// branches on, along with the value of the condition when the edge is taken.
+func ifEdge(from, to *cfg.Block) (ast.Expr, bool, bool) {
+	switch to.Kind {
+	case cfg.KindIfThen:
+		return to.Stmt.(*ast.IfStmt).Cond, true, true
+	case cfg.KindIfElse:
+		return to.Stmt.(*ast.IfStmt).Cond, false, true
+	case cfg.KindIfDone:
+		// The false branch of an if without else
+		if len(from.Succs) == 2 && from.Succs[0].Kind == cfg.KindIfThen && from.Succs[1] == to {
+			return from.Succs[0].Stmt.(*ast.IfStmt).Cond, false, true
+		}
+	}
+	return nil, false, false
+}
+
+// negatedOps maps comparison operators to their negations.
+var negatedOps = map[token.Token]token.Token{
+	token.EQL: token.NEQ,
+	token.NEQ: token.EQL,
+	token.LSS: token.GEQ,
+	token.GEQ: token.LSS,
+	token.GTR: token.LEQ,
+	token.LEQ: token.GTR,
+}
+
+// negateCondition renders the negation of the condition ("err == nil" for "err != nil", "!ok" for "ok").
+func negateCondition(cond ast.Expr) string {
+	switch e := ast.Unparen(cond).(type) {
+	case *ast.BinaryExpr:
+		if op, ok := negatedOps[e.Op]; ok {
+			return StrExpr(&ast.BinaryExpr{X: e.X, Op: op, Y: e.Y})
+		}
+		return "!(" + StrExpr(e) + ")"
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return StrExpr(e.X)
+		}
+	}
+	return "!" + StrExpr(cond)
+}
+
+// noReturnFuncs are the functions that never return to the caller.
+var noReturnFuncs = map[FQN]bool{
+	"os.Exit":        true,
+	"log.Fatal":      true,
+	"log.Fatalf":     true,
+	"log.Fatalln":    true,
+	"log.Panic":      true,
+	"log.Panicf":     true,
+	"log.Panicln":    true,
+	"runtime.Goexit": true,
+}
+
+// mayReturn reports whether the call may return, for building control flow graphs:
+// calls to panic and the well-known exiting functions don't.
+func (a *Analyzer) mayReturn(call *ast.CallExpr) bool {
+	return !isNoReturnCall(call, a.info)
+}
+
+// isNoReturnCall checks if the call is made to panic or a well-known exiting function.
+func isNoReturnCall(call *ast.CallExpr, info *types.Info) bool {
+	if info == nil {
+		return false
+	}
+	if ident, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+		if _, isBuiltin := info.Uses[ident].(*types.Builtin); isBuiltin && ident.Name == "panic" {
+			return true
+		}
+	}
+	pkg, name, ok := GetCallInfo(call, info)
+	return ok && noReturnFuncs[FromCallInfo(pkg, name)]
+}
+
+// leavesBlock checks if the statements end with leaving the enclosing block for good:
+// returning, jumping elsewhere or calling a function that never returns.
+// An unlabeled break leaves the innermost loop, switch or select, so it's considered
+// to reach the end of a switch or select case (see inCase).
+func leavesBlock(stmts []ast.Stmt, info *types.Info, inCase bool) bool {
+	if len(stmts) == 0 {
+		return false
+	}
+	switch s := stmts[len(stmts)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		if s.Tok == token.BREAK && s.Label == nil {
+			return !inCase
+		}
+		return s.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		return ok && isNoReturnCall(call, info)
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+)
+
+// PoolRegistry tracks the New functions of sync.Pool values, so that Get calls
+// can be analyzed as synchronously invoking New under the caller's held locks.
+// Pools are identified by their struct field ("Server.bufs") or package variable name.
+type PoolRegistry struct {
+	news map[string]ast.Expr
+	info *types.Info
+}
+
+func NewPoolRegistry(info *types.Info) *PoolRegistry {
+	return &PoolRegistry{
+		news: make(map[string]ast.Expr),
+		info: info,
+	}
+}
+
+// Collect records sync.Pool initializations found in the node:
+//
+//	var pool = sync.Pool{New: newBuffer}
+//	s := &Server{bufs: sync.Pool{New: func() any { ... }}}
+//	s.bufs.New = s.newBuffer
+func (r *PoolRegistry) Collect(node ast.Node) {
+	forEachInit(node, r.info, func(target ast.Expr, key string, value ast.Expr) {
+		// pool.New = fn
+		if sel, ok := target.(*ast.SelectorExpr); ok && sel.Sel.Name == "New" && r.isPool(sel.X) {
+			if poolKey := r.poolKey(sel.X); poolKey != "" {
+				r.news[poolKey] = value
+			}
+			return
+		}
+		if newFn := poolNewFunc(value, r.info); newFn != nil && key != "" {
+			r.news[key] = newFn
+		}
+	})
+}
+
+// NewFunc returns the New function of the pool referenced by the expression, if known.
+func (r *PoolRegistry) NewFunc(pool ast.Expr) (ast.Expr, bool) {
+	key := r.poolKey(pool)
+	if key == "" {
+		return nil, false
+	}
+	newFn, ok := r.news[key]
+	return newFn, ok
+}
+
+// PoolForGet returns the pool expression if the call is a Get() on a sync.Pool.
+func (r *PoolRegistry) PoolForGet(call *ast.CallExpr) ast.Expr {
+	selector := SelectorExpr(call)
+	if selector == nil || selector.Sel.Name != "Get" || !r.isPool(selector.X) {
+		return nil
+	}
+	return selector.X
+}
+
+func (r *PoolRegistry) isPool(expr ast.Expr) bool {
+	return isPoolType(r.info.TypeOf(expr))
+}
+
+// poolKey identifies a pool by its struct field or variable name.
+func (r *PoolRegistry) poolKey(expr ast.Expr) string {
+	return fieldOrVarKey(expr, r.info)
+}
+
+// poolNewFunc returns the New field value of a sync.Pool composite literal.
+func poolNewFunc(expr ast.Expr, info *types.Info) ast.Expr {
+	if unary, ok := expr.(*ast.UnaryExpr); ok {
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || !isPoolType(info.TypeOf(lit)) {
+		return nil
+	}
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "New" {
+				return kv.Value
+			}
+		}
+	}
+	return nil
+}
+
+// isPoolType checks if a type is sync.Pool or *sync.Pool.
+func isPoolType(t types.Type) bool {
+	named, ok := derefType(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "sync" && named.Obj().Name() == "Pool"
+}
+
+// derefType returns the element type for pointers and the type itself otherwise.
+func derefType(t types.Type) types.Type {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return t
+}
+
+// checkPoolGet checks if a Get() call on a sync.Pool invokes a New function
+// that acquires the mutex held by the scope.
+func (a *Analyzer) checkPoolGet(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
+	pool := a.pools.PoolForGet(call)
+	if pool == nil {
+		return
+	}
+	newFn, ok := a.pools.NewFunc(pool)
+	if !ok {
+		return
+	}
+
+	if a.callbackLocks(newFn, scope.Key(currentFQN)) {
+		a.recordError(currentFQN, scope, call)
+	}
+}
+
+// callbackLocks checks if a function expression (a func literal or a function value)
+// acquires the mutex identified by key when called.
+func (a *Analyzer) callbackLocks(fn ast.Expr, key string) bool {
+	switch f := fn.(type) {
+	case *ast.FuncLit:
+		found := false
+		inspectScopeCalls(f.Body, a.info, func(call *ast.CallExpr) {
+			if found {
+				return
+			}
+			if subject := SubjectForCall(call, lockMethods); subject != nil && IsMutexType(subject, a.info) {
+				found = typedMutexKey(subject, a.info) == key
+				return
+			}
+			if pkg, name, ok := GetCallInfo(call, a.info); ok {
+				found = slices.Contains(a.LocksReachable(FromCallInfo(pkg, name)), key)
+			}
+		})
+		return found
+	case *ast.Ident, *ast.SelectorExpr:
+		var obj types.Object
+		if sel, ok := f.(*ast.SelectorExpr); ok {
+			obj = a.info.ObjectOf(sel.Sel)
+		} else {
+			obj = a.info.ObjectOf(f.(*ast.Ident))
+		}
+		if fnObj, ok := obj.(*types.Func); ok {
+			return slices.Contains(a.LocksReachable(FromFunc(fnObj)), key)
+		}
+	}
+	return false
+}
+
+// typedMutexKey returns the mutex key for a lock subject expression,
+// using the type of the root instead of the variable name (e.g. "s.mu" -> "Server.mu").
+func typedMutexKey(subject ast.Expr, info *types.Info) string {
+	if unary, ok := subject.(*ast.UnaryExpr); ok {
+		subject = unary.X
+	}
+	if sel, ok := subject.(*ast.SelectorExpr); ok {
+		// Mutexes of package-level variables are keyed by the variable (see MutexScope.Key)
+		if packageVar(sel, info) != nil {
+			return StrExpr(subject)
+		}
+		if selection, ok := info.Selections[sel]; ok && selection.Kind() == types.FieldVal {
+			return getTypeName(selection.Recv()) + "." + sel.Sel.Name
+		}
+	}
+	return StrExpr(subject)
+}
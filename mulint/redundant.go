@@ -0,0 +1,172 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// checkRedundantMutexes reports mutexes only ever locked while holding another mutex
+// of the same value (e.g., s.statsMu always locked within s.mu sections): the outer lock
+// already serializes the sections, so the inner mutex may be redundant.
+// A scope is nested within another one if it's acquired and released within the other scope
+// in the same function. Read lock scopes don't serialize, so they don't count as outer ones.
+// Mutexes having their address taken (sync.NewCond(&s.mu), lockers) aren't considered.
+func (a *Analyzer) checkRedundantMutexes() {
+	keys := a.lockKeys()
+	escaped := a.addressedMutexes()
+
+	type lockedScope struct {
+		scope *MutexScope
+		key   string
+	}
+	byFunc := make(map[FQN][]lockedScope)
+	var mutexes []string
+	for fqn, tracker := range a.scopes {
+		for _, scope := range tracker.Scopes() {
+			if scope.IsFresh() {
+				continue
+			}
+			key, ok := keys[scope.Pos()]
+			if !ok {
+				key = scope.Key(fqn)
+			}
+			byFunc[fqn] = append(byFunc[fqn], lockedScope{scope: scope, key: key})
+			if !slices.Contains(mutexes, key) {
+				mutexes = append(mutexes, key)
+			}
+		}
+	}
+	slices.Sort(mutexes)
+
+	for _, inner := range mutexes {
+		if escaped[inner] {
+			continue
+		}
+
+		// The mutexes held over every scope of the inner one, along with the first outer scope
+		var candidates []string
+		outerScopes := make(map[string]*MutexScope)
+		var first *MutexScope
+		count := 0
+		for fqn, scopes := range byFunc {
+			for _, s := range scopes {
+				if s.key != inner {
+					continue
+				}
+				count++
+				if first == nil || s.scope.Pos() < first.Pos() {
+					first = s.scope
+				}
+
+				var outer []string
+				for _, o := range byFunc[fqn] {
+					if o.key == inner || o.scope.IsRead() || !sameOwner(o.scope, s.scope) || !nestedScope(s.scope, o.scope) {
+						continue
+					}
+					outer = append(outer, o.key)
+					if prev, ok := outerScopes[o.key]; !ok || o.scope.Pos() < prev.Pos() {
+						outerScopes[o.key] = o.scope
+					}
+				}
+				if count == 1 {
+					candidates = outer
+				} else {
+					candidates = slices.DeleteFunc(candidates, func(key string) bool { return !slices.Contains(outer, key) })
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		slices.Sort(candidates)
+		outer := candidates[0]
+		a.diagnostics = append(a.diagnostics, NewRedundantMutexError(
+			NewLocation(first.Pos()),
+			NewLocation(outerScopes[outer].Pos()),
+			inner,
+			outer,
+			count,
+		))
+	}
+}
+
+// lockKeys returns the typed keys (see typedMutexKey) of the mutexes locked by the direct lock calls
+// of the package functions by the position of the calls.
+func (a *Analyzer) lockKeys() map[token.Pos]string {
+	keys := make(map[token.Pos]string)
+	for _, fn := range a.funcs {
+		if fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if subject := SubjectForCall(call, lockMethods); subject != nil && IsMutexType(subject, a.info) {
+					keys[call.Pos()] = typedMutexKey(subject, a.info)
+				}
+			}
+			return true
+		})
+	}
+	return keys
+}
+
+// addressedMutexes returns the typed keys of the mutexes having their address taken.
+func (a *Analyzer) addressedMutexes() map[string]bool {
+	addressed := make(map[string]bool)
+	for _, fn := range a.funcs {
+		if fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if unary, ok := n.(*ast.UnaryExpr); ok && unary.Op == token.AND && IsMutexType(unary.X, a.info) {
+				addressed[typedMutexKey(unary.X, a.info)] = true
+			}
+			return true
+		})
+	}
+	return addressed
+}
+
+// sameOwner checks if the mutexes of the scopes belong to the same value (s.mu and s.statsMu)
+// or both are package-level variables.
+func sameOwner(a, b *MutexScope) bool {
+	if a.IsGlobal() || b.IsGlobal() {
+		return a.IsGlobal() && b.IsGlobal()
+	}
+	return selectorOwner(a.Selector()) == selectorOwner(b.Selector())
+}
+
+// selectorOwner returns the value owning the mutex ("s.inner" for "s.inner.mu").
+func selectorOwner(selector string) string {
+	if i := strings.LastIndex(selector, "."); i >= 0 {
+		return selector[:i]
+	}
+	return ""
+}
+
+// nestedScope checks if the inner scope is acquired and released within the outer one:
+// the inner lock and the statements executed under it are executed under the outer lock, too.
+func nestedScope(inner, outer *MutexScope) bool {
+	if !scopeCovers(outer, inner.Pos(), inner.Pos()) {
+		return false
+	}
+	for _, node := range inner.Nodes() {
+		if !scopeCovers(outer, node.Pos(), node.End()) {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeCovers checks if the source range is within one of the statements executed under the scope's lock.
+func scopeCovers(scope *MutexScope, pos, end token.Pos) bool {
+	for _, node := range scope.Nodes() {
+		if node.Pos() <= pos && end <= node.End() {
+			return true
+		}
+	}
+	return false
+}
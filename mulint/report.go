@@ -3,7 +3,9 @@ package mulint
 import (
 	"bufio"
 	"fmt"
+	"go/ast"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,10 +27,99 @@ func relativePath(filename string) string {
 	return rel
 }
 
+// Diagnostic codes identifying checks in the short output format.
+const (
+	CodeReentrantLock     = "MU001"
+	CodeMissingUnlock     = "MU002"
+	CodeCondWait          = "MU003"
+	CodeDoubleCheckedLock = "MU004"
+	CodeTimerCallbackWait = "MU005"
+	CodeSharedHandlerLock = "MU006"
+	CodeExportedCall      = "MU007"
+	CodeLockSummary       = "MU008"
+	CodeBlockingCall      = "MU009"
+	CodeLockedCycle       = "MU010"
+	CodeSelectDeadlock    = "MU011"
+	CodeEarlyUnlock       = "MU012"
+	CodeLockedConvention  = "MU013"
+	CodeMutexAssign       = "MU014"
+	CodeWriterStarvation  = "MU015"
+	CodeHeldMutexCopy     = "MU016"
+	CodeLoopVarLock       = "MU017"
+	CodeCopiedElementLock = "MU018"
+	CodeUnverifiableCall  = "MU019"
+	CodeRequiredLock      = "MU020"
+	CodeDeferUnlock       = "MU021"
+	CodeCriticalSection   = "MU022"
+	CodeConditionalUnlock = "MU023"
+	CodeUseAfterUnlock    = "MU024"
+	CodeGuardedReturn     = "MU025"
+	CodeEscapingClosure   = "MU026"
+	CodeMaybeSyncCallback = "MU027"
+	CodeDoubleDeferUnlock = "MU028"
+	CodeRedundantMutex    = "MU029"
+	CodeHotMethodLock     = "MU030"
+	CodeLockMetrics       = "MU031"
+	CodeSuspectWrapper    = "MU032"
+	CodeLocalMutex        = "MU033"
+)
+
+// reportDiagnostic emits a diagnostic using the configured output format.
+// In the short format, the message is replaced by a single-line summary
+// followed by the check code, e.g. "Mutex lock is already held [MU001]".
+//
+// Each diagnostic also gets the enclosing function's FQN as related information,
+// so that structured (-json) output can be aggregated by function and type.
+// The check code is used as the diagnostic category.
+func reportDiagnostic(pass *analysis.Pass, d analysis.Diagnostic, code, short string) {
+	d.Category = code
+	if config.Format == FormatShort {
+		d.Message = fmt.Sprintf("%s [%s]", short, code)
+	}
+	if decl, fqn := enclosingFunc(pass, d.Pos); decl != nil {
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     decl.Name.Pos(),
+			Message: "in function " + string(fqn),
+		})
+	}
+	pass.Report(d)
+}
+
+// enclosingFunc returns the function declaration containing the position and its FQN.
+func enclosingFunc(pass *analysis.Pass, pos token.Pos) (*ast.FuncDecl, FQN) {
+	for _, file := range pass.Files {
+		if pos < file.FileStart || pos >= file.FileEnd {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || pos < fn.Pos() || pos >= fn.End() {
+				continue
+			}
+			if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+				return fn, FromFunc(obj)
+			}
+		}
+	}
+	return nil, ""
+}
+
+// shortPosition formats a position as "file:line" relative to the working directory.
+func shortPosition(pass *analysis.Pass, pos token.Pos) string {
+	position := pass.Fset.Position(pos)
+	return fmt.Sprintf("%s:%d", relativePath(position.Filename), position.Line)
+}
+
 type LintError struct {
 	origin        Location
 	secondLock    Location
 	originWrapper *WrapperInfo // non-nil if origin lock was via wrapper
+	via           *LockSite    // non-nil if the second lock is acquired transitively
+	fqn           FQN          // the function holding the lock
+	selector      string       // the mutex selector
+	upgrade       bool         // true if the second lock is a write lock acquired under the read lock
+	hint          string       // how to avoid acquiring the lock again, if known (see suggestSlowPath)
+	fix           *analysis.SuggestedFix
 }
 
 func NewLintError(origin Location, secondLock Location) LintError {
@@ -67,14 +158,134 @@ func (le LintError) Report(pass *analysis.Pass) {
 		originSuffix = fmt.Sprintf(" (via %s)", le.originWrapper.FQN.ShortName())
 	}
 
-	pass.Reportf(le.secondLock.Pos(),
-		"Mutex lock is acquired on this line: %s\n\t%s:%d: But the same lock was acquired here: %s%s\n",
+	headline, originLabel := le.labels()
+
+	related := []analysis.RelatedInformation{
+		{Pos: le.origin.pos, Message: originLabel},
+	}
+	wrapperLine, wrapperSnippet, wrapperRelated := wrapperLock(pass, le.originWrapper)
+	related = append(related, wrapperRelated...)
+
+	// Add the function actually acquiring the lock for transitive locks
+	viaSuffix := ""
+	if le.via != nil {
+		viaPosition := pass.Fset.Position(le.via.Pos)
+		viaSuffix = fmt.Sprintf("\t%s:%d: Lock is acquired in %s: %s\n",
+			relativePath(viaPosition.Filename),
+			viaPosition.Line,
+			le.via.FQN.ShortName(),
+			strings.TrimSpace(sourceLine(viaPosition)),
+		)
+		related = append(related, analysis.RelatedInformation{
+			Pos:     le.via.Pos,
+			Message: fmt.Sprintf("lock is acquired in %s", le.via.FQN.ShortName()),
+		})
+	}
+
+	short := fmt.Sprintf("Mutex lock is acquired while already held (acquired at %s%s)",
+		shortPosition(pass, le.origin.pos), originSuffix)
+	if le.upgrade {
+		short = fmt.Sprintf("Mutex write lock is acquired while holding the read lock (acquired at %s%s)",
+			shortPosition(pass, le.origin.pos), originSuffix)
+	}
+	if le.via != nil {
+		short += fmt.Sprintf(", locked in %s at %s", le.via.FQN.ShortName(), shortPosition(pass, le.via.Pos))
+	}
+
+	message := fmt.Sprintf(
+		"%s: %s\n\t%s:%d: But %s: %s%s\n%s%s",
+		headline,
 		strings.TrimSpace(secondLockLine),
 		relativePath(originLockPosition.Filename),
 		originLockPosition.Line,
+		originLabel,
 		strings.TrimSpace(originLine),
 		originSuffix,
+		wrapperLine,
+		viaSuffix,
+	)
+	if le.hint != "" {
+		message += "\t" + le.hint + "\n"
+	}
+	if le.fix != nil {
+		message += "\t" + le.fix.Message + "\n"
+	}
+
+	if colorOutput() {
+		message = paint(ansiBoldRed, headline) + "\n" +
+			snippet(secondLockPosition, '^', ansiBoldRed) +
+			fmt.Sprintf("%s:%d: %s%s\n",
+				relativePath(originLockPosition.Filename),
+				originLockPosition.Line,
+				paint(ansiBold, "But "+originLabel),
+				originSuffix,
+			) +
+			snippet(originLockPosition, '-', ansiCyan) +
+			wrapperSnippet
+		if le.via != nil {
+			viaPosition := pass.Fset.Position(le.via.Pos)
+			message += fmt.Sprintf("%s:%d: %s\n",
+				relativePath(viaPosition.Filename),
+				viaPosition.Line,
+				paint(ansiBold, "Lock is acquired in "+le.via.FQN.ShortName()),
+			) + snippet(viaPosition, '-', ansiCyan)
+		}
+		if le.hint != "" {
+			message += le.hint + "\n"
+		}
+		if le.fix != nil {
+			message += le.fix.Message + "\n"
+		}
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:            le.secondLock.Pos(),
+		Message:        message,
+		Related:        related,
+		SuggestedFixes: le.suggestedFixes(),
+	}, CodeReentrantLock, short)
+}
+
+// wrapperLock describes the lock acquired inside the wrapper the origin lock was acquired via:
+// the message line, the colored message lines with the source snippet and the related information.
+func wrapperLock(pass *analysis.Pass, wrapper *WrapperInfo) (string, string, []analysis.RelatedInformation) {
+	if wrapper == nil || !wrapper.LockPos.IsValid() {
+		return "", "", nil
+	}
+
+	position := pass.Fset.Position(wrapper.LockPos)
+	label := fmt.Sprintf("Lock is acquired in wrapper %s", wrapper.FQN.ShortName())
+	line := fmt.Sprintf("\t%s:%d: %s: %s\n",
+		relativePath(position.Filename),
+		position.Line,
+		label,
+		strings.TrimSpace(sourceLine(position)),
 	)
+	colored := fmt.Sprintf("%s:%d: %s\n", relativePath(position.Filename), position.Line, paint(ansiBold, label)) +
+		snippet(position, '-', ansiCyan)
+	related := []analysis.RelatedInformation{{
+		Pos:     wrapper.LockPos,
+		Message: fmt.Sprintf("lock is acquired in wrapper %s", wrapper.FQN.ShortName()),
+	}}
+	return line, colored, related
+}
+
+// labels returns the headline of the error and the label of the origin lock.
+// Write locks acquired under the read lock of the same RWMutex (RLock -> Lock upgrades)
+// are reported distinctly, as they deadlock even without contending readers.
+func (le LintError) labels() (string, string) {
+	if le.upgrade {
+		return "Mutex write lock is acquired on this line while holding the read lock", "the read lock was acquired here"
+	}
+	return "Mutex lock is acquired on this line", "the same lock was acquired here"
+}
+
+// suggestedFixes returns the suggested fix for the error, if any.
+func (le LintError) suggestedFixes() []analysis.SuggestedFix {
+	if le.fix == nil {
+		return nil
+	}
+	return []analysis.SuggestedFix{*le.fix}
 }
 
 func (le LintError) GetLine(pass *analysis.Pass, position token.Position) string {
@@ -116,6 +327,7 @@ type MissingUnlockError struct {
 	lockPos   Location
 	returnPos Location
 	wrapper   *WrapperInfo // non-nil if the lock was acquired via wrapper
+	path      []string     // branch conditions of the path to the return, if known
 }
 
 func NewMissingUnlockError(lockPos, returnPos Location) MissingUnlockError {
@@ -134,6 +346,14 @@ func NewMissingUnlockErrorWithWrapper(lockPos, returnPos Location, wrapper *Wrap
 	}
 }
 
+func NewMissingUnlockErrorOnPath(lockPos, returnPos Location, path []string) MissingUnlockError {
+	return MissingUnlockError{
+		lockPos:   lockPos,
+		returnPos: returnPos,
+		path:      path,
+	}
+}
+
 func (e MissingUnlockError) Report(pass *analysis.Pass) {
 	lockPosition := pass.Fset.Position(e.lockPos.pos)
 	lockLine := e.GetLine(pass, lockPosition)
@@ -144,13 +364,43 @@ func (e MissingUnlockError) Report(pass *analysis.Pass) {
 		lockSuffix = fmt.Sprintf(" (via %s)", e.wrapper.FQN.ShortName())
 	}
 
-	pass.Reportf(e.returnPos.Pos(),
-		"Mutex lock must be released before this line\n\t%s:%d: Lock was acquired here: %s%s\n",
+	wrapperLine, wrapperSnippet, wrapperRelated := wrapperLock(pass, e.wrapper)
+
+	pathSuffix := ""
+	if len(e.path) > 0 {
+		pathSuffix = fmt.Sprintf("\tThe lock is not released when %s\n", strings.Join(e.path, " and "))
+	}
+
+	message := fmt.Sprintf(
+		"Mutex lock must be released before this line\n\t%s:%d: Lock was acquired here: %s%s\n%s%s",
 		relativePath(lockPosition.Filename),
 		lockPosition.Line,
 		strings.TrimSpace(lockLine),
 		lockSuffix,
+		wrapperLine,
+		pathSuffix,
 	)
+
+	if colorOutput() {
+		message = paint(ansiBoldRed, "Mutex lock must be released before this line") + "\n" +
+			snippet(pass.Fset.Position(e.returnPos.pos), '^', ansiBoldRed) +
+			fmt.Sprintf("%s:%d: %s%s\n",
+				relativePath(lockPosition.Filename),
+				lockPosition.Line,
+				paint(ansiBold, "Lock was acquired here"),
+				lockSuffix,
+			) +
+			snippet(lockPosition, '-', ansiCyan) +
+			wrapperSnippet +
+			pathSuffix
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:     e.returnPos.Pos(),
+		Message: message,
+		Related: wrapperRelated,
+	}, CodeMissingUnlock, fmt.Sprintf("Mutex lock must be released before this line (acquired at %s%s)",
+		shortPosition(pass, e.lockPos.pos), lockSuffix))
 }
 
 func (e MissingUnlockError) GetLine(pass *analysis.Pass, position token.Position) string {
@@ -175,3 +425,1207 @@ func (e MissingUnlockError) readfile(filename string) []string {
 	}
 	return lines
 }
+
+// SharedHandlerLockError reports a helper called by an HTTP handler under a lock
+// that another handler using the same helper also acquires.
+type SharedHandlerLockError struct {
+	lockPos Location
+	callPos Location
+	helper  FQN
+	handler FQN // the other handler sharing the helper and the mutex
+}
+
+func NewSharedHandlerLockError(lockPos, callPos Location, helper, handler FQN) SharedHandlerLockError {
+	return SharedHandlerLockError{
+		lockPos: lockPos,
+		callPos: callPos,
+		helper:  helper,
+		handler: handler,
+	}
+}
+
+func (e SharedHandlerLockError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Shared handler helper %s is called under lock\n\t%s:%d: Lock was acquired here: %s\n\tHandler %s also uses it and locks the same mutex\n",
+			e.helper.ShortName(),
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			e.handler.ShortName(),
+		),
+	}, CodeSharedHandlerLock, fmt.Sprintf("Shared handler helper %s is called under lock (acquired at %s); handler %s also uses it and locks the same mutex",
+		e.helper.ShortName(), shortPosition(pass, e.lockPos.pos), e.handler.ShortName()))
+}
+
+// sourceLine returns the source line at the given position, or an empty string
+// if the file cannot be read.
+func sourceLine(position token.Position) string {
+	f, err := os.Open(position.Filename)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	line := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line++
+		if line == position.Line {
+			return scanner.Text()
+		}
+	}
+	return ""
+}
+
+// LockSummaryReport reports the lock behavior of an exported function.
+type LockSummaryReport struct {
+	pos     Location
+	summary LockSummary
+}
+
+func NewLockSummaryReport(pos Location, summary LockSummary) LockSummaryReport {
+	return LockSummaryReport{
+		pos:     pos,
+		summary: summary,
+	}
+}
+
+func (r LockSummaryReport) Report(pass *analysis.Pass) {
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: r.pos.Pos(),
+		Message: fmt.Sprintf(
+			"Lock summary for %s\n\tacquires: %s\n\treturns holding: %s\n\trequires: %s\n",
+			r.summary.FQN.ShortName(),
+			formatList(r.summary.Acquires),
+			formatList(r.summary.ReturnsHolding),
+			formatList(r.summary.Requires),
+		),
+	}, CodeLockSummary, fmt.Sprintf("Lock summary for %s: acquires: %s; returns holding: %s; requires: %s",
+		r.summary.FQN.ShortName(),
+		formatList(r.summary.Acquires),
+		formatList(r.summary.ReturnsHolding),
+		formatList(r.summary.Requires),
+	))
+}
+
+// CriticalSectionReport lists the calls to other packages made while holding a lock.
+type CriticalSectionReport struct {
+	lockPos Location
+	mutex   string
+	calls   []FQN
+}
+
+func NewCriticalSectionReport(lockPos Location, mutex string, calls []FQN) CriticalSectionReport {
+	return CriticalSectionReport{
+		lockPos: lockPos,
+		mutex:   mutex,
+		calls:   calls,
+	}
+}
+
+func (r CriticalSectionReport) Report(pass *analysis.Pass) {
+	calls := make([]string, len(r.calls))
+	for i, call := range r.calls {
+		calls[i] = string(call)
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: r.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"External calls under lock %s\n\t%s\n",
+			r.mutex,
+			strings.Join(calls, "\n\t"),
+		),
+	}, CodeCriticalSection, fmt.Sprintf("External calls under lock %s: %s", r.mutex, strings.Join(calls, ", ")))
+}
+
+// LockMetricsReport reports the usage metrics of a mutex: its fan-in and the maximum held set.
+type LockMetricsReport struct {
+	lockPos   Location
+	metrics   lockMetrics
+	highFanIn bool
+}
+
+func NewLockMetricsReport(lockPos Location, metrics lockMetrics, highFanIn bool) LockMetricsReport {
+	return LockMetricsReport{
+		lockPos:   lockPos,
+		metrics:   metrics,
+		highFanIn: highFanIn,
+	}
+}
+
+func (r LockMetricsReport) Report(pass *analysis.Pass) {
+	var advice, shortAdvice string
+	if r.highFanIn {
+		advice = fmt.Sprintf("\tHigh fan-in (%d functions); consider splitting the mutex or narrowing the code acquiring it\n", r.metrics.funcs)
+		shortAdvice = "; high fan-in"
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: r.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Lock metrics for %s\n\tacquiring functions: %d\n\tmax held set: %d\n%s",
+			r.metrics.mutex,
+			r.metrics.funcs,
+			r.metrics.maxHeld,
+			advice,
+		),
+	}, CodeLockMetrics, fmt.Sprintf("Lock metrics for %s: acquiring functions: %d; max held set: %d%s",
+		r.metrics.mutex, r.metrics.funcs, r.metrics.maxHeld, shortAdvice))
+}
+
+// formatList joins items with commas, or returns "none" for an empty list.
+func formatList(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// ExportedCallError reports an exported method called under lock by another exported
+// method of the same type, where the callee also acquires the lock.
+type ExportedCallError struct {
+	lockPos Location
+	callPos Location
+	callee  FQN
+}
+
+func NewExportedCallError(lockPos, callPos Location, callee FQN) ExportedCallError {
+	return ExportedCallError{
+		lockPos: lockPos,
+		callPos: callPos,
+		callee:  callee,
+	}
+}
+
+func (e ExportedCallError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:      e.callPos.Pos(),
+		Category: "advisory",
+		Message: fmt.Sprintf(
+			"Exported method %s locks the same mutex and is called under lock\n\t%s:%d: Lock was acquired here: %s\n\tConsider extracting the shared logic into an unexported helper\n",
+			e.callee.ShortName(),
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+		),
+	}, CodeExportedCall, fmt.Sprintf("Exported method %s locks the same mutex and is called under lock (acquired at %s)",
+		e.callee.ShortName(), shortPosition(pass, e.lockPos.pos)))
+}
+
+// UseAfterUnlockError reports an access to a field guarded by a mutex made after releasing it.
+type UseAfterUnlockError struct {
+	accessPos Location
+	unlockPos Location
+	access    string
+	mutex     string
+	deferred  bool // accessed by a deferred function running after the deferred unlock
+}
+
+func NewUseAfterUnlockError(accessPos, unlockPos Location, access, mutex string) UseAfterUnlockError {
+	return UseAfterUnlockError{
+		accessPos: accessPos,
+		unlockPos: unlockPos,
+		access:    access,
+		mutex:     mutex,
+	}
+}
+
+// NewDeferredUseAfterUnlockError reports an access made by a function deferred before the deferred unlock,
+// which runs after the lock is released.
+func NewDeferredUseAfterUnlockError(accessPos, unlockPos Location, access, mutex string) UseAfterUnlockError {
+	e := NewUseAfterUnlockError(accessPos, unlockPos, access, mutex)
+	e.deferred = true
+	return e
+}
+
+func (e UseAfterUnlockError) Report(pass *analysis.Pass) {
+	unlockPosition := pass.Fset.Position(e.unlockPos.pos)
+
+	accessed, released, hint := "accessed", "Lock was released here", "Consider accessing it before the unlock or copying it to a local variable"
+	if e.deferred {
+		accessed = "accessed by a deferred function"
+		released = "Lock is released here first (deferred calls run in the reverse order)"
+		hint = "Consider deferring the function after the unlock, so that it runs under the lock"
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:      e.accessPos.Pos(),
+		Category: "advisory",
+		Message: fmt.Sprintf(
+			"%s is guarded by %s and %s after releasing the lock\n\t%s:%d: %s: %s\n\t%s\n",
+			e.access,
+			e.mutex,
+			accessed,
+			relativePath(unlockPosition.Filename),
+			unlockPosition.Line,
+			released,
+			strings.TrimSpace(sourceLine(unlockPosition)),
+			hint,
+		),
+	}, CodeUseAfterUnlock, fmt.Sprintf("%s is guarded by %s and %s after releasing the lock (released at %s)",
+		e.access, e.mutex, accessed, shortPosition(pass, e.unlockPos.pos)))
+}
+
+// GuardedReturnError reports guarded data of a reference type returned while holding the lock.
+type GuardedReturnError struct {
+	resultPos Location
+	lockPos   Location
+	result    string
+	mutex     string
+	copyHint  string // e.g., "slices.Clone(s.items)"
+}
+
+func NewGuardedReturnError(resultPos, lockPos Location, result, mutex, copyHint string) GuardedReturnError {
+	return GuardedReturnError{
+		resultPos: resultPos,
+		lockPos:   lockPos,
+		result:    result,
+		mutex:     mutex,
+		copyHint:  copyHint,
+	}
+}
+
+func (e GuardedReturnError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.resultPos.Pos(),
+		Message: fmt.Sprintf(
+			"%s is guarded by %s and returned under lock\n\t%s:%d: Lock was acquired here: %s\n\tCallers may access it after the lock is released; consider returning %s\n",
+			e.result,
+			e.mutex,
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			e.copyHint,
+		),
+	}, CodeGuardedReturn, fmt.Sprintf("%s is guarded by %s and returned under lock (acquired at %s)",
+		e.result, e.mutex, shortPosition(pass, e.lockPos.pos)))
+}
+
+// EscapingClosureError reports a func literal accessing guarded fields of captured values
+// that escapes the function, so it runs later without holding the lock.
+type EscapingClosureError struct {
+	litPos    Location
+	accessPos Location
+	access    string
+	mutex     string
+	how       string // "returned", "stored" or "sent"
+}
+
+func NewEscapingClosureError(litPos, accessPos Location, access, mutex, how string) EscapingClosureError {
+	return EscapingClosureError{
+		litPos:    litPos,
+		accessPos: accessPos,
+		access:    access,
+		mutex:     mutex,
+		how:       how,
+	}
+}
+
+func (e EscapingClosureError) Report(pass *analysis.Pass) {
+	accessPosition := pass.Fset.Position(e.accessPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:      e.litPos.Pos(),
+		Category: "advisory",
+		Message: fmt.Sprintf(
+			"Func literal is %s and accesses %s guarded by %s without holding the lock\n\t%s:%d: Guarded field is accessed here: %s\n\tThe literal runs later; consider acquiring the lock within it\n",
+			e.how,
+			e.access,
+			e.mutex,
+			relativePath(accessPosition.Filename),
+			accessPosition.Line,
+			strings.TrimSpace(sourceLine(accessPosition)),
+		),
+		Related: []analysis.RelatedInformation{
+			{Pos: e.accessPos.pos, Message: "guarded field is accessed without the lock"},
+		},
+	}, CodeEscapingClosure, fmt.Sprintf("Func literal is %s and accesses %s guarded by %s without holding the lock (at %s)",
+		e.how, e.access, e.mutex, shortPosition(pass, e.accessPos.pos)))
+}
+
+// MaybeSyncCallbackError reports a callback acquiring the held mutex registered under lock
+// with a function that may invoke it synchronously (see Config.MaybeSyncCallbacks).
+type MaybeSyncCallbackError struct {
+	callPos     Location
+	lockPos     Location
+	callbackPos Location
+	callee      FQN
+}
+
+func NewMaybeSyncCallbackError(callPos, lockPos, callbackPos Location, callee FQN) MaybeSyncCallbackError {
+	return MaybeSyncCallbackError{
+		callPos:     callPos,
+		lockPos:     lockPos,
+		callbackPos: callbackPos,
+		callee:      callee,
+	}
+}
+
+func (e MaybeSyncCallbackError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+	callbackPosition := pass.Fset.Position(e.callbackPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Callback registered under lock acquires the same lock and deadlocks if %s invokes it synchronously\n\t%s:%d: Lock was acquired here: %s\n\t%s:%d: Callback acquiring the lock: %s\n",
+			e.callee.ShortName(),
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			relativePath(callbackPosition.Filename),
+			callbackPosition.Line,
+			strings.TrimSpace(sourceLine(callbackPosition)),
+		),
+		Related: []analysis.RelatedInformation{
+			{Pos: e.callbackPos.pos, Message: "callback acquires the lock"},
+		},
+	}, CodeMaybeSyncCallback, fmt.Sprintf("Callback registered under lock acquires the same lock and deadlocks if %s invokes it synchronously (acquired at %s)",
+		e.callee.ShortName(), shortPosition(pass, e.lockPos.pos)))
+}
+
+// CondWaitError reports a sync.Cond.Wait() call made without holding the mutex
+// the condition variable was constructed with.
+type CondWaitError struct {
+	waitPos Location
+	locker  string   // the mutex bound to the condition variable
+	held    []string // the mutexes held at the call
+}
+
+func NewCondWaitError(waitPos Location, locker string, held []string) CondWaitError {
+	return CondWaitError{
+		waitPos: waitPos,
+		locker:  locker,
+		held:    held,
+	}
+}
+
+func (e CondWaitError) Report(pass *analysis.Pass) {
+	msg := fmt.Sprintf("Cond.Wait is called without holding its lock %s", e.locker)
+	if len(e.held) > 0 {
+		msg = fmt.Sprintf("Cond.Wait is called while holding %s, but the condition variable is bound to %s",
+			strings.Join(e.held, ", "),
+			e.locker,
+		)
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:     e.waitPos.Pos(),
+		Message: msg + "\n",
+	}, CodeCondWait, msg)
+}
+
+// DoubleCheckedLockError reports a guarded field read without the lock
+// before being re-checked under the lock.
+type DoubleCheckedLockError struct {
+	pos   Location
+	field string
+	mutex string
+}
+
+func NewDoubleCheckedLockError(pos Location, field, mutex string) DoubleCheckedLockError {
+	return DoubleCheckedLockError{
+		pos:   pos,
+		field: field,
+		mutex: mutex,
+	}
+}
+
+func (e DoubleCheckedLockError) Report(pass *analysis.Pass) {
+	msg := fmt.Sprintf("Guarded field %s is read without holding %s (double-checked locking)", e.field, e.mutex)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:     e.pos.Pos(),
+		Message: msg + "\n\tConsider using sync.Once or atomic values instead\n",
+	}, CodeDoubleCheckedLock, msg)
+}
+
+// TimerCallbackWaitError reports waiting for a timer callback to complete while holding
+// a mutex the callback needs.
+type TimerCallbackWaitError struct {
+	lockPos     Location
+	waitPos     Location
+	callbackPos Location
+}
+
+func NewTimerCallbackWaitError(lockPos, waitPos, callbackPos Location) TimerCallbackWaitError {
+	return TimerCallbackWaitError{
+		lockPos:     lockPos,
+		waitPos:     waitPos,
+		callbackPos: callbackPos,
+	}
+}
+
+func (e TimerCallbackWaitError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+	callbackPosition := pass.Fset.Position(e.callbackPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.waitPos.Pos(),
+		Message: fmt.Sprintf(
+			"Waiting for a timer callback that needs the held lock\n\t%s:%d: Lock was acquired here: %s\n\t%s:%d: Callback was scheduled here: %s\n",
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			relativePath(callbackPosition.Filename),
+			callbackPosition.Line,
+			strings.TrimSpace(sourceLine(callbackPosition)),
+		),
+	}, CodeTimerCallbackWait, fmt.Sprintf("Waiting for a timer callback that needs the held lock (acquired at %s, callback scheduled at %s)",
+		shortPosition(pass, e.lockPos.pos), shortPosition(pass, e.callbackPos.pos)))
+}
+
+// BlockingCallError reports a call to a blocking function made while holding a lock.
+type BlockingCallError struct {
+	lockPos  Location
+	callPos  Location
+	callee   FQN
+	blocking FQN // the blocking function reachable from callee (may be callee itself)
+}
+
+func NewBlockingCallError(lockPos, callPos Location, callee, blocking FQN) BlockingCallError {
+	return BlockingCallError{
+		lockPos:  lockPos,
+		callPos:  callPos,
+		callee:   callee,
+		blocking: blocking,
+	}
+}
+
+func (e BlockingCallError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	viaSuffix := ""
+	shortSuffix := ""
+	if e.blocking != e.callee {
+		viaSuffix = fmt.Sprintf("\tIt calls %s, which blocks\n", e.blocking.ShortName())
+		shortSuffix = fmt.Sprintf(", calls blocking %s", e.blocking.ShortName())
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Blocking call %s while holding lock\n\t%s:%d: Lock was acquired here: %s\n%s",
+			e.callee.ShortName(),
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			viaSuffix,
+		),
+	}, CodeBlockingCall, fmt.Sprintf("Blocking call %s while holding lock (acquired at %s%s)",
+		e.callee.ShortName(), shortPosition(pass, e.lockPos.pos), shortSuffix))
+}
+
+// LockedCycleError reports a call under lock entering a recursion cycle,
+// one of the functions of which acquires the same lock.
+type LockedCycleError struct {
+	lockPos Location
+	callPos Location
+	cycle   []FQN
+	site    LockSite
+}
+
+func NewLockedCycleError(lockPos, callPos Location, cycle []FQN, site LockSite) LockedCycleError {
+	return LockedCycleError{
+		lockPos: lockPos,
+		callPos: callPos,
+		cycle:   cycle,
+		site:    site,
+	}
+}
+
+func (e LockedCycleError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+	sitePosition := pass.Fset.Position(e.site.Pos)
+
+	names := make([]string, len(e.cycle))
+	for i, fqn := range e.cycle {
+		names[i] = fqn.ShortName()
+	}
+	cycle := strings.Join(names, " -> ")
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Call under lock enters a recursion cycle: %s\n\t%s:%d: Lock was acquired here: %s\n\t%s:%d: Lock is acquired again in %s: %s\n",
+			cycle,
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			relativePath(sitePosition.Filename),
+			sitePosition.Line,
+			e.site.FQN.ShortName(),
+			strings.TrimSpace(sourceLine(sitePosition)),
+		),
+		Related: []analysis.RelatedInformation{
+			{Pos: e.site.Pos, Message: fmt.Sprintf("lock is acquired again in %s", e.site.FQN.ShortName())},
+		},
+	}, CodeLockedCycle, fmt.Sprintf("Call under lock enters a recursion cycle: %s (acquired at %s, acquired again in %s at %s)",
+		cycle, shortPosition(pass, e.lockPos.pos), e.site.FQN.ShortName(), shortPosition(pass, e.site.Pos)))
+}
+
+// SelectDeadlockError reports a select without a default case waiting under lock
+// for channels fed only by goroutines that need the same lock.
+type SelectDeadlockError struct {
+	lockPos      Location
+	selectPos    Location
+	goroutinePos Location
+	channel      string
+}
+
+func NewSelectDeadlockError(lockPos, selectPos, goroutinePos Location, channel string) SelectDeadlockError {
+	return SelectDeadlockError{
+		lockPos:      lockPos,
+		selectPos:    selectPos,
+		goroutinePos: goroutinePos,
+		channel:      channel,
+	}
+}
+
+func (e SelectDeadlockError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+	goroutinePosition := pass.Fset.Position(e.goroutinePos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.selectPos.Pos(),
+		Message: fmt.Sprintf(
+			"Select waits under lock for channels fed only by goroutines that need the same lock\n\t%s:%d: Lock was acquired here: %s\n\t%s:%d: Goroutine sending to %s needs the lock: %s\n",
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			relativePath(goroutinePosition.Filename),
+			goroutinePosition.Line,
+			e.channel,
+			strings.TrimSpace(sourceLine(goroutinePosition)),
+		),
+	}, CodeSelectDeadlock, fmt.Sprintf("Select waits under lock for channels fed only by goroutines that need the same lock (acquired at %s, goroutine sending to %s at %s)",
+		shortPosition(pass, e.lockPos.pos), e.channel, shortPosition(pass, e.goroutinePos.pos)))
+}
+
+// EarlyUnlockError reports a return made after releasing a lock with a deferred unlock
+// and before re-acquiring it: the deferred unlock runs on an unlocked mutex.
+type EarlyUnlockError struct {
+	unlockPos Location
+	returnPos Location
+}
+
+func NewEarlyUnlockError(unlockPos, returnPos Location) EarlyUnlockError {
+	return EarlyUnlockError{
+		unlockPos: unlockPos,
+		returnPos: returnPos,
+	}
+}
+
+func (e EarlyUnlockError) Report(pass *analysis.Pass) {
+	unlockPosition := pass.Fset.Position(e.unlockPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.returnPos.Pos(),
+		Message: fmt.Sprintf(
+			"Deferred unlock runs on an unlocked mutex when returning here\n\t%s:%d: Lock was released here and not re-acquired: %s\n",
+			relativePath(unlockPosition.Filename),
+			unlockPosition.Line,
+			strings.TrimSpace(sourceLine(unlockPosition)),
+		),
+	}, CodeEarlyUnlock, fmt.Sprintf("Deferred unlock runs on an unlocked mutex when returning here (released at %s)",
+		shortPosition(pass, e.unlockPos.pos)))
+}
+
+// ConditionalUnlockError reports an unconditional unlock of a lock acquired only
+// when a condition holds: otherwise, the unlock runs on an unlocked mutex.
+type ConditionalUnlockError struct {
+	unlockPos Location
+	lockPos   Location
+	cond      string
+}
+
+func NewConditionalUnlockError(unlockPos, lockPos Location, cond string) ConditionalUnlockError {
+	return ConditionalUnlockError{
+		unlockPos: unlockPos,
+		lockPos:   lockPos,
+		cond:      cond,
+	}
+}
+
+func (e ConditionalUnlockError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.unlockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Mutex is unlocked unconditionally but locked only when %s\n\t%s:%d: Lock was acquired here: %s\n",
+			e.cond,
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+		),
+	}, CodeConditionalUnlock, fmt.Sprintf("Mutex is unlocked unconditionally but locked only when %s (acquired at %s)",
+		e.cond, shortPosition(pass, e.lockPos.pos)))
+}
+
+// DoubleDeferUnlockError reports a deferred unlock registered on top of the deferred unlocks
+// already matching the acquisitions of the mutex (defer s.mu.Unlock() written twice):
+// one of them runs on an unlocked mutex when returning.
+type DoubleDeferUnlockError struct {
+	deferPos Location
+	firstPos Location
+	selector string
+}
+
+func NewDoubleDeferUnlockError(deferPos, firstPos Location, selector string) DoubleDeferUnlockError {
+	return DoubleDeferUnlockError{
+		deferPos: deferPos,
+		firstPos: firstPos,
+		selector: selector,
+	}
+}
+
+func (e DoubleDeferUnlockError) Report(pass *analysis.Pass) {
+	firstPosition := pass.Fset.Position(e.firstPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.deferPos.Pos(),
+		Message: fmt.Sprintf(
+			"Unlock of %s is deferred more times than it's locked\n\t%s:%d: Unlock was already deferred here: %s\n",
+			e.selector,
+			relativePath(firstPosition.Filename),
+			firstPosition.Line,
+			strings.TrimSpace(sourceLine(firstPosition)),
+		),
+	}, CodeDoubleDeferUnlock, fmt.Sprintf("Unlock of %s is deferred more times than it's locked (already deferred at %s)",
+		e.selector, shortPosition(pass, e.firstPos.pos)))
+}
+
+// SuspectWrapperError reports a lock wrapper none of the callers of which release the lock,
+// so it's likely missing an unlock (see checkSuspectWrappers).
+type SuspectWrapperError struct {
+	lockPos Location
+	wrapper FQN
+	calls   []Location // the calls of the wrapper
+}
+
+func NewSuspectWrapperError(lockPos Location, wrapper FQN, calls []Location) SuspectWrapperError {
+	return SuspectWrapperError{
+		lockPos: lockPos,
+		wrapper: wrapper,
+		calls:   calls,
+	}
+}
+
+func (e SuspectWrapperError) Report(pass *analysis.Pass) {
+	var calls strings.Builder
+	related := make([]analysis.RelatedInformation, 0, len(e.calls))
+	for _, call := range e.calls {
+		position := pass.Fset.Position(call.pos)
+		fmt.Fprintf(&calls, "\t%s:%d: Called here: %s\n",
+			relativePath(position.Filename),
+			position.Line,
+			strings.TrimSpace(sourceLine(position)),
+		)
+		related = append(related, analysis.RelatedInformation{Pos: call.pos, Message: "called here"})
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Function %s returns holding the lock, but none of its callers release it\n%s\tIt's treated as a lock wrapper; it may be missing an unlock instead\n",
+			e.wrapper.ShortName(),
+			calls.String(),
+		),
+		Related: related,
+	}, CodeSuspectWrapper, fmt.Sprintf("Function %s returns holding the lock, but none of its callers release it (called at %s)",
+		e.wrapper.ShortName(), shortPosition(pass, e.calls[0].pos)))
+}
+
+// RedundantMutexError reports a mutex only ever locked while holding another mutex of the same value,
+// which may make it redundant.
+type RedundantMutexError struct {
+	lockPos  Location // the first lock of the inner mutex
+	outerPos Location // the first lock of the outer mutex held over it
+	inner    string
+	outer    string
+	count    int // the number of scopes of the inner mutex
+}
+
+func NewRedundantMutexError(lockPos, outerPos Location, inner, outer string, count int) RedundantMutexError {
+	return RedundantMutexError{
+		lockPos:  lockPos,
+		outerPos: outerPos,
+		inner:    inner,
+		outer:    outer,
+		count:    count,
+	}
+}
+
+func (e RedundantMutexError) Report(pass *analysis.Pass) {
+	outerPosition := pass.Fset.Position(e.outerPos.pos)
+
+	locks := "The lock"
+	if e.count > 1 {
+		locks = fmt.Sprintf("All %d locks", e.count)
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:      e.lockPos.Pos(),
+		Category: "advisory",
+		Message: fmt.Sprintf(
+			"Mutex %s is only locked while holding %s, so it may be redundant\n\t%s:%d: %s is held here: %s\n\t%s of %s are nested in %s sections; consider guarding its data with %s alone\n",
+			e.inner,
+			e.outer,
+			relativePath(outerPosition.Filename),
+			outerPosition.Line,
+			e.outer,
+			strings.TrimSpace(sourceLine(outerPosition)),
+			locks,
+			e.inner,
+			e.outer,
+			e.outer,
+		),
+	}, CodeRedundantMutex, fmt.Sprintf("Mutex %s is only locked while holding %s (held at %s)",
+		e.inner, e.outer, shortPosition(pass, e.outerPos.pos)))
+}
+
+// LocalMutexError reports a mutex field locked within a single function only, which guards
+// no fields accessed elsewhere.
+type LocalMutexError struct {
+	lockPos Location // the first lock of the mutex
+	mutex   string
+	fqn     FQN      // the function locking the mutex
+	guarded []string // the fields accessed under the lock
+}
+
+func NewLocalMutexError(lockPos Location, mutex string, fqn FQN, guarded []string) LocalMutexError {
+	return LocalMutexError{
+		lockPos: lockPos,
+		mutex:   mutex,
+		fqn:     fqn,
+		guarded: guarded,
+	}
+}
+
+func (e LocalMutexError) Report(pass *analysis.Pass) {
+	fields := "No fields are accessed under the lock"
+	if len(e.guarded) > 0 {
+		fields = "Fields accessed under the lock: " + strings.Join(e.guarded, ", ")
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:      e.lockPos.Pos(),
+		Category: "advisory",
+		Message: fmt.Sprintf(
+			"Mutex %s is only locked in %s and guards no fields accessed elsewhere\n\t%s\n\tUnless it's meant to serialize the calls of %s, it may not need to be a struct field, or the other accesses to the state it guards miss the lock\n",
+			e.mutex,
+			e.fqn.ShortName(),
+			fields,
+			e.fqn.ShortName(),
+		),
+	}, CodeLocalMutex, fmt.Sprintf("Mutex %s is only locked in %s and guards no fields accessed elsewhere",
+		e.mutex, e.fqn.ShortName()))
+}
+
+// HotMethodLockError reports a String, Error, Hash or Less method acquiring locks (see hotMethods).
+type HotMethodLockError struct {
+	methodPos Location
+	method    FQN
+	sites     []lockSite
+}
+
+func NewHotMethodLockError(methodPos Location, method FQN, sites []lockSite) HotMethodLockError {
+	return HotMethodLockError{
+		methodPos: methodPos,
+		method:    method,
+		sites:     sites,
+	}
+}
+
+func (e HotMethodLockError) Report(pass *analysis.Pass) {
+	var locks strings.Builder
+	related := make([]analysis.RelatedInformation, 0, len(e.sites))
+	for _, site := range e.sites {
+		position := pass.Fset.Position(site.scope.Pos())
+		label := "Lock is acquired here"
+		if site.fqn != e.method {
+			label = "Lock is acquired in " + site.fqn.ShortName()
+		}
+		fmt.Fprintf(&locks, "\t%s:%d: %s: %s\n",
+			relativePath(position.Filename),
+			position.Line,
+			label,
+			strings.TrimSpace(sourceLine(position)),
+		)
+		related = append(related, analysis.RelatedInformation{Pos: site.scope.Pos(), Message: "lock is acquired here"})
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:      e.methodPos.Pos(),
+		Category: "advisory",
+		Message: fmt.Sprintf(
+			"Method %s acquires locks, though it's commonly called implicitly or on hot paths\n%s\tCalling it under the same lock (e.g., formatting or logging the value) deadlocks; consider using a snapshot or atomic values\n",
+			e.method.ShortName(),
+			locks.String(),
+		),
+		Related: related,
+	}, CodeHotMethodLock, fmt.Sprintf("Method %s acquires locks, though it's commonly called implicitly or on hot paths (at %s)",
+		e.method.ShortName(), shortPosition(pass, e.sites[0].scope.Pos())))
+}
+
+// LockedSelfLockError reports a lock acquired by a function named *Locked,
+// which is expected to be called with the lock already held.
+type LockedSelfLockError struct {
+	lockPos Location
+	fn      FQN
+}
+
+func NewLockedSelfLockError(lockPos Location, fn FQN) LockedSelfLockError {
+	return LockedSelfLockError{
+		lockPos: lockPos,
+		fn:      fn,
+	}
+}
+
+func (e LockedSelfLockError) Report(pass *analysis.Pass) {
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Function %s acquires the lock it must be called with\n\tFunctions named *Locked expect their callers to hold the lock\n",
+			e.fn.ShortName(),
+		),
+	}, CodeLockedConvention, fmt.Sprintf("Function %s acquires the lock it must be called with", e.fn.ShortName()))
+}
+
+// LockedCallError reports a call to a function named *Locked made without holding the lock.
+type LockedCallError struct {
+	callPos Location
+	callee  FQN
+}
+
+func NewLockedCallError(callPos Location, callee FQN) LockedCallError {
+	return LockedCallError{
+		callPos: callPos,
+		callee:  callee,
+	}
+}
+
+func (e LockedCallError) Report(pass *analysis.Pass) {
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Function %s is called without holding the lock\n\tFunctions named *Locked expect their callers to hold the lock\n",
+			e.callee.ShortName(),
+		),
+	}, CodeLockedConvention, fmt.Sprintf("Function %s is called without holding the lock", e.callee.ShortName()))
+}
+
+// MutexAssignError reports an assignment to a mutex field outside of constructors.
+type MutexAssignError struct {
+	assignPos Location
+	field     string
+}
+
+func NewMutexAssignError(assignPos Location, field string) MutexAssignError {
+	return MutexAssignError{
+		assignPos: assignPos,
+		field:     field,
+	}
+}
+
+func (e MutexAssignError) Report(pass *analysis.Pass) {
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.assignPos.Pos(),
+		Message: fmt.Sprintf(
+			"Mutex %s is assigned outside of a constructor\n\tResetting a mutex that may be held corrupts the lock\n",
+			e.field,
+		),
+	}, CodeMutexAssign, fmt.Sprintf("Mutex %s is assigned outside of a constructor", e.field))
+}
+
+// HeldMutexCopyError reports a value containing a held mutex copied into another goroutine.
+type HeldMutexCopyError struct {
+	lockPos    Location
+	copyPos    Location
+	selector   string // the held mutex
+	viaChannel bool   // true if the value is sent on a channel, false if passed to a go statement
+}
+
+func NewHeldMutexCopyError(lockPos, copyPos Location, selector string, viaChannel bool) HeldMutexCopyError {
+	return HeldMutexCopyError{
+		lockPos:    lockPos,
+		copyPos:    copyPos,
+		selector:   selector,
+		viaChannel: viaChannel,
+	}
+}
+
+func (e HeldMutexCopyError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	headline := fmt.Sprintf("Value containing the held mutex %s is copied into a goroutine", e.selector)
+	if e.viaChannel {
+		headline = fmt.Sprintf("Value containing the held mutex %s is sent on a channel", e.selector)
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.copyPos.Pos(),
+		Message: fmt.Sprintf(
+			"%s\n\t%s:%d: Lock was acquired here: %s\n\tThe copy gets the mutex in the locked state; pass a pointer instead\n",
+			headline,
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+		),
+	}, CodeHeldMutexCopy, fmt.Sprintf("%s (acquired at %s)", headline, shortPosition(pass, e.lockPos.pos)))
+}
+
+// LoopVarLockError reports a goroutine locking the mutex of a loop variable shared by
+// all iterations (pre-Go 1.22 semantics).
+type LoopVarLockError struct {
+	lockPos Location
+	varPos  Location
+	name    string // the loop variable
+}
+
+func NewLoopVarLockError(lockPos, varPos Location, name string) LoopVarLockError {
+	return LoopVarLockError{
+		lockPos: lockPos,
+		varPos:  varPos,
+		name:    name,
+	}
+}
+
+func (e LoopVarLockError) Report(pass *analysis.Pass) {
+	varPosition := pass.Fset.Position(e.varPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Goroutine locks the mutex of loop variable %s shared by all iterations\n\t%s:%d: Loop variable is declared here: %s\n\tBefore Go 1.22, the goroutines may lock the mutex of another item; copy the variable (%s := %s) or pass it as an argument\n",
+			e.name,
+			relativePath(varPosition.Filename),
+			varPosition.Line,
+			strings.TrimSpace(sourceLine(varPosition)),
+			e.name, e.name,
+		),
+	}, CodeLoopVarLock, fmt.Sprintf("Goroutine locks the mutex of loop variable %s shared by all iterations (declared at %s)",
+		e.name, shortPosition(pass, e.varPos.pos)))
+}
+
+// CopiedElementLockError reports a lock acquired on the mutex of a copy of a map value or a slice element.
+type CopiedElementLockError struct {
+	lockPos Location
+	copyPos Location
+	mutex   string
+	fromMap bool // true for map values, false for slice and array elements
+}
+
+func NewCopiedElementLockError(lockPos, copyPos Location, mutex string, fromMap bool) CopiedElementLockError {
+	return CopiedElementLockError{
+		lockPos: lockPos,
+		copyPos: copyPos,
+		mutex:   mutex,
+		fromMap: fromMap,
+	}
+}
+
+func (e CopiedElementLockError) Report(pass *analysis.Pass) {
+	copyPosition := pass.Fset.Position(e.copyPos.pos)
+
+	what, advice := "slice element", "index the slice (s[i].mu) or store pointers"
+	if e.fromMap {
+		what, advice = "map value", "store pointers in the map (map[K]*V)"
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Mutex %s is locked on a copy of a %s\n\t%s:%d: The value is copied here: %s\n\tThe lock doesn't protect the stored value; %s\n",
+			e.mutex,
+			what,
+			relativePath(copyPosition.Filename),
+			copyPosition.Line,
+			strings.TrimSpace(sourceLine(copyPosition)),
+			advice,
+		),
+	}, CodeCopiedElementLock, fmt.Sprintf("Mutex %s is locked on a copy of a %s (copied at %s)",
+		e.mutex, what, shortPosition(pass, e.copyPos.pos)))
+}
+
+// WriterStarvationError reports a read lock held over a loop or a blocking call,
+// which may starve the writers waiting for the lock.
+type WriterStarvationError struct {
+	lockPos  Location
+	pointPos Location // the loop or the blocking call
+	blocking FQN      // the blocking function reached by the call, empty for loops
+}
+
+func NewWriterStarvationError(lockPos, pointPos Location, blocking FQN) WriterStarvationError {
+	return WriterStarvationError{
+		lockPos:  lockPos,
+		pointPos: pointPos,
+		blocking: blocking,
+	}
+}
+
+func (e WriterStarvationError) Report(pass *analysis.Pass) {
+	pointPosition := pass.Fset.Position(e.pointPos.pos)
+
+	what := "a loop"
+	if e.blocking != "" {
+		what = "blocking call " + e.blocking.ShortName()
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Read lock is held over %s, which may starve writers\n\t%s:%d: Held over this line: %s\n\tPending Lock() calls block new readers too; consider releasing the read lock earlier\n",
+			what,
+			relativePath(pointPosition.Filename),
+			pointPosition.Line,
+			strings.TrimSpace(sourceLine(pointPosition)),
+		),
+		Related: []analysis.RelatedInformation{
+			{Pos: e.pointPos.pos, Message: "read lock is held over " + what},
+		},
+	}, CodeWriterStarvation, fmt.Sprintf("Read lock is held over %s, which may starve writers (at %s)",
+		what, shortPosition(pass, e.pointPos.pos)))
+}
+
+// UnverifiableCallError reports a call made under lock the analysis can't follow.
+type UnverifiableCallError struct {
+	lockPos Location
+	callPos Location
+	callee  string
+	reason  string // the kind of the callee (e.g., "interface method")
+}
+
+func NewUnverifiableCallError(lockPos, callPos Location, callee, reason string) UnverifiableCallError {
+	return UnverifiableCallError{
+		lockPos: lockPos,
+		callPos: callPos,
+		callee:  callee,
+		reason:  reason,
+	}
+}
+
+func (e UnverifiableCallError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	hint := "resolve dynamic calls with -callgraph"
+	if e.reason == unverifiableExternal {
+		hint = "declare its effects with -extern-summaries"
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Unverifiable call %s (%s) under lock\n\t%s:%d: Lock was acquired here: %s\n\tLocks acquired by the callee can't be checked; %s\n",
+			e.callee,
+			e.reason,
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+			hint,
+		),
+	}, CodeUnverifiableCall, fmt.Sprintf("Unverifiable call %s (%s) under lock (acquired at %s)",
+		e.callee, e.reason, shortPosition(pass, e.lockPos.pos)))
+}
+
+// BlockingOpError reports a channel operation that may block while holding a lock.
+type BlockingOpError struct {
+	lockPos Location
+	opPos   Location
+	op      string // the kind of the operation (e.g., "channel send")
+}
+
+func NewBlockingOpError(lockPos, opPos Location, op string) BlockingOpError {
+	return BlockingOpError{
+		lockPos: lockPos,
+		opPos:   opPos,
+		op:      op,
+	}
+}
+
+func (e BlockingOpError) Report(pass *analysis.Pass) {
+	lockPosition := pass.Fset.Position(e.lockPos.pos)
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.opPos.Pos(),
+		Message: fmt.Sprintf(
+			"Blocking %s while holding lock\n\t%s:%d: Lock was acquired here: %s\n",
+			e.op,
+			relativePath(lockPosition.Filename),
+			lockPosition.Line,
+			strings.TrimSpace(sourceLine(lockPosition)),
+		),
+	}, CodeBlockingCall, fmt.Sprintf("Blocking %s while holding lock (acquired at %s)",
+		e.op, shortPosition(pass, e.lockPos.pos)))
+}
+
+// RequiredLockError reports a call to a function annotated with //mulint:requires
+// made without holding the required mutex.
+type RequiredLockError struct {
+	callPos Location
+	callee  FQN
+	mutex   string
+}
+
+func NewRequiredLockError(callPos Location, callee FQN, mutex string) RequiredLockError {
+	return RequiredLockError{
+		callPos: callPos,
+		callee:  callee,
+		mutex:   mutex,
+	}
+}
+
+func (e RequiredLockError) Report(pass *analysis.Pass) {
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.callPos.Pos(),
+		Message: fmt.Sprintf(
+			"Function %s requires %s to be held, but it's called without it\n\tThe requirement is declared with //mulint:requires\n",
+			e.callee.ShortName(),
+			e.mutex,
+		),
+	}, CodeRequiredLock, fmt.Sprintf("Function %s requires %s to be held, but it's called without it",
+		e.callee.ShortName(), e.mutex))
+}
+
+// DeferUnlockError reports a lock not immediately followed by the deferred unlock of the mutex.
+type DeferUnlockError struct {
+	lockPos Location
+	mutex   string
+	unlock  string // the unlock method to defer (Unlock or RUnlock)
+	fix     *analysis.SuggestedFix
+}
+
+func NewDeferUnlockError(lockPos Location, mutex, unlock string) DeferUnlockError {
+	return DeferUnlockError{
+		lockPos: lockPos,
+		mutex:   mutex,
+		unlock:  unlock,
+	}
+}
+
+func (e DeferUnlockError) Report(pass *analysis.Pass) {
+	var fixes []analysis.SuggestedFix
+	if e.fix != nil {
+		fixes = []analysis.SuggestedFix{*e.fix}
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: e.lockPos.Pos(),
+		Message: fmt.Sprintf(
+			"Mutex lock is not followed by defer %s.%s()\n\tDefer the unlock right after the lock or annotate the function with //mulint:manual-unlock\n",
+			e.mutex,
+			e.unlock,
+		),
+		SuggestedFixes: fixes,
+	}, CodeDeferUnlock, fmt.Sprintf("Mutex lock is not followed by defer %s.%s()", e.mutex, e.unlock))
+}
@@ -4,12 +4,16 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"maps"
+	"slices"
+	"strings"
 )
 
 // WrapperInfo contains information about a wrapper method that was used to acquire a lock.
 type WrapperInfo struct {
 	FQN     FQN       // Fully qualified name of the wrapper method
 	LockPos token.Pos // Position of the actual Lock() call inside the wrapper
+	Read    bool      // Whether the wrapper acquires the read lock (RLock)
 }
 
 // MutexScope represents a region of code where a mutex is held.
@@ -18,8 +22,12 @@ type MutexScope struct {
 	selector string
 	pos      token.Pos
 	nodes    []ast.Node
-	unlocked bool        // true if the scope was properly unlocked (deferred or direct)
-	wrapper  *WrapperInfo // non-nil if the lock was acquired via a wrapper method
+	unlocked bool          // true if the scope was properly unlocked (deferred or direct)
+	wrapper  *WrapperInfo  // non-nil if the lock was acquired via a wrapper method
+	fresh    bool          // true if the mutex belongs to a new value that hasn't escaped (see markFreshScopes)
+	global   bool          // true if the mutex belongs to a package-level variable
+	read     bool          // true if the lock is acquired for reading (RLock)
+	merged   []*MutexScope // scopes of the mutex acquired on other paths continuing as this one (see continueAs)
 }
 
 func NewMutexScope(selector string, pos token.Pos) *MutexScope {
@@ -33,12 +41,14 @@ func NewMutexScope(selector string, pos token.Pos) *MutexScope {
 }
 
 // NewMutexScopeWithWrapper creates a scope that was acquired via a wrapper method.
+// The scope is a read one if the wrapper acquires the read lock.
 func NewMutexScopeWithWrapper(selector string, pos token.Pos, wrapper *WrapperInfo) *MutexScope {
 	return &MutexScope{
 		selector: selector,
 		nodes:    make([]ast.Node, 0),
 		pos:      pos,
 		unlocked: false,
+		read:     wrapper != nil && wrapper.Read,
 		wrapper:  wrapper,
 	}
 }
@@ -63,6 +73,37 @@ func (s *MutexScope) HasSameSelector(other *MutexScope) bool {
 	return s.selector == other.selector
 }
 
+// Key identifies the mutex within the function independently of the receiver name (see mutexKey).
+// Mutexes of package-level variables are identified by the variable ("state.mu").
+func (s *MutexScope) Key(fqn FQN) string {
+	if s.global {
+		return s.selector
+	}
+	return mutexKey(fqn, s.selector)
+}
+
+// IsGlobal returns true if the mutex belongs to a package-level variable.
+func (s *MutexScope) IsGlobal() bool {
+	return s.global
+}
+
+// mutexKey identifies a mutex independently of the receiver name used in a function.
+// For methods, "s.mu" in "pkg.Server:Handle" becomes "Server.mu"; other selectors
+// are returned as is.
+func mutexKey(fqn FQN, selector string) string {
+	typeName, _, isMethod := strings.Cut(fqn.ShortName(), ":")
+	_, field := SplitSelector(selector)
+	if !isMethod || field == "" {
+		return selector
+	}
+	return typeName + "." + field
+}
+
+// IsRead returns true if the lock is acquired for reading (RLock).
+func (s *MutexScope) IsRead() bool {
+	return s.read
+}
+
 // IsUnlocked returns true if the scope was properly unlocked.
 func (s *MutexScope) IsUnlocked() bool {
 	return s.unlocked
@@ -70,6 +111,33 @@ func (s *MutexScope) IsUnlocked() bool {
 
 func (s *MutexScope) markUnlocked() {
 	s.unlocked = true
+	for _, other := range s.merged {
+		other.markUnlocked()
+	}
+}
+
+// continueAs merges the scope acquired on another path reaching the same point into the held one,
+// so it's released along with it, e.g. the re-lock of
+//
+//	s.mu.Lock()
+//	if slow {
+//	    s.mu.Unlock()
+//	    wait()
+//	    s.mu.Lock()
+//	}
+//	s.mu.Unlock()
+func (s *MutexScope) continueAs(held *MutexScope) {
+	held.merged = append(held.merged, s)
+}
+
+// IsFresh returns true if the lock is acquired on a new value that hasn't escaped the function,
+// so no caller can hold it.
+func (s *MutexScope) IsFresh() bool {
+	return s.fresh
+}
+
+func (s *MutexScope) markFresh() {
+	s.fresh = true
 }
 
 // Wrapper returns the wrapper info if the lock was acquired via a wrapper, nil otherwise.
@@ -77,13 +145,23 @@ func (s *MutexScope) Wrapper() *WrapperInfo {
 	return s.wrapper
 }
 
+// deferredCall is a call deferred while tracking a block. It runs at the end of the block
+// (the function exit), before the deferred unlocks registered earlier.
+type deferredCall struct {
+	call          *ast.CallExpr
+	unlockedAfter map[string]bool // mutexes with deferred unlocks running after the call
+}
+
 // LockTracker tracks mutex lock/unlock operations within a function body.
 // It maintains state about ongoing locks, deferred unlocks, and completed scopes.
 type LockTracker struct {
 	onGoing  map[string]*MutexScope
 	defers   map[string]bool
+	deferred []deferredCall // deferred calls (other than unlocks) registered in this block
 	finished []*MutexScope
-	info     *types.Info // Optional type info for filtering non-mutex Lock calls
+	info     *types.Info      // Optional type info for filtering non-mutex Lock calls
+	wrappers *WrapperRegistry // Optional lock wrappers to track the calls of (see trackWrapperCalls)
+	left     bool             // true if no branch of the last joined statement reaches its end (see join)
 
 	// For future checks: track unlocks without matching locks
 	// unmatchedUnlocks []UnlockInfo
@@ -107,6 +185,13 @@ func NewLockTrackerWithInfo(info *types.Info) *LockTracker {
 	}
 }
 
+// NewLockTrackerWithWrappers creates a tracker recognizing the calls to the lock and unlock wrappers, too.
+func NewLockTrackerWithWrappers(registry *WrapperRegistry, info *types.Info) *LockTracker {
+	tracker := NewLockTrackerWithInfo(info)
+	tracker.wrappers = registry
+	return tracker
+}
+
 // Clone creates a copy of the tracker for independent branch analysis.
 func (t *LockTracker) Clone() *LockTracker {
 	clone := &LockTracker{
@@ -114,6 +199,7 @@ func (t *LockTracker) Clone() *LockTracker {
 		defers:   make(map[string]bool, len(t.defers)),
 		finished: make([]*MutexScope, 0),
 		info:     t.info,
+		wrappers: t.wrappers,
 	}
 	for k, v := range t.onGoing {
 		clone.onGoing[k] = v
@@ -125,29 +211,39 @@ func (t *LockTracker) Clone() *LockTracker {
 }
 
 // Track processes a statement for lock/unlock operations.
-// If addToOngoing is true, the statement is added to all currently held lock scopes.
-func (t *LockTracker) Track(stmt ast.Stmt, addToOngoing bool) {
+// The statement is added to all currently held lock scopes.
+func (t *LockTracker) Track(stmt ast.Stmt) {
+	// Labels don't affect the lock state, track the labeled statement itself
+	if labeled, ok := stmt.(*ast.LabeledStmt); ok {
+		t.Track(labeled.Stmt)
+		return
+	}
+
 	// For compound statements, add only the "prefix" parts (init, condition)
 	// that execute before any body code, not the entire statement.
-	if addToOngoing {
-		t.addStatementToOngoing(stmt)
-	}
+	t.addStatementToOngoing(stmt)
+
+	// Check for wrapper calls
+	t.trackWrapperCalls(stmt)
 
 	// Check for lock acquisition
 	if e := subjectForLockCall(stmt); e != nil {
 		// Only track if it's actually a sync.Mutex or sync.RWMutex
 		if IsMutexType(e, t.info) {
-			selector := StrExpr(e)
+			selector := LockSelector(e, t.info)
 			if _, exists := t.onGoing[selector]; !exists {
-				t.onGoing[selector] = NewMutexScope(selector, stmt.Pos())
+				scope := NewMutexScope(selector, stmt.Pos())
+				scope.global = packageVar(e, t.info) != nil
+				scope.read = isReadLockCall(stmt)
+				t.onGoing[selector] = scope
 			}
 		}
 	}
 
 	// Check for deferred unlock
-	if e := subjectForDeferUnlockCall(stmt); e != nil {
+	if e := deferredUnlockSubject(stmt); e != nil {
 		if IsMutexType(e, t.info) {
-			selector := StrExpr(e)
+			selector := LockSelector(e, t.info)
 			t.defers[selector] = true
 		}
 	}
@@ -155,7 +251,7 @@ func (t *LockTracker) Track(stmt ast.Stmt, addToOngoing bool) {
 	// Check for unlock
 	if e := subjectForUnlockCall(stmt); e != nil {
 		if IsMutexType(e, t.info) {
-			selector := StrExpr(e)
+			selector := LockSelector(e, t.info)
 			if scope, ok := t.onGoing[selector]; ok {
 				scope.markUnlocked()
 				t.finished = append(t.finished, scope)
@@ -166,7 +262,7 @@ func (t *LockTracker) Track(stmt ast.Stmt, addToOngoing bool) {
 	}
 
 	// Recurse into nested blocks
-	t.trackNestedStatements(stmt, addToOngoing)
+	walkFlow(t, stmt)
 }
 
 // addStatementToOngoing adds the appropriate parts of a statement to ongoing scopes.
@@ -189,7 +285,7 @@ func (t *LockTracker) addStatementToOngoing(stmt ast.Stmt) {
 		if s.Cond != nil {
 			t.addExprToOngoing(s.Cond)
 		}
-		// Note: Post executes after body, so we don't add it here
+		// Note: Post executes after body, so it's tracked after the body statements
 	case *ast.RangeStmt:
 		if s.X != nil {
 			t.addExprToOngoing(s.X)
@@ -201,6 +297,16 @@ func (t *LockTracker) addStatementToOngoing(stmt ast.Stmt) {
 		if s.Tag != nil {
 			t.addExprToOngoing(s.Tag)
 		}
+		// Case expressions are evaluated before the matching case body
+		if s.Body != nil {
+			for _, clause := range s.Body.List {
+				if cc, ok := clause.(*ast.CaseClause); ok {
+					for _, expr := range cc.List {
+						t.addExprToOngoing(expr)
+					}
+				}
+			}
+		}
 	case *ast.TypeSwitchStmt:
 		if s.Init != nil {
 			t.AddToOngoing(s.Init)
@@ -209,7 +315,16 @@ func (t *LockTracker) addStatementToOngoing(stmt ast.Stmt) {
 			t.AddToOngoing(s.Assign)
 		}
 	case *ast.SelectStmt:
-		// Select has no prefix expressions
+		// Communication clauses are evaluated (and block) before any case body
+		if s.Body != nil {
+			for _, clause := range s.Body.List {
+				if cc, ok := clause.(*ast.CommClause); ok && cc.Comm != nil {
+					t.AddToOngoing(cc.Comm)
+				}
+			}
+		}
+	case *ast.DeferStmt:
+		t.addDeferToOngoing(s)
 	case *ast.BlockStmt:
 		// Block has no prefix expressions
 	default:
@@ -218,6 +333,32 @@ func (t *LockTracker) addStatementToOngoing(stmt ast.Stmt) {
 	}
 }
 
+// addDeferToOngoing adds the parts of a defer statement evaluated in place (the arguments
+// and the receiver of the deferred call) to ongoing scopes, while the call itself
+// is added to the scopes still held when it runs at the end of the block (see EndBlock).
+func (t *LockTracker) addDeferToOngoing(stmt *ast.DeferStmt) {
+	if sel, ok := stmt.Call.Fun.(*ast.SelectorExpr); ok {
+		t.addExprToOngoing(sel.X)
+	}
+	for _, arg := range stmt.Call.Args {
+		t.addExprToOngoing(arg)
+	}
+
+	unlockedAfter := make(map[string]bool, len(t.defers))
+	for selector := range t.defers {
+		unlockedAfter[selector] = true
+	}
+	if e := deferredUnlockSubject(stmt); e != nil {
+		// Direct deferred unlocks don't run anything else under lock
+		if _, isLit := stmt.Call.Fun.(*ast.FuncLit); !isLit {
+			return
+		}
+		// Code before the unlock in a deferred func literal runs under the lock it releases
+		unlockedAfter[LockSelector(e, t.info)] = true
+	}
+	t.deferred = append(t.deferred, deferredCall{call: stmt.Call, unlockedAfter: unlockedAfter})
+}
+
 // addExprToOngoing wraps an expression and adds it to ongoing scopes.
 func (t *LockTracker) addExprToOngoing(expr ast.Expr) {
 	for _, scope := range t.onGoing {
@@ -225,101 +366,180 @@ func (t *LockTracker) addExprToOngoing(expr ast.Expr) {
 	}
 }
 
-// trackNestedStatements processes statements inside compound statements.
-func (t *LockTracker) trackNestedStatements(stmt ast.Stmt, addToOngoing bool) {
-	switch s := stmt.(type) {
-	case *ast.IfStmt:
-		// Track each branch independently to avoid cross-branch contamination
-		if s.Body != nil {
-			ifTracker := t.Clone()
-			for _, inner := range s.Body.List {
-				ifTracker.Track(inner, addToOngoing)
-			}
-			ifTracker.EndBlock()
-			t.finished = append(t.finished, ifTracker.finished...)
+// walkBranch tracks the statements of a branch of a compound statement (see walkFlow).
+func (t *LockTracker) walkBranch(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		t.Track(stmt)
+	}
+}
+
+// enterFlow does nothing: the prefix parts of compound statements are tracked along with the statements.
+func (t *LockTracker) enterFlow(*flowShape) {}
+
+// forkFlow tracks each branch of a branching statement independently to avoid cross-branch
+// contamination, while blocks and loop bodies are tracked in place.
+func (t *LockTracker) forkFlow(shape *flowShape, branch int) (*LockTracker, bool) {
+	switch {
+	case shape.kind == flowBlock || shape.kind == flowLoop:
+		return t, true
+	case !shape.branches[branch].present:
+		return nil, false
+	}
+	return t.Clone(), true
+}
+
+// joinFlow continues after a compound statement. The branches of an exhaustive if/else chain
+// and a select reaching the end of the statement are joined (see join), others (including
+// the switch cases) are finished along with the branch. The post statement of a loop is tracked
+// after the body with the lock state left by it.
+func (t *LockTracker) joinFlow(shape *flowShape, ends []flowEnd[*LockTracker]) {
+	switch shape.kind {
+	case flowBlock:
+		return
+	case flowLoop:
+		if shape.post != nil {
+			t.Track(shape.post)
 		}
-		if s.Else != nil {
-			elseTracker := t.Clone()
-			switch e := s.Else.(type) {
-			case *ast.BlockStmt:
-				for _, inner := range e.List {
-					elseTracker.Track(inner, addToOngoing)
-				}
-			case *ast.IfStmt:
-				elseTracker.Track(e, addToOngoing)
+		return
+	}
+
+	joined := shape.exhaustive && (shape.kind == flowIf || shape.kind == flowSelect)
+	var reaching []*LockTracker
+	for _, end := range ends {
+		branch := end.state
+		reachesEnd := !branch.left && !leavesBlock(shape.branches[end.branch].body, t.info, shape.kind == flowSelect)
+		if joined && reachesEnd {
+			reaching = append(reaching, branch)
+			continue
+		}
+		if reachesEnd {
+			t.continueRelocks(branch)
+		}
+		branch.EndBlock()
+		t.finished = append(t.finished, branch.finished...)
+	}
+	if joined {
+		t.join(reaching, true)
+	}
+}
+
+// join continues tracking after a branching statement with the lock state of the branches
+// reaching its end. Each of them is a possible path, so a lock held at the end of any branch
+// remains held (until released after the statement), while a lock released on all of them doesn't.
+// Unless the branches are exhaustive, the current state is one of the paths, too.
+// Locks with deferred unlocks registered within a branch are finished along with the branch.
+func (t *LockTracker) join(branches []*LockTracker, exhaustive bool) {
+	t.left = exhaustive && len(branches) == 0
+	onGoing := make(map[string]*MutexScope, len(t.onGoing))
+	if !exhaustive || len(branches) == 0 {
+		maps.Copy(onGoing, t.onGoing)
+	}
+
+	for _, branch := range branches {
+		for selector, scope := range branch.onGoing {
+			if branch.defers[selector] && !t.defers[selector] {
+				continue
 			}
-			elseTracker.EndBlock()
-			t.finished = append(t.finished, elseTracker.finished...)
+			delete(branch.onGoing, selector)
+			if held, ok := onGoing[selector]; ok && held != scope {
+				// Acquired on several branches, the first acquisition continues
+				scope.continueAs(held)
+				branch.finished = append(branch.finished, scope)
+				continue
+			}
+			onGoing[selector] = scope
+		}
+		branch.EndBlock()
+		t.finished = append(t.finished, branch.finished...)
+	}
+	t.onGoing = onGoing
+}
+
+// continueRelocks merges the locks the branch re-acquires (after releasing them) into the ones
+// still held after the statement, so that they are released along with them: the branch reaches
+// the end of the statement with the same mutex held.
+func (t *LockTracker) continueRelocks(branch *LockTracker) {
+	for selector, scope := range branch.onGoing {
+		held, ok := t.onGoing[selector]
+		if !ok || held == scope || branch.defers[selector] {
+			continue
 		}
+		scope.continueAs(held)
+		branch.finished = append(branch.finished, scope)
+		delete(branch.onGoing, selector)
+	}
+}
+
+// endsWithFallthrough checks if a case body transfers control to the next case.
+func endsWithFallthrough(body []ast.Stmt) bool {
+	if len(body) == 0 {
+		return false
+	}
+	branch, ok := body[len(body)-1].(*ast.BranchStmt)
+	return ok && branch.Tok == token.FALLTHROUGH
+}
+
+// prefixExprs returns the expressions of a compound statement evaluated before its body:
+// conditions, switch tags and case expressions, and ranged expressions.
+func prefixExprs(stmt ast.Stmt) []ast.Expr {
+	var exprs []ast.Expr
+	switch s := stmt.(type) {
+	case *ast.IfStmt:
+		exprs = append(exprs, s.Cond)
 	case *ast.ForStmt:
-		if s.Body != nil {
-			for _, inner := range s.Body.List {
-				t.Track(inner, addToOngoing)
-			}
+		if s.Cond != nil {
+			exprs = append(exprs, s.Cond)
 		}
 	case *ast.RangeStmt:
-		if s.Body != nil {
-			for _, inner := range s.Body.List {
-				t.Track(inner, addToOngoing)
-			}
+		if s.X != nil {
+			exprs = append(exprs, s.X)
 		}
 	case *ast.SwitchStmt:
-		if s.Body != nil {
-			for _, clause := range s.Body.List {
-				if cc, ok := clause.(*ast.CaseClause); ok {
-					// Track each case with a clone to avoid cross-case contamination
-					caseTracker := t.Clone()
-					for _, inner := range cc.Body {
-						caseTracker.Track(inner, addToOngoing)
-					}
-					// Finalize and merge scopes back
-					caseTracker.EndBlock()
-					t.finished = append(t.finished, caseTracker.finished...)
-				}
-			}
+		if s.Tag != nil {
+			exprs = append(exprs, s.Tag)
 		}
-	case *ast.TypeSwitchStmt:
 		if s.Body != nil {
 			for _, clause := range s.Body.List {
 				if cc, ok := clause.(*ast.CaseClause); ok {
-					caseTracker := t.Clone()
-					for _, inner := range cc.Body {
-						caseTracker.Track(inner, addToOngoing)
-					}
-					caseTracker.EndBlock()
-					t.finished = append(t.finished, caseTracker.finished...)
-				}
-			}
-		}
-	case *ast.SelectStmt:
-		if s.Body != nil {
-			for _, clause := range s.Body.List {
-				if cc, ok := clause.(*ast.CommClause); ok {
-					caseTracker := t.Clone()
-					for _, inner := range cc.Body {
-						caseTracker.Track(inner, addToOngoing)
-					}
-					caseTracker.EndBlock()
-					t.finished = append(t.finished, caseTracker.finished...)
+					exprs = append(exprs, cc.List...)
 				}
 			}
 		}
-	case *ast.BlockStmt:
-		for _, inner := range s.List {
-			t.Track(inner, addToOngoing)
-		}
 	}
+	return exprs
 }
 
-// isCompoundStatement returns true if the statement contains nested blocks.
-func isCompoundStatement(stmt ast.Stmt) bool {
-	switch stmt.(type) {
-	case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt,
-		*ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt,
-		*ast.BlockStmt:
-		return true
+// prefixStmts returns the statements of a compound statement executed before its body:
+// init statements and type switch guards.
+func prefixStmts(stmt ast.Stmt) []ast.Stmt {
+	var stmts []ast.Stmt
+	switch s := stmt.(type) {
+	case *ast.IfStmt:
+		stmts = append(stmts, s.Init)
+	case *ast.ForStmt:
+		stmts = append(stmts, s.Init)
+	case *ast.SwitchStmt:
+		stmts = append(stmts, s.Init)
+	case *ast.TypeSwitchStmt:
+		stmts = append(stmts, s.Init, s.Assign)
 	}
-	return false
+	return slices.DeleteFunc(stmts, func(s ast.Stmt) bool { return s == nil })
+}
+
+// exprCalls returns the calls made by evaluating the expression in source order.
+// Calls in func literals are not considered.
+func exprCalls(expr ast.Expr) []*ast.CallExpr {
+	var calls []*ast.CallExpr
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			calls = append(calls, e)
+		}
+		return true
+	})
+	return calls
 }
 
 // AddToOngoing adds a node to all currently held lock scopes.
@@ -360,6 +580,15 @@ func (t *LockTracker) AddDeferredUnlock(selector string) {
 // EndBlock finalizes tracking at block end.
 // Processes deferred unlocks and moves remaining locks to finished.
 func (t *LockTracker) EndBlock() {
+	// Deferred calls run under the locks still held, unless their deferred unlocks run first
+	for _, d := range t.deferred {
+		for selector, scope := range t.onGoing {
+			if !t.defers[selector] || d.unlockedAfter[selector] {
+				scope.Add(d.call)
+			}
+		}
+	}
+
 	// Process deferred unlocks - these are properly unlocked
 	for selector := range t.defers {
 		if scope, ok := t.onGoing[selector]; ok {
@@ -380,6 +609,17 @@ func (t *LockTracker) EndBlock() {
 
 	t.onGoing = make(map[string]*MutexScope)
 	t.defers = make(map[string]bool)
+	t.deferred = nil
+}
+
+// MergeWrapperScopes adds the scopes acquired via wrapper methods (see NewLockTrackerWithWrappers)
+// to the tracked ones.
+func (t *LockTracker) MergeWrapperScopes(scopes []*MutexScope) {
+	for _, scope := range scopes {
+		if scope.Wrapper() != nil {
+			t.finished = append(t.finished, scope)
+		}
+	}
 }
 
 // HasScopes returns true if any lock scopes were tracked.
@@ -407,6 +647,21 @@ func subjectForLockCall(node ast.Node) ast.Expr {
 	return SubjectForCall(node, lockMethods)
 }
 
+// isReadLockCall checks if the node is an RLock() call.
+func isReadLockCall(node ast.Node) bool {
+	return SubjectForCall(node, []string{"RLock"}) != nil
+}
+
+// isWriteLockCall checks if the node is a Lock() call.
+func isWriteLockCall(node ast.Node) bool {
+	return SubjectForCall(node, []string{"Lock"}) != nil
+}
+
+// isReadUnlockCall checks if the node is an RUnlock() call.
+func isReadUnlockCall(node ast.Node) bool {
+	return SubjectForCall(node, []string{"RUnlock"}) != nil
+}
+
 func subjectForUnlockCall(node ast.Node) ast.Expr {
 	return SubjectForCall(node, unlockMethods)
 }
@@ -428,12 +683,41 @@ func subjectForDeferUnlockCall(node ast.Node) ast.Expr {
 		return nil
 	}
 
-	// Search for Unlock call inside the closure body
+	// Search for Unlock call inside the closure body, up to the first statement which may return:
+	// the unlocks following it are deferred under a condition (see guardedDeferUnlock)
 	for _, stmt := range funcLit.Body.List {
 		if subject := SubjectForCall(stmt, unlockMethods); subject != nil {
 			return subject
 		}
+		if mayReturn(stmt) {
+			return nil
+		}
 	}
 
 	return nil
 }
+
+// deferredUnlockSubject returns the subject of an unlock deferred by the statement,
+// whether unconditionally or under a condition (see guardedDeferUnlock).
+func deferredUnlockSubject(node ast.Node) ast.Expr {
+	if subject := subjectForDeferUnlockCall(node); subject != nil {
+		return subject
+	}
+	subject, _ := guardedDeferUnlock(node)
+	return subject
+}
+
+// mayReturn checks if the statement contains a return (outside of func literals).
+func mayReturn(stmt ast.Stmt) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,220 @@
+package mulint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+)
+
+// goroutineFeed is a function launched with a go statement and the channels it sends to or closes.
+type goroutineFeed struct {
+	fn       ast.Expr
+	pos      token.Pos
+	channels map[string][]token.Pos // channel -> send (or close) positions
+}
+
+// checkSelectDeadlocks detects select statements without a default case executed under lock,
+// when all of their receive cases wait for channels fed only by goroutines that need the same lock.
+func (a *Analyzer) checkSelectDeadlocks() {
+	feeds := a.goroutineFeeds()
+	if len(feeds) == 0 {
+		return
+	}
+	senders := a.channelSenders()
+	reported := make(map[token.Pos]bool)
+
+	for _, decl := range a.funcs {
+		fqn := a.declFQN(decl)
+		tracker, ok := a.scopes[fqn]
+		if !ok || !a.isLive(fqn) {
+			continue
+		}
+
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			sel, ok := n.(*ast.SelectStmt)
+			if !ok || reported[sel.Pos()] {
+				return true
+			}
+			comms, ok := selectReceives(sel)
+			if !ok {
+				return true
+			}
+
+			for _, scope := range tracker.Scopes() {
+				if !scopeContains(scope, comms) {
+					continue
+				}
+				key := scope.Key(fqn)
+				if feed, channel, ok := a.lockedFeed(sel, key, feeds, senders); ok {
+					reported[sel.Pos()] = true
+					a.diagnostics = append(a.diagnostics, NewSelectDeadlockError(
+						NewLocation(scope.Pos()),
+						NewLocation(sel.Pos()),
+						NewLocation(feed.pos),
+						channel,
+					))
+					break
+				}
+			}
+			return true
+		})
+	}
+}
+
+// selectReceives returns the communication statements of a select without a default case,
+// if all of them are receives.
+func selectReceives(sel *ast.SelectStmt) ([]ast.Stmt, bool) {
+	var comms []ast.Stmt
+	for _, clause := range sel.Body.List {
+		cc, ok := clause.(*ast.CommClause)
+		if !ok || cc.Comm == nil {
+			return nil, false
+		}
+		if receivedChannel(cc.Comm) == nil {
+			return nil, false
+		}
+		comms = append(comms, cc.Comm)
+	}
+	return comms, len(comms) > 0
+}
+
+// receivedChannel returns the channel expression of a receive statement
+// (<-ch, v := <-ch, v, ok = <-ch).
+func receivedChannel(stmt ast.Stmt) ast.Expr {
+	var expr ast.Expr
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		expr = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			expr = s.Rhs[0]
+		}
+	}
+	if recv, ok := ast.Unparen(expr).(*ast.UnaryExpr); ok && recv.Op == token.ARROW {
+		return recv.X
+	}
+	return nil
+}
+
+// scopeContains checks if all the statements are part of the scope.
+func scopeContains(scope *MutexScope, stmts []ast.Stmt) bool {
+	nodes := scope.Nodes()
+	for _, stmt := range stmts {
+		if !slices.Contains(nodes, ast.Node(stmt)) {
+			return false
+		}
+	}
+	return true
+}
+
+// lockedFeed returns a goroutine needing the mutex, if all the channels the select
+// receives from are fed only by such goroutines.
+func (a *Analyzer) lockedFeed(sel *ast.SelectStmt, key string, feeds []goroutineFeed, senders map[string][]token.Pos) (goroutineFeed, string, bool) {
+	var found goroutineFeed
+	var foundChannel string
+
+	for _, clause := range sel.Body.List {
+		ch := receivedChannel(clause.(*ast.CommClause).Comm)
+		channel := channelKey(ch, a.info)
+		if channel == "" || len(senders[channel]) == 0 {
+			return found, "", false
+		}
+
+		// Every send must happen in a goroutine acquiring the held mutex
+		covered := make(map[token.Pos]bool)
+		for _, feed := range feeds {
+			positions := feed.channels[channel]
+			if len(positions) == 0 || !a.callbackLocks(feed.fn, key) {
+				continue
+			}
+			for _, pos := range positions {
+				covered[pos] = true
+			}
+			if foundChannel == "" {
+				found, foundChannel = feed, StrExpr(ch)
+			}
+		}
+		for _, pos := range senders[channel] {
+			if !covered[pos] {
+				return found, "", false
+			}
+		}
+	}
+
+	return found, foundChannel, foundChannel != ""
+}
+
+// goroutineFeeds returns the functions launched with go statements in the package
+// along with the channels they feed.
+func (a *Analyzer) goroutineFeeds() []goroutineFeed {
+	var feeds []goroutineFeed
+	for _, decl := range a.funcs {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			stmt, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+			body := a.funcBody(stmt.Call.Fun)
+			if body == nil {
+				return true
+			}
+			feeds = append(feeds, goroutineFeed{
+				fn:       stmt.Call.Fun,
+				pos:      stmt.Pos(),
+				channels: a.channelSends(body),
+			})
+			return true
+		})
+	}
+	return feeds
+}
+
+// channelSenders returns the positions of all sends to (and closes of) channels in the package.
+func (a *Analyzer) channelSenders() map[string][]token.Pos {
+	senders := make(map[string][]token.Pos)
+	for _, decl := range a.funcs {
+		for channel, positions := range a.channelSends(decl.Body) {
+			senders[channel] = append(senders[channel], positions...)
+		}
+	}
+	return senders
+}
+
+// channelSends returns the positions of sends to (and closes of) channels within the node.
+func (a *Analyzer) channelSends(node ast.Node) map[string][]token.Pos {
+	sends := make(map[string][]token.Pos)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.SendStmt:
+			if ch := channelKey(s.Chan, a.info); ch != "" {
+				sends[ch] = append(sends[ch], s.Pos())
+			}
+		case *ast.CallExpr:
+			if ident, ok := s.Fun.(*ast.Ident); ok && ident.Name == "close" && len(s.Args) == 1 {
+				if ch := channelKey(s.Args[0], a.info); ch != "" {
+					sends[ch] = append(sends[ch], s.Pos())
+				}
+			}
+		}
+		return true
+	})
+	return sends
+}
+
+// channelKey identifies a channel by its struct field, package or local variable.
+func channelKey(expr ast.Expr, info *types.Info) string {
+	if key := fieldOrVarKey(expr, info); key != "" {
+		return key
+	}
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+		if v, ok := info.ObjectOf(ident).(*types.Var); ok {
+			return fmt.Sprintf("%s@%d", v.Name(), v.Pos())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package mulint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// slowPath is the structure of a function checking without the lock first and acquiring it
+// to check again only if the fast path fails (e.g., a lazily populated cache):
+//
+//	func (c *Cache) get(key string) *Entry {
+//	    if e := c.cached(key); e != nil {
+//	        return e
+//	    }
+//	    c.mu.Lock()
+//	    defer c.mu.Unlock()
+//	    if e, ok := c.items[key]; ok {
+//	        return e
+//	    }
+//	    ...
+//	}
+//
+// Callers already holding the lock should call the locked part (the slow path) instead.
+type slowPath struct {
+	decl *ast.FuncDecl
+	lock ast.Stmt   // the lock of the slow path
+	rest []ast.Stmt // the statements following the lock and its deferred unlock, if split-able
+}
+
+// slowPathOf returns the slow path structure of the function acquiring the lock at the position, if any:
+// a top-level lock preceded by an early return (the fast path) and followed by another one (the recheck),
+// or a lock within an if statement rechecking its condition (see checkDoubleCheckedLocking).
+func (a *Analyzer) slowPathOf(fqn FQN, lockPos token.Pos) *slowPath {
+	decl := a.funcDecl(fqn)
+	if decl == nil || decl.Body == nil {
+		return nil
+	}
+
+	list := decl.Body.List
+	for i, stmt := range list {
+		if outer, ok := stmt.(*ast.IfStmt); ok && outer.Pos() < lockPos && lockPos < outer.Body.End() {
+			return a.doubleCheckedSlowPath(decl, outer, lockPos)
+		}
+		if stmt.Pos() != lockPos {
+			continue
+		}
+		if !containsEarlyReturn(list[:i]) || !containsEarlyReturn(list[i+1:]) {
+			return nil
+		}
+		path := &slowPath{decl: decl, lock: stmt}
+		subject := subjectForLockCall(stmt)
+		if i+2 < len(list) && subject != nil {
+			if deferred := subjectForDeferUnlockCall(list[i+1]); deferred != nil && StrExpr(deferred) == StrExpr(subject) {
+				path.rest = list[i+2:]
+			}
+		}
+		return path
+	}
+	return nil
+}
+
+// doubleCheckedSlowPath returns the slow path of the lock within the if statement if it's
+// followed by an if statement rechecking the outer condition.
+func (a *Analyzer) doubleCheckedSlowPath(decl *ast.FuncDecl, outer *ast.IfStmt, lockPos token.Pos) *slowPath {
+	for i, stmt := range outer.Body.List {
+		if stmt.Pos() != lockPos {
+			continue
+		}
+		for _, next := range outer.Body.List[i+1:] {
+			if inner, ok := next.(*ast.IfStmt); ok && rechecked(outer.Cond, inner.Cond, a.info) != "" {
+				return &slowPath{decl: decl, lock: stmt}
+			}
+		}
+	}
+	return nil
+}
+
+// containsEarlyReturn checks if one of the statements is an if statement returning early.
+func containsEarlyReturn(list []ast.Stmt) bool {
+	for _, stmt := range list {
+		if ifStmt, ok := stmt.(*ast.IfStmt); ok && isEarlyReturn(ifStmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestSlowPath points the reentrant lock error reported for the call at the slow path of the callee
+// (see slowPath), unless the callee has a *Locked variant already (see suggestLockedVariant).
+// When the slow path consists of the statements following the lock and its deferred unlock, the fix
+// moves them to a new *Locked function called by the callee and calls it instead.
+func (a *Analyzer) suggestSlowPath(call *ast.CallExpr, callee FQN, site *LockSite) {
+	if site == nil || site.FQN != callee {
+		return
+	}
+	if _, ok := a.lockedVariant(callee); ok {
+		return
+	}
+	path := a.slowPathOf(callee, site.Pos)
+	if path == nil {
+		return
+	}
+
+	for i := len(a.errors) - 1; i >= 0; i-- {
+		if a.errors[i].secondLock.pos != call.Pos() {
+			continue
+		}
+		name := path.decl.Name.Name
+		a.errors[i].hint = fmt.Sprintf("%s acquires the lock only if the check made without it fails; its locked part can be called directly, as the lock is already held", name)
+		a.errors[i].fix = a.slowPathFix(call, path)
+		return
+	}
+}
+
+// slowPathFix returns the fix moving the slow path of the function to a new *Locked function
+// and calling it at the call. Only the slow paths not using the variables declared before the lock
+// are moved, and the functions with unnamed parameters aren't split.
+func (a *Analyzer) slowPathFix(call *ast.CallExpr, path *slowPath) *analysis.SuggestedFix {
+	ident := calleeIdent(call)
+	if len(path.rest) == 0 || ident == nil {
+		return nil
+	}
+	decl := path.decl
+	name := decl.Name.Name + "Locked"
+	if a.declaresName(decl, name) || a.usesEarlierLocals(decl, path) {
+		return nil
+	}
+
+	var args []string
+	params := decl.Type.Params.List
+	for _, field := range params {
+		if len(field.Names) == 0 {
+			return nil
+		}
+		for _, param := range field.Names {
+			if param.Name == "_" {
+				return nil
+			}
+			args = append(args, param.Name)
+		}
+	}
+	if n := len(params); n > 0 {
+		if _, variadic := params[n-1].Type.(*ast.Ellipsis); variadic {
+			args[len(args)-1] += "..."
+		}
+	}
+	callee := name
+	recv := ""
+	if decl.Recv != nil {
+		if len(decl.Recv.List) == 0 || len(decl.Recv.List[0].Names) == 0 || decl.Recv.List[0].Names[0].Name == "_" {
+			return nil
+		}
+		callee = decl.Recv.List[0].Names[0].Name + "." + name
+		recv = a.source(decl.Recv.Pos(), decl.Recv.End()) + " "
+	}
+	inner := fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))
+	if decl.Type.Results != nil && len(decl.Type.Results.List) > 0 {
+		inner = "return " + inner
+	}
+
+	first, last := path.rest[0], path.rest[len(path.rest)-1]
+	tokFile := a.pass.Fset.File(first.Pos())
+	body := a.source(tokFile.LineStart(tokFile.Line(first.Pos())), last.End())
+	split := fmt.Sprintf("\n\n// %s is the part of %s run under the lock.\nfunc %s%s%s {\n%s\n}",
+		name, decl.Name.Name, recv, name, a.source(decl.Name.End(), decl.Type.End()), body)
+
+	return &analysis.SuggestedFix{
+		Message: fmt.Sprintf("Move the locked part of %s to %s and call it instead", decl.Name.Name, name),
+		TextEdits: []analysis.TextEdit{
+			{Pos: first.Pos(), End: last.End(), NewText: []byte(inner)},
+			{Pos: decl.End(), End: decl.End(), NewText: []byte(split)},
+			{Pos: ident.Pos(), End: ident.End(), NewText: []byte(name)},
+		},
+	}
+}
+
+// declaresName checks if the name is taken by a package-level object or, for methods,
+// by a field or method of the receiver type.
+func (a *Analyzer) declaresName(decl *ast.FuncDecl, name string) bool {
+	if a.pass.Pkg.Scope().Lookup(name) != nil {
+		return true
+	}
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return false
+	}
+	recv := a.info.TypeOf(decl.Recv.List[0].Type)
+	if recv == nil {
+		return true
+	}
+	obj, _, _ := types.LookupFieldOrMethod(recv, true, a.pass.Pkg, name)
+	return obj != nil
+}
+
+// usesEarlierLocals checks if the slow path uses variables declared in the function before the lock.
+func (a *Analyzer) usesEarlierLocals(decl *ast.FuncDecl, path *slowPath) bool {
+	uses := false
+	for _, stmt := range path.rest {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok {
+				if obj := a.info.Uses[ident]; obj != nil && decl.Body.Pos() <= obj.Pos() && obj.Pos() < path.lock.Pos() {
+					uses = true
+				}
+			}
+			return !uses
+		})
+	}
+	return uses
+}
+
+// source returns the source code between the positions.
+func (a *Analyzer) source(pos, end token.Pos) string {
+	tokFile := a.pass.Fset.File(pos)
+	data, err := os.ReadFile(tokFile.Name())
+	if err != nil {
+		return ""
+	}
+	return string(data[tokFile.Offset(pos):tokFile.Offset(end)])
+}
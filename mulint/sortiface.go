@@ -0,0 +1,143 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// sortInterfaceCallers are the functions calling the methods of their sort.Interface (heap.Interface)
+// argument before returning, i.e., under the caller's locks, along with the methods they call.
+// Less and Swap are called many times per call, so they must not acquire the locks held by the caller
+// (a deadlock) and better not acquire any (a performance bug).
+var sortInterfaceCallers = map[FQN][]string{
+	"sort.Sort":     {"Len", "Less", "Swap"},
+	"sort.Stable":   {"Len", "Less", "Swap"},
+	"sort.IsSorted": {"Len", "Less"},
+
+	"container/heap.Init":   {"Len", "Less", "Swap"},
+	"container/heap.Push":   {"Len", "Less", "Swap", "Push"},
+	"container/heap.Pop":    {"Len", "Less", "Swap", "Pop"},
+	"container/heap.Remove": {"Len", "Less", "Swap", "Pop"},
+	"container/heap.Fix":    {"Len", "Less", "Swap"},
+}
+
+// checkSortCall checks if the sort.Interface methods called by sort.Sort (sort.Stable, heap.Push, etc.)
+// acquire the mutex held by the scope, e.g. sort.Sort(s) under s.mu with Less locking s.mu.
+func (a *Analyzer) checkSortCall(scope *MutexScope, call *ast.CallExpr, currentFQN FQN) {
+	recv, methods := a.sortMethods(call)
+	for _, method := range methods {
+		methodScope := a.receiverScope(recv, method, a.methodIndex(recv, method), scope)
+		if methodScope == nil {
+			if !scope.IsGlobal() {
+				continue
+			}
+			methodScope = scope
+		}
+		if site := a.findTransitiveLock(FromFunc(method), methodScope, make(map[FQN]*LockSite)); site != nil {
+			a.recordErrorVia(currentFQN, scope, call, site)
+			return
+		}
+	}
+}
+
+// sortMethods returns the value the call to a sort.Interface caller (see sortInterfaceCallers) calls
+// the methods of, along with the methods. Interface values aren't resolved.
+func (a *Analyzer) sortMethods(call *ast.CallExpr) (ast.Expr, []*types.Func) {
+	pkg, name, ok := GetCallInfo(call, a.info)
+	if !ok || len(call.Args) == 0 {
+		return nil, nil
+	}
+	names, ok := sortInterfaceCallers[FromCallInfo(pkg, name)]
+	if !ok {
+		return nil, nil
+	}
+
+	recv := sortReceiver(call.Args[0], a.info)
+	t := a.info.TypeOf(recv)
+	if t == nil || types.IsInterface(t) {
+		return nil, nil
+	}
+	var methods []*types.Func
+	for _, name := range names {
+		obj, _, _ := types.LookupFieldOrMethod(t, true, a.pass.Pkg, name)
+		if method, ok := obj.(*types.Func); ok {
+			methods = append(methods, method)
+		}
+	}
+	return recv, methods
+}
+
+// sortReceiver returns the value the sort.Interface methods are called on: the argument itself,
+// the value it points to, or the value wrapped with sort.Reverse.
+func sortReceiver(arg ast.Expr, info *types.Info) ast.Expr {
+	switch e := ast.Unparen(arg).(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return sortReceiver(e.X, info)
+		}
+	case *ast.CallExpr:
+		if pkg, name, ok := GetCallInfo(e, info); ok && FromCallInfo(pkg, name) == "sort.Reverse" && len(e.Args) == 1 {
+			return sortReceiver(e.Args[0], info)
+		}
+	}
+	return ast.Unparen(arg)
+}
+
+// receiverScope returns the scope with the mutex selector expressed via the receiver name
+// of the method called on recv (see calleeScope), or nil if the mutex doesn't belong to recv.
+// The index is the path to the method through the embedded fields (see types.Selection.Index).
+func (a *Analyzer) receiverScope(recv ast.Expr, method *types.Func, index []int, scope *MutexScope) *MutexScope {
+	sig, ok := method.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil || sig.Recv().Name() == "" || sig.Recv().Name() == "_" {
+		return nil
+	}
+	field, ok := strings.CutPrefix(scope.Selector(), LockSelector(recv, a.info)+".")
+	if !ok {
+		return nil
+	}
+	t := a.info.TypeOf(recv)
+	if t == nil {
+		return nil
+	}
+	if path, ok := a.expandFields(t, strings.Split(field, ".")); ok {
+		field = strings.Join(path, ".")
+	}
+	if len(index) == 0 {
+		return nil
+	}
+	if embedded := embeddedFields(t, index[:len(index)-1]); len(embedded) > 0 {
+		if field, ok = strings.CutPrefix(field, strings.Join(embedded, ".")+"."); !ok {
+			return nil
+		}
+	}
+	return NewMutexScope(sig.Recv().Name()+"."+field, scope.Pos())
+}
+
+// methodIndex returns the path to the method of the value's type through the embedded fields.
+func (a *Analyzer) methodIndex(recv ast.Expr, method *types.Func) []int {
+	_, index, _ := types.LookupFieldOrMethod(a.info.TypeOf(recv), true, method.Pkg(), method.Name())
+	return index
+}
+
+// sortedMethods returns the sort.Interface (heap.Interface) methods called by the calls
+// to sort.Sort, sort.Stable, heap.Push, etc. made by the package functions.
+func (a *Analyzer) sortedMethods() map[FQN]bool {
+	sorted := make(map[FQN]bool)
+	for _, fn := range a.funcs {
+		if fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				_, methods := a.sortMethods(call)
+				for _, method := range methods {
+					sorted[FromFunc(method)] = true
+				}
+			}
+			return true
+		})
+	}
+	return sorted
+}
@@ -0,0 +1,100 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// checkWriterStarvation reports read lock scopes containing loops or blocking calls.
+// Readers holding the lock for long keep the writers waiting, and a pending Lock()
+// blocks new readers too, so such scopes are potential throughput bottlenecks.
+// Only the first loop or blocking call of a scope is reported.
+func (a *Analyzer) checkWriterStarvation() {
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+		decl := a.funcDecl(fqn)
+		if decl == nil || decl.Body == nil {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			if !scope.IsRead() || scope.IsFresh() || reported[scope.Pos()] {
+				continue
+			}
+
+			pos, blocking := a.firstBlockingCall(scope)
+			if loop := firstLoopUnder(decl.Body, scope); loop.IsValid() && (!pos.IsValid() || loop < pos) {
+				pos, blocking = loop, ""
+			}
+			if !pos.IsValid() {
+				continue
+			}
+
+			reported[scope.Pos()] = true
+			a.diagnostics = append(a.diagnostics, NewWriterStarvationError(
+				NewLocation(scope.Pos()),
+				NewLocation(pos),
+				blocking,
+			))
+		}
+	}
+}
+
+// firstLoopUnder returns the position of the first loop executed while holding the scope's lock:
+// a loop started after acquiring the lock and containing the statements of the scope.
+func firstLoopUnder(body *ast.BlockStmt, scope *MutexScope) token.Pos {
+	first := token.NoPos
+	ast.Inspect(body, func(n ast.Node) bool {
+		if first.IsValid() {
+			return false
+		}
+		switch n.(type) {
+		case *ast.FuncLit:
+			// Func literals run with a different lock state
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			if n.Pos() > scope.Pos() && containsScopeNode(n, scope) {
+				first = n.Pos()
+				return false
+			}
+		}
+		return true
+	})
+	return first
+}
+
+// containsScopeNode checks if any statement of the scope is located within the node.
+func containsScopeNode(n ast.Node, scope *MutexScope) bool {
+	for _, node := range scope.Nodes() {
+		if n.Pos() <= node.Pos() && node.End() <= n.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// firstBlockingCall returns the position of the first call within the scope reaching
+// a function declared as blocking (see findBlockingCall), along with the blocking function.
+func (a *Analyzer) firstBlockingCall(scope *MutexScope) (token.Pos, FQN) {
+	first := token.NoPos
+	var blocking FQN
+	for _, node := range scope.Nodes() {
+		inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+			if first.IsValid() && first < call.Pos() {
+				return
+			}
+			pkg, name, ok := GetCallInfo(call, a.info)
+			if !ok {
+				return
+			}
+			if fn, ok := a.findBlockingCall(FromCallInfo(pkg, name)); ok {
+				first, blocking = call.Pos(), fn
+			}
+		})
+	}
+	return first, blocking
+}
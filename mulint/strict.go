@@ -0,0 +1,225 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// strictBlockingFuncs are the standard library functions treated as blocking in addition
+// to the ones declared with EffectBlocks. Calls reaching them are reported in the strict profile.
+var strictBlockingFuncs = []FQN{
+	"time.Sleep",
+	"sync.WaitGroup:Wait",
+	"net.Dial",
+	"net/http.Get",
+	"net/http.Post",
+	"net/http.Client:Do",
+	"os/exec.Cmd:Run",
+	"os/exec.Cmd:Wait",
+}
+
+// Channel operations that may block (reported under lock in the strict profile).
+const (
+	blockingSend    = "channel send"
+	blockingReceive = "channel receive"
+	blockingRange   = "range over channel"
+	blockingSelect  = "select without default"
+)
+
+// isStrict checks if the strict profile is enabled for the package:
+// either with -strict or by matching one of the -strict-packages patterns.
+func (a *Analyzer) isStrict() bool {
+	return a.config.Strict || matchesPackage(a.pass.Pkg.Path(), splitList(a.config.StrictPackages))
+}
+
+// matchesPackage checks if the package path matches one of the patterns:
+// an exact path or a path prefix followed by "/..." (e.g. "github.com/acme/app/sync/...").
+func matchesPackage(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if path == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlockingFunc checks if the function is declared as blocking or is one of the well-known
+// blocking functions of the standard library (only reported in the strict profile, see findBlockingCall).
+func (a *Analyzer) isBlockingFunc(fqn FQN) bool {
+	return a.externs().Has(fqn, EffectBlocks) || slices.Contains(strictBlockingFuncs, fqn)
+}
+
+// checkBlockingOps detects channel operations that may block while holding a lock:
+// sends, receives, ranging over channels and selects without a default case.
+// Operations of goroutines and func literals are not considered, as they may run without the lock.
+func (a *Analyzer) checkBlockingOps() {
+	deadlocks := make(map[token.Pos]bool)
+	for _, d := range a.diagnostics {
+		if e, ok := d.(SelectDeadlockError); ok {
+			deadlocks[e.selectPos.pos] = true
+		}
+	}
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		tracker, ok := a.scopes[fqn]
+		if fn.Body == nil || !ok || !a.isLive(fqn) {
+			continue
+		}
+
+		inspectBlockingOps(fn.Body, a.info, func(op ast.Node, held ast.Node, kind string) {
+			if deadlocks[op.Pos()] {
+				return
+			}
+			scope := heldScopeAt(tracker, held)
+			if scope == nil {
+				return
+			}
+			a.diagnostics = append(a.diagnostics, NewBlockingOpError(NewLocation(scope.Pos()), NewLocation(op.Pos()), kind))
+		})
+	}
+}
+
+// inspectBlockingOps calls fn for each channel operation of the node that may block,
+// along with the part of the operation evaluated first (which lock scopes track).
+// The communications of selects with a default case never block.
+func inspectBlockingOps(node ast.Node, info *types.Info, fn func(op ast.Node, held ast.Node, kind string)) {
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit, *ast.GoStmt:
+			return false
+		case *ast.SendStmt:
+			fn(s, s, blockingSend)
+		case *ast.UnaryExpr:
+			if s.Op == token.ARROW {
+				fn(s, s, blockingReceive)
+			}
+		case *ast.RangeStmt:
+			if _, ok := info.TypeOf(s.X).Underlying().(*types.Chan); ok {
+				fn(s, s.X, blockingRange)
+			}
+		case *ast.SelectStmt:
+			if comm := firstComm(s); comm != nil && !hasDefaultCase(s) {
+				fn(s, comm, blockingSelect)
+			}
+			// The communications are covered by the select itself
+			for _, clause := range s.Body.List {
+				for _, stmt := range clause.(*ast.CommClause).Body {
+					ast.Inspect(stmt, visit)
+				}
+			}
+			return false
+		}
+		return true
+	}
+	ast.Inspect(node, visit)
+}
+
+// heldScopeAt returns the lock scope the node belongs to, if any.
+func heldScopeAt(tracker *LockTracker, node ast.Node) *MutexScope {
+	for _, scope := range tracker.Scopes() {
+		for _, n := range scope.Nodes() {
+			if n.Pos() <= node.Pos() && node.End() <= n.End() {
+				return scope
+			}
+		}
+	}
+	return nil
+}
+
+// firstComm returns the communication of the first non-default case of the select statement.
+func firstComm(s *ast.SelectStmt) ast.Stmt {
+	for _, clause := range s.Body.List {
+		if comm := clause.(*ast.CommClause).Comm; comm != nil {
+			return comm
+		}
+	}
+	return nil
+}
+
+// hasDefaultCase checks if the select statement has a default case.
+func hasDefaultCase(s *ast.SelectStmt) bool {
+	for _, clause := range s.Body.List {
+		if clause.(*ast.CommClause).Comm == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRequiredLocks enforces the //mulint:requires annotations: the annotated package functions
+// must be called while holding the declared mutexes. Calls from functions declaring the same
+// requirement and calls on new values that haven't escaped yet are fine.
+func (a *Analyzer) checkRequiredLocks() {
+	required := make(map[FQN][]string)
+	for _, fn := range a.funcs {
+		if requires := FuncDirectives(fn)[requiresDirective]; len(requires) > 0 {
+			required[a.declFQN(fn)] = requires
+		}
+	}
+	if len(required) == 0 {
+		return
+	}
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if fn.Body == nil || !a.isLive(fqn) {
+			continue
+		}
+
+		held := a.heldAtCalls(fqn)
+		ownRequires := FuncDirectives(fn)[requiresDirective]
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			// Func literals may run with a different lock state
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			pkg, name, ok := GetCallInfo(call, a.info)
+			if !ok {
+				return true
+			}
+			callee := FromCallInfo(pkg, name)
+			requires, ok := required[callee]
+			if !ok {
+				return true
+			}
+			if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && a.isFreshAt(fn.Body, sel, call.Pos()) {
+				return true
+			}
+
+			for _, mutex := range requires {
+				if holdsRequired(held[call.Pos()], ownRequires, mutex) {
+					continue
+				}
+				a.diagnostics = append(a.diagnostics, NewRequiredLockError(NewLocation(call.Pos()), callee, mutex))
+			}
+			return true
+		})
+	}
+}
+
+// holdsRequired checks if the required mutex ("mu" or "Queue.mu") is among the held mutex keys
+// or the requirements of the calling function itself.
+func holdsRequired(held, ownRequires []string, mutex string) bool {
+	for _, key := range held {
+		if requiresLock([]string{mutex}, key) {
+			return true
+		}
+	}
+	return slices.Contains(ownRequires, mutex)
+}
@@ -0,0 +1,58 @@
+package mulint
+
+import (
+	"go/ast"
+	"sort"
+)
+
+// requiresDirective declares the mutexes a function expects to be held by its callers,
+// e.g. "//mulint:requires mu".
+const requiresDirective = "requires"
+
+// LockSummary describes the lock behavior of a function.
+type LockSummary struct {
+	FQN            FQN
+	Acquires       []string // mutexes acquired directly or transitively
+	ReturnsHolding []string // mutexes that may still be held when the function returns
+	Requires       []string // mutexes the function expects to be held (per annotations)
+}
+
+// Summarize builds the lock summary for a function declaration.
+func (a *Analyzer) Summarize(fn *ast.FuncDecl) LockSummary {
+	fqn := a.declFQN(fn)
+	return LockSummary{
+		FQN:            fqn,
+		Acquires:       a.LocksReachable(fqn),
+		ReturnsHolding: a.summaryOf(fqn).ReturnsHolding,
+		Requires:       FuncDirectives(fn)[requiresDirective],
+	}
+}
+
+// LocksReachable returns the mutexes acquired by a function or any function
+// reachable from it through the call graph.
+func (a *Analyzer) LocksReachable(fqn FQN) []string {
+	return a.reachableSummary(fqn).locks
+}
+
+// summarizeExported builds lock summaries for all exported functions and methods.
+func (a *Analyzer) summarizeExported() {
+	for _, fn := range a.funcs {
+		if !fn.Name.IsExported() {
+			continue
+		}
+		summary := a.Summarize(fn)
+		if summary.FQN == "" {
+			continue
+		}
+		a.diagnostics = append(a.diagnostics, NewLockSummaryReport(NewLocation(fn.Name.Pos()), summary))
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package mulint
+
+import (
+	"go/ast"
+	"slices"
+)
+
+// wrapperCall is a lock scope acquired by a function via a lock wrapper.
+type wrapperCall struct {
+	fqn   FQN
+	scope *MutexScope
+}
+
+// checkSuspectWrappers reports lock wrappers none of the callers of which release the lock:
+// such functions are more likely missing an unlock than meant to return holding the lock,
+// while treating them as wrappers moves the missing unlocks to the callers (if reported at all).
+// Callers returning holding the lock pass it on to their own callers, which aren't followed,
+// so the wrappers having such callers called within the package aren't reported. Neither are
+// the exported ones, as the callers releasing the lock may be in other packages.
+func (a *Analyzer) checkSuspectWrappers() {
+	calls := make(map[FQN][]wrapperCall)
+	for fqn, tracker := range a.scopes {
+		for _, scope := range tracker.Scopes() {
+			if w := scope.Wrapper(); w != nil && !scope.IsFresh() {
+				calls[w.FQN] = append(calls[w.FQN], wrapperCall{fqn: fqn, scope: scope})
+			}
+		}
+	}
+	called := make(map[FQN]bool)
+	for _, callees := range a.calls {
+		for _, callee := range callees {
+			called[callee] = true
+		}
+	}
+	released := func(wrapper FQN) bool {
+		return slices.ContainsFunc(calls[wrapper], func(call wrapperCall) bool {
+			return call.scope.IsUnlocked() || called[call.fqn]
+		})
+	}
+
+	for _, fn := range a.funcs {
+		fqn := a.declFQN(fn)
+		if ast.IsExported(fn.Name.Name) || !a.isLive(fqn) || !a.wrappers.IsLockWrapper(fqn) || len(calls[fqn]) == 0 || released(fqn) {
+			continue
+		}
+		wrapper, _ := a.wrappers.Get(fqn)
+
+		sites := make([]Location, 0, len(calls[fqn]))
+		for _, call := range calls[fqn] {
+			sites = append(sites, NewLocation(call.scope.Pos()))
+		}
+		slices.SortFunc(sites, func(x, y Location) int { return int(x.Pos() - y.Pos()) })
+		sites = slices.CompactFunc(sites, func(x, y Location) bool { return x.Pos() == y.Pos() })
+		a.diagnostics = append(a.diagnostics, NewSuspectWrapperError(NewLocation(wrapper.LockPos), fqn, sites))
+	}
+}
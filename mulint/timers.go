@@ -0,0 +1,160 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// TimerCallback is a function scheduled via time.AfterFunc.
+type TimerCallback struct {
+	Fn  ast.Expr  // the callback (a func literal or a function value)
+	Pos token.Pos // position of the time.AfterFunc call
+}
+
+// TimerRegistry tracks callbacks of timers created with time.AfterFunc and stored
+// in struct fields or package variables ("Server.timer").
+type TimerRegistry struct {
+	callbacks map[string]TimerCallback
+	info      *types.Info
+}
+
+func NewTimerRegistry(info *types.Info) *TimerRegistry {
+	return &TimerRegistry{
+		callbacks: make(map[string]TimerCallback),
+		info:      info,
+	}
+}
+
+// Collect records timers initialized in the node: s.timer = time.AfterFunc(d, fn).
+func (r *TimerRegistry) Collect(node ast.Node) {
+	forEachInit(node, r.info, func(target ast.Expr, key string, value ast.Expr) {
+		call, ok := value.(*ast.CallExpr)
+		if !ok || key == "" || len(call.Args) != 2 {
+			return
+		}
+		if pkg, name, ok := GetCallInfo(call, r.info); ok && pkg == "time" && name == "AfterFunc" {
+			r.callbacks[key] = TimerCallback{Fn: call.Args[1], Pos: call.Pos()}
+		}
+	})
+}
+
+// CallbackForStop returns the callback of the timer if the call is a Stop() on a known timer.
+func (r *TimerRegistry) CallbackForStop(call *ast.CallExpr) (TimerCallback, bool) {
+	selector := SelectorExpr(call)
+	if selector == nil || selector.Sel.Name != "Stop" {
+		return TimerCallback{}, false
+	}
+	key := fieldOrVarKey(selector.X, r.info)
+	if key == "" {
+		return TimerCallback{}, false
+	}
+	cb, ok := r.callbacks[key]
+	return cb, ok
+}
+
+// checkTimerCallbackWaits detects scopes that stop a timer and then wait for its callback
+// to signal completion, while the callback needs the mutex held by the scope:
+//
+//	s.mu.Lock()
+//	if !s.timer.Stop() {
+//	    <-s.done // the callback is blocked on s.mu and never closes s.done
+//	}
+func (a *Analyzer) checkTimerCallbackWaits() {
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			key := scope.Key(fqn)
+
+			var stopped []TimerCallback
+			var receives []*ast.UnaryExpr
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if cb, ok := a.timers.CallbackForStop(call); ok {
+						stopped = append(stopped, cb)
+					}
+				})
+				ast.Inspect(node, func(n ast.Node) bool {
+					if _, ok := n.(*ast.FuncLit); ok {
+						return false
+					}
+					if recv, ok := n.(*ast.UnaryExpr); ok && recv.Op == token.ARROW {
+						receives = append(receives, recv)
+					}
+					return true
+				})
+			}
+
+			for _, cb := range stopped {
+				if !a.callbackLocks(cb.Fn, key) {
+					continue
+				}
+				signaled := a.signaledChannels(cb.Fn)
+				for _, recv := range receives {
+					if ch := fieldOrVarKey(recv.X, a.info); ch != "" && signaled[ch] && !reported[recv.Pos()] {
+						reported[recv.Pos()] = true
+						a.diagnostics = append(a.diagnostics, NewTimerCallbackWaitError(
+							NewLocation(scope.Pos()),
+							NewLocation(recv.Pos()),
+							NewLocation(cb.Pos),
+						))
+					}
+				}
+			}
+		}
+	}
+}
+
+// signaledChannels returns the channels (by field or variable key) a callback
+// sends to or closes.
+func (a *Analyzer) signaledChannels(fn ast.Expr) map[string]bool {
+	signaled := make(map[string]bool)
+	body := a.funcBody(fn)
+	if body == nil {
+		return signaled
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.SendStmt:
+			if ch := fieldOrVarKey(s.Chan, a.info); ch != "" {
+				signaled[ch] = true
+			}
+		case *ast.CallExpr:
+			if ident, ok := s.Fun.(*ast.Ident); ok && ident.Name == "close" && len(s.Args) == 1 {
+				if ch := fieldOrVarKey(s.Args[0], a.info); ch != "" {
+					signaled[ch] = true
+				}
+			}
+		}
+		return true
+	})
+	return signaled
+}
+
+// funcBody returns the body of a func literal or of a function declared in the package.
+func (a *Analyzer) funcBody(fn ast.Expr) *ast.BlockStmt {
+	var obj types.Object
+	switch f := fn.(type) {
+	case *ast.FuncLit:
+		return f.Body
+	case *ast.Ident:
+		obj = a.info.ObjectOf(f)
+	case *ast.SelectorExpr:
+		obj = a.info.ObjectOf(f.Sel)
+	}
+
+	fnObj, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	if decl := a.funcDecl(FromFunc(fnObj)); decl != nil {
+		return decl.Body
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package mulint
+
+import (
+	"go/ast"
+)
+
+// checkUseAfterUnlock reports the accesses to fields guarded by a mutex (see GuardIndex) made
+// after releasing it in the same block and before acquiring it again (c.mu.Unlock(); c.items = nil),
+// which are frequently left behind by refactorings shrinking critical sections.
+// Only the fields of the value owning the mutex and the first access after each unlock are reported.
+// Func literals may run at any time, so they are not checked, except for the deferred ones registered
+// before a deferred unlock in the same block: deferred calls run in the reverse order, i.e., after the unlock.
+func (a *Analyzer) checkUseAfterUnlock() {
+	guards := a.guardIndex()
+
+	for _, fn := range a.funcs {
+		if fn.Body == nil || !a.isLive(a.declFQN(fn)) {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			for _, list := range stmtLists(n) {
+				for i, stmt := range list {
+					if _, ok := stmt.(*ast.DeferStmt); ok {
+						a.checkDeferredUseAfterUnlock(list[:i], stmt, guards)
+						continue
+					}
+					subject := subjectForUnlockCall(stmt)
+					if subject == nil || !IsMutexType(subject, a.info) {
+						continue
+					}
+					mutexSel, ok := ast.Unparen(subject).(*ast.SelectorExpr)
+					if !ok || packageVar(mutexSel, a.info) != nil {
+						continue
+					}
+
+					mutex := typedMutexKey(subject, a.info)
+					owner := LockSelector(mutexSel.X, a.info)
+					if access := a.guardedAccessAfter(list[i+1:], LockSelector(subject, a.info), owner, mutex, guards); access != nil {
+						a.diagnostics = append(a.diagnostics, NewUseAfterUnlockError(
+							NewLocation(access.Pos()),
+							NewLocation(stmt.Pos()),
+							StrExpr(access),
+							StrExpr(subject),
+						))
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// checkDeferredUseAfterUnlock reports the accesses to guarded fields made by the func literals
+// deferred before the deferred unlock (defer func() { log(s.count) }(); s.mu.Lock(); defer s.mu.Unlock()),
+// which run after it when the function returns.
+func (a *Analyzer) checkDeferredUseAfterUnlock(before []ast.Stmt, unlock ast.Stmt, guards *GuardIndex) {
+	subject := deferredUnlockSubject(unlock)
+	if subject == nil || !IsMutexType(subject, a.info) {
+		return
+	}
+	mutexSel, ok := ast.Unparen(subject).(*ast.SelectorExpr)
+	if !ok || packageVar(mutexSel, a.info) != nil {
+		return
+	}
+
+	mutex := typedMutexKey(subject, a.info)
+	owner := LockSelector(mutexSel.X, a.info)
+	for _, stmt := range before {
+		deferStmt, ok := stmt.(*ast.DeferStmt)
+		if !ok || deferredUnlockSubject(deferStmt) != nil {
+			continue
+		}
+		funcLit, ok := ast.Unparen(deferStmt.Call.Fun).(*ast.FuncLit)
+		if !ok || funcLit.Body == nil {
+			continue
+		}
+		if access := a.guardedAccessAfter(funcLit.Body.List, LockSelector(subject, a.info), owner, mutex, guards); access != nil {
+			a.diagnostics = append(a.diagnostics, NewDeferredUseAfterUnlockError(
+				NewLocation(access.Pos()),
+				NewLocation(unlock.Pos()),
+				StrExpr(access),
+				StrExpr(subject),
+			))
+		}
+	}
+}
+
+// guardedAccessAfter returns the first access to a field of the owner guarded by the mutex
+// in the statements, up to the statement acquiring the mutex again.
+func (a *Analyzer) guardedAccessAfter(list []ast.Stmt, selector, owner, mutex string, guards *GuardIndex) *ast.SelectorExpr {
+	for _, stmt := range list {
+		var access *ast.SelectorExpr
+		relocked := false
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if access != nil || relocked {
+				return false
+			}
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if subject := subjectForLockCall(n); subject != nil && IsMutexType(subject, a.info) && LockSelector(subject, a.info) == selector {
+				relocked = true
+				return false
+			}
+			if field := guardableField(n, a.info); field != "" && guards.IsGuardedBy(field, mutex) {
+				if sel := n.(*ast.SelectorExpr); LockSelector(sel.X, a.info) == owner {
+					access = sel
+					return false
+				}
+			}
+			return true
+		})
+		if access != nil || relocked {
+			return access
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package mulint
+
+import (
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Reasons the calls can't be verified.
+const (
+	unverifiableInterface = "interface method"
+	unverifiableFuncValue = "function value"
+	unverifiableExternal  = "external function without a summary"
+)
+
+// checkUnverifiableCalls reports the calls made under lock the analysis can't follow,
+// so reentrant locks through them would go unnoticed: interface methods and function values
+// not resolved by the call graph (see -callgraph) and functions of other modules
+// without declared effects (see -extern-summaries). Standard library functions are trusted.
+func (a *Analyzer) checkUnverifiableCalls() {
+	reported := make(map[token.Pos]bool)
+
+	for fqn, tracker := range a.scopes {
+		if !a.isLive(fqn) {
+			continue
+		}
+
+		for _, scope := range tracker.Scopes() {
+			for _, node := range scope.Nodes() {
+				inspectScopeCalls(node, a.info, func(call *ast.CallExpr) {
+					if reported[call.Pos()] {
+						return
+					}
+					callee, reason := a.unverifiableCall(call)
+					if reason == "" {
+						return
+					}
+					reported[call.Pos()] = true
+					a.diagnostics = append(a.diagnostics, NewUnverifiableCallError(
+						NewLocation(scope.Pos()),
+						NewLocation(call.Pos()),
+						callee,
+						reason,
+					))
+				})
+			}
+		}
+	}
+}
+
+// unverifiableCall returns the callee and the reason the call can't be analyzed,
+// or an empty reason if the call can be verified.
+func (a *Analyzer) unverifiableCall(call *ast.CallExpr) (string, string) {
+	if len(a.dynamicCalls[call.Lparen]) > 0 {
+		return "", ""
+	}
+	fun := ast.Unparen(call.Fun)
+	if _, isLit := fun.(*ast.FuncLit); isLit {
+		return "", ""
+	}
+	if tv, ok := a.info.Types[fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+		return "", ""
+	}
+	// Lock calls are tracked on their own
+	for _, methods := range [][]string{lockMethods, unlockMethods} {
+		if subject := SubjectForCall(call, methods); subject != nil && IsMutexType(subject, a.info) {
+			return "", ""
+		}
+	}
+
+	fn, ok := typeutil.Callee(a.info, call).(*types.Func)
+	if !ok {
+		return StrExpr(fun), unverifiableFuncValue
+	}
+	fqn := FromFunc(fn)
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil && types.IsInterface(sig.Recv().Type()) {
+		return fqn.ShortName(), unverifiableInterface
+	}
+	if fn.Pkg() == nil || fn.Pkg() == a.pass.Pkg || isStdPackage(fn.Pkg().Path()) {
+		return "", ""
+	}
+	if len(a.externs()[fqn]) > 0 {
+		return "", ""
+	}
+	return fqn.ShortName(), unverifiableExternal
+}
+
+// isStdPackage checks if the package path belongs to the standard library
+// (its first element has no dot, unlike module paths).
+func isStdPackage(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// isStdLibrary checks if the analyzed package belongs to the standard library: its files are in GOROOT.
+// Unlike isStdPackage, it tells the standard library apart from the GOPATH packages
+// without a dot in their paths (e.g., the analysistest fixtures).
+func isStdLibrary(pass *analysis.Pass) bool {
+	if build.Default.GOROOT == "" || len(pass.Files) == 0 {
+		return isStdPackage(pass.Pkg.Path())
+	}
+	file := pass.Fset.File(pass.Files[0].Pos())
+	src := filepath.Join(build.Default.GOROOT, "src") + string(filepath.Separator)
+	return file != nil && strings.HasPrefix(file.Name(), src)
+}
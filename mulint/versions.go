@@ -0,0 +1,54 @@
+package mulint
+
+import (
+	"go/token"
+	"go/types"
+	"go/version"
+)
+
+// Go versions changing the semantics of the code the checks model.
+// New features (e.g., Mutex.TryLock since Go 1.18 or ranging over functions since Go 1.23)
+// need no gating: the code using them doesn't type-check with the older releases.
+const (
+	// goPerIterationLoopVars makes the variables declared by loops per-iteration.
+	goPerIterationLoopVars = "go1.22"
+)
+
+// goVersionAt returns the Go language version of the file containing the position
+// (e.g., "go1.21"), or an empty string if unknown.
+// File versions account for the module's version and //go:build constraints downgrading it.
+func goVersionAt(info *types.Info, pos token.Pos) string {
+	if info == nil {
+		return ""
+	}
+	for file, v := range info.FileVersions {
+		if file.FileStart <= pos && pos < file.FileEnd {
+			return v
+		}
+	}
+	return ""
+}
+
+// versionAtLeast checks if the Go version v is at least min.
+// Unknown versions are considered recent.
+func versionAtLeast(v, min string) bool {
+	return !version.IsValid(v) || version.Compare(v, min) >= 0
+}
+
+// fileGoVersion returns the Go language version of the file containing the position,
+// falling back to the module's version; empty if unknown.
+func (a *Analyzer) fileGoVersion(pos token.Pos) string {
+	if v := goVersionAt(a.info, pos); v != "" {
+		return v
+	}
+	if a.pass.Module != nil && a.pass.Module.GoVersion != "" {
+		return "go" + a.pass.Module.GoVersion
+	}
+	return ""
+}
+
+// goVersionAtLeast checks if the language version at the position is at least v (e.g., "go1.22").
+// Unknown versions are considered recent.
+func (a *Analyzer) goVersionAtLeast(pos token.Pos, v string) bool {
+	return versionAtLeast(a.fileGoVersion(pos), v)
+}
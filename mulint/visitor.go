@@ -12,6 +12,9 @@ type Visitor struct {
 	calls        map[FQN][]FQN
 	wrappers     *WrapperRegistry
 	conditionals *ConditionalLockRegistry
+	pools        *PoolRegistry
+	conds        *CondRegistry
+	timers       *TimerRegistry
 	pkg          *types.Package
 	info         *types.Info
 	funcs        []*ast.FuncDecl
@@ -23,17 +26,23 @@ func NewVisitor(pkg *types.Package, info *types.Info) *Visitor {
 		calls:        make(map[FQN][]FQN),
 		wrappers:     NewWrapperRegistry(),
 		conditionals: NewConditionalLockRegistry(info),
+		pools:        NewPoolRegistry(info),
+		conds:        NewCondRegistry(info),
+		timers:       NewTimerRegistry(info),
 		pkg:          pkg,
 		info:         info,
 		funcs:        make([]*ast.FuncDecl, 0),
 	}
 }
 
-// Visit collects function declarations for later analysis.
+// Visit collects function declarations and sync.Pool/sync.Cond/time.Timer initializations for later analysis.
 func (v *Visitor) Visit(node ast.Node) ast.Visitor {
 	if fn, ok := node.(*ast.FuncDecl); ok && fn.Body != nil {
 		v.funcs = append(v.funcs, fn)
 	}
+	v.pools.Collect(node)
+	v.conds.Collect(node)
+	v.timers.Collect(node)
 	return v
 }
 
@@ -51,18 +60,20 @@ func (v *Visitor) AnalyzeAll() {
 	v.conditionals.PropagateConditionalLocks(v.funcs, v.funcFQN)
 
 	// Pass 2: Identify wrapper methods from collected scopes
-	v.wrappers.IdentifyWrappers(v.scopes, v.funcs, v.funcFQN)
+	v.wrappers.IdentifyWrappers(v.scopes, v.funcs, v.funcFQN, v.info)
 
-	// Pass 3: Re-analyze bodies without scopes using wrapper awareness
+	// Pass 3: Re-analyze bodies using wrapper awareness
 	for _, fn := range v.funcs {
 		fqn := v.funcFQN(fn)
-		if _, exists := v.scopes[fqn]; exists {
-			continue // Already has direct lock scopes
-		}
-
 		tracker := v.analyzeWithWrappers(fn.Body)
+		if direct, exists := v.scopes[fqn]; exists {
+			// Keep the direct lock scopes, adding the ones of the wrapper calls
+			// (e.g., s.a locked directly and s.b via a wrapper)
+			direct.MergeWrapperScopes(tracker.Scopes())
+			continue
+		}
 		if tracker.HasScopes() {
-			v.scopes[fqn] = tracker.LockTracker
+			v.scopes[fqn] = tracker
 		}
 	}
 }
@@ -72,10 +83,11 @@ func (v *Visitor) analyzeDirectLocks(fqn FQN, body *ast.BlockStmt) {
 	tracker := NewLockTrackerWithInfo(v.info)
 
 	for _, stmt := range body.List {
-		tracker.Track(stmt, true)
+		tracker.Track(stmt)
 	}
 
 	tracker.EndBlock()
+	markFreshScopes(body, tracker.Scopes(), v.info)
 
 	if tracker.HasScopes() {
 		v.scopes[fqn] = tracker
@@ -83,23 +95,43 @@ func (v *Visitor) analyzeDirectLocks(fqn FQN, body *ast.BlockStmt) {
 }
 
 // analyzeWithWrappers analyzes a function body recognizing wrapper method calls.
-func (v *Visitor) analyzeWithWrappers(body *ast.BlockStmt) *WrapperAwareTracker {
-	tracker := NewWrapperAwareTracker(v.wrappers, v.info)
-	tracker.AnalyzeStatements(body.List)
+func (v *Visitor) analyzeWithWrappers(body *ast.BlockStmt) *LockTracker {
+	tracker := NewLockTrackerWithWrappers(v.wrappers, v.info)
+	for _, stmt := range body.List {
+		tracker.Track(stmt)
+	}
 	tracker.EndBlock()
 	return tracker
 }
 
-// recordCalls records function calls made within a function body.
+// recordCalls records function calls made within a function body, including the calls
+// nested in argument expressions, conditions and nested blocks (e.g., process(s.get())).
+// Uses the same walk as lock scopes (see inspectScopeCalls): goroutines and func literals
+// passed elsewhere are not treated as calls made by the function.
 func (v *Visitor) recordCalls(fqn FQN, body *ast.BlockStmt) {
-	for _, stmt := range body.List {
-		if call := CallExpr(stmt); call != nil {
-			if pkg, name, ok := GetCallInfo(call, v.info); ok {
-				calledFQN := FromCallInfo(pkg, name)
-				v.addCall(fqn, calledFQN)
-			}
+	seen := make(map[FQN]bool)
+	record := func(call *ast.CallExpr) {
+		pkg, name, ok := GetCallInfo(call, v.info)
+		if !ok {
+			return
+		}
+		calledFQN := FromCallInfo(pkg, name)
+		if !seen[calledFQN] {
+			seen[calledFQN] = true
+			v.addCall(fqn, calledFQN)
 		}
 	}
+
+	inspectScopeCalls(body, v.info, record)
+	// Range-over-func loops call the iterator
+	ast.Inspect(body, func(n ast.Node) bool {
+		if loop, ok := n.(*ast.RangeStmt); ok {
+			if call := iteratorCall(loop.X, v.info); call != nil {
+				record(call)
+			}
+		}
+		return true
+	})
 }
 
 func (v *Visitor) addCall(from, to FQN) {
@@ -117,10 +149,18 @@ func (v *Visitor) funcFQN(fn *ast.FuncDecl) FQN {
 }
 
 // extractTypeName extracts the type name from a receiver type expression.
+// Type parameters of generic receivers are dropped (Set[T] -> Set), as in getTypeName.
+// The pointerness is dropped, too: a method can't be declared on both T and *T.
 func extractTypeName(e ast.Expr) string {
 	switch t := e.(type) {
 	case *ast.StarExpr:
 		return extractTypeName(t.X)
+	case *ast.ParenExpr:
+		return extractTypeName(t.X)
+	case *ast.IndexExpr:
+		return extractTypeName(t.X)
+	case *ast.IndexListExpr:
+		return extractTypeName(t.X)
 	case *ast.SelectorExpr:
 		return t.Sel.Name
 	case *ast.Ident:
@@ -153,3 +193,18 @@ func (v *Visitor) Wrappers() *WrapperRegistry {
 func (v *Visitor) Conditionals() *ConditionalLockRegistry {
 	return v.conditionals
 }
+
+// Pools returns the sync.Pool registry.
+func (v *Visitor) Pools() *PoolRegistry {
+	return v.pools
+}
+
+// Conds returns the sync.Cond registry.
+func (v *Visitor) Conds() *CondRegistry {
+	return v.conds
+}
+
+// Timers returns the time.AfterFunc timer registry.
+func (v *Visitor) Timers() *TimerRegistry {
+	return v.timers
+}
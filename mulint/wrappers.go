@@ -4,8 +4,12 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"slices"
 )
 
+// errorType is the predeclared error interface.
+var errorType = types.Universe.Lookup("error").Type()
+
 // WrapperKind indicates whether a wrapper method locks or unlocks.
 type WrapperKind int
 
@@ -20,6 +24,8 @@ type WrapperMethod struct {
 	Kind       WrapperKind // Whether this wrapper locks or unlocks
 	FQN        FQN         // The fully qualified name of the wrapper method
 	LockPos    token.Pos   // Position of the actual Lock() call inside the wrapper
+	OnNilError bool        // Whether the lock is held only when the wrapper returns a nil error
+	Read       bool        // Whether the wrapper acquires or releases the read lock (RLock, RUnlock)
 }
 
 // WrapperRegistry tracks methods that are lock/unlock wrappers.
@@ -33,13 +39,15 @@ func NewWrapperRegistry() *WrapperRegistry {
 	}
 }
 
-// Register adds a wrapper method to the registry.
-func (r *WrapperRegistry) Register(fqn FQN, mutexField string, kind WrapperKind, lockPos token.Pos) {
+// Register adds a wrapper method to the registry; read is true for the wrappers
+// acquiring or releasing the read lock.
+func (r *WrapperRegistry) Register(fqn FQN, mutexField string, kind WrapperKind, lockPos token.Pos, read bool) {
 	r.wrappers[fqn] = WrapperMethod{
 		MutexField: mutexField,
 		Kind:       kind,
 		FQN:        fqn,
 		LockPos:    lockPos,
+		Read:       read,
 	}
 }
 
@@ -62,46 +70,132 @@ func (r *WrapperRegistry) IsUnlockWrapper(fqn FQN) bool {
 }
 
 // IdentifyWrappers scans collected scopes and function bodies to identify wrapper methods.
-func (r *WrapperRegistry) IdentifyWrappers(scopes map[FQN]*LockTracker, funcs []*ast.FuncDecl, fqnFunc func(*ast.FuncDecl) FQN) {
+func (r *WrapperRegistry) IdentifyWrappers(scopes map[FQN]*LockTracker, funcs []*ast.FuncDecl, fqnFunc func(*ast.FuncDecl) FQN, info *types.Info) {
 	// A locking wrapper is a function that locks a mutex but does NOT unlock it.
 	// Functions that lock AND unlock (like doSomeWork with defer unlock) are self-contained
 	// and should not be treated as locking wrappers.
 	for fqn, tracker := range scopes {
 		for _, scope := range tracker.Scopes() {
-			// Only consider scopes that were NOT properly unlocked
-			if scope.IsUnlocked() {
+			// Only consider scopes that were NOT properly unlocked.
+			// Locked new values (e.g., returned to the caller locked) don't make wrappers either.
+			if scope.IsUnlocked() || scope.IsFresh() {
 				continue
 			}
 			_, mutexField := SplitSelector(scope.Selector())
 			if mutexField != "" {
-				r.Register(fqn, mutexField, WrapperLock, scope.Pos())
+				r.Register(fqn, mutexField, WrapperLock, scope.Pos(), scope.IsRead())
 				break // One mutex field per function is enough
 			}
 		}
 	}
 
 	// Identify unlock-only methods (methods that unlock without locking)
+	// and locking methods releasing the lock when failing (returning a non-nil error)
 	for _, fn := range funcs {
 		fqn := fqnFunc(fn)
 		if _, isLocking := r.wrappers[fqn]; isLocking {
 			continue // Already registered as locking
 		}
 
-		if mutexField, pos := getUnlockOnlyField(fn.Body); mutexField != "" {
-			r.Register(fqn, mutexField, WrapperUnlock, pos)
+		if mutexField, pos, read := getUnlockOnlyField(fn.Body); mutexField != "" {
+			r.Register(fqn, mutexField, WrapperUnlock, pos, read)
+			continue
+		}
+
+		if tracker, ok := scopes[fqn]; ok {
+			if mutexField, scope := getErrorWrapperField(fn, tracker.Scopes(), info); mutexField != "" {
+				r.Register(fqn, mutexField, WrapperLock, scope.Pos(), scope.IsRead())
+				w := r.wrappers[fqn]
+				w.OnNilError = true
+				r.wrappers[fqn] = w
+			}
+		}
+	}
+}
+
+// getErrorWrapperField checks if a function returns holding a lock exactly when it returns
+// a nil error, e.g.
+//
+//	func (s *Store) acquire() error {
+//	    s.mu.Lock()
+//	    if s.closed {
+//	        s.mu.Unlock()
+//	        return errClosed
+//	    }
+//	    return nil
+//	}
+//
+// and returns the mutex field name and the lock scope if so.
+func getErrorWrapperField(fn *ast.FuncDecl, scopes []*MutexScope, info *types.Info) (string, *MutexScope) {
+	results := fn.Type.Results
+	if results == nil || len(results.List) == 0 || fn.Body == nil {
+		return "", nil
+	}
+	if t := info.TypeOf(results.List[len(results.List)-1].Type); t == nil || !types.Identical(t, errorType) {
+		return "", nil
+	}
+
+	tracker := NewBranchTrackerWithWrappers(nil, info)
+	tracker.CollectLabels(fn.Body)
+	tracker.CollectFlags(fn.Body)
+	tracker.AnalyzeStatements(fn.Body.List)
+	held := make(map[token.Pos][]string)
+	for _, missing := range tracker.Errors() {
+		held[missing.returnPos] = append(held[missing.returnPos], missing.lockInfo.selector)
+	}
+
+	// Returns of nil errors must hold the lock, and the other ones must not
+	var succeeding, failing []*ast.ReturnStmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) == 0 {
+				failing = append(failing, n) // named results aren't followed
+			} else if info.Types[n.Results[len(n.Results)-1]].IsNil() {
+				succeeding = append(succeeding, n)
+			} else {
+				failing = append(failing, n)
+			}
+		}
+		return true
+	})
+	if len(succeeding) == 0 || len(failing) == 0 {
+		return "", nil
+	}
+	selector := ""
+	for _, ret := range succeeding {
+		if len(held[ret.Pos()]) != 1 || (selector != "" && held[ret.Pos()][0] != selector) {
+			return "", nil
+		}
+		selector = held[ret.Pos()][0]
+	}
+	for _, ret := range failing {
+		if slices.Contains(held[ret.Pos()], selector) {
+			return "", nil
+		}
+	}
+
+	_, mutexField := SplitSelector(selector)
+	for _, scope := range scopes {
+		if scope.Selector() == selector && !scope.IsFresh() {
+			return mutexField, scope
 		}
 	}
+	return "", nil
 }
 
 // getUnlockOnlyField checks if a function body only contains an unlock call
-// and returns the mutex field name and position if so.
-func getUnlockOnlyField(body *ast.BlockStmt) (string, token.Pos) {
+// and returns the mutex field name and position if so, along with whether it's a read unlock.
+func getUnlockOnlyField(body *ast.BlockStmt) (string, token.Pos, bool) {
 	if body == nil {
-		return "", token.NoPos
+		return "", token.NoPos, false
 	}
 
 	var unlockField string
 	var unlockPos token.Pos
+	read := false
 	hasLock := false
 
 	for _, stmt := range body.List {
@@ -112,236 +206,78 @@ func getUnlockOnlyField(body *ast.BlockStmt) (string, token.Pos) {
 			selector := StrExpr(e)
 			_, unlockField = SplitSelector(selector)
 			unlockPos = stmt.Pos()
+			read = isReadUnlockCall(stmt)
 		}
 	}
 
 	if hasLock || unlockField == "" {
-		return "", token.NoPos
-	}
-	return unlockField, unlockPos
-}
-
-// WrapperAwareTracker extends LockTracker with wrapper method awareness.
-type WrapperAwareTracker struct {
-	*LockTracker
-	registry *WrapperRegistry
-	typeInfo *types.Info
-}
-
-func NewWrapperAwareTracker(registry *WrapperRegistry, typeInfo *types.Info) *WrapperAwareTracker {
-	return &WrapperAwareTracker{
-		LockTracker: NewLockTracker(),
-		registry:    registry,
-		typeInfo:    typeInfo,
-	}
-}
-
-// TrackWithWrappers processes a statement, recognizing both direct and wrapper lock/unlock calls.
-func (t *WrapperAwareTracker) TrackWithWrappers(stmt ast.Stmt) {
-	// Only add leaf statements to ongoing scopes.
-	// Compound statements (if, for, switch, etc.) should not be added as a whole
-	// because they may contain unlocks that affect subsequent statements within the block.
-	if !isCompoundStmt(stmt) {
-		t.AddToOngoing(stmt)
-	}
-
-	// Check for wrapper calls (creates new scopes)
-	t.trackWrapperCall(stmt)
-
-	// Track direct lock/unlock calls (don't re-add to scopes)
-	t.Track(stmt, false)
-}
-
-// isCompoundStmt returns true if the statement contains nested blocks.
-func isCompoundStmt(stmt ast.Stmt) bool {
-	switch stmt.(type) {
-	case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt,
-		*ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt,
-		*ast.BlockStmt:
-		return true
-	}
-	return false
-}
-
-// trackWrapperCall checks if a statement is a call to a wrapper method.
-func (t *WrapperAwareTracker) trackWrapperCall(stmt ast.Stmt) {
-	call := CallExpr(stmt)
-	if call == nil {
-		return
-	}
-
-	pkg, name, ok := GetCallInfo(call, t.typeInfo)
-	if !ok {
-		return
-	}
-
-	fqn := FromCallInfo(pkg, name)
-	wrapper, isWrapper := t.registry.Get(fqn)
-	if !isWrapper {
-		return
-	}
-
-	// Get the receiver of the call (e.g., "w" from "w.Acquire()")
-	selector := SelectorExpr(call)
-	if selector == nil {
-		return
-	}
-	receiver := RootSelector(selector)
-	if receiver == nil {
-		return
-	}
-
-	// Build the effective mutex selector (e.g., "w" + "." + "m" = "w.m")
-	effectiveSelector := receiver.Name + "." + wrapper.MutexField
-
-	switch wrapper.Kind {
-	case WrapperLock:
-		wrapperInfo := &WrapperInfo{
-			FQN:     wrapper.FQN,
-			LockPos: wrapper.LockPos,
-		}
-		t.StartLockWithWrapper(effectiveSelector, stmt.Pos(), wrapperInfo)
-	case WrapperUnlock:
-		t.EndLock(effectiveSelector)
-	}
-
-	// Handle deferred wrapper calls
-	if deferStmt, ok := stmt.(*ast.DeferStmt); ok {
-		t.trackDeferredWrapperCall(deferStmt)
+		return "", token.NoPos, false
 	}
+	return unlockField, unlockPos, read
 }
 
-// trackDeferredWrapperCall handles deferred wrapper unlock calls.
-func (t *WrapperAwareTracker) trackDeferredWrapperCall(deferStmt *ast.DeferStmt) {
-	call := deferStmt.Call
-	pkg, name, ok := GetCallInfo(call, t.typeInfo)
-	if !ok {
-		return
-	}
-
-	fqn := FromCallInfo(pkg, name)
-	wrapper, isWrapper := t.registry.Get(fqn)
-	if !isWrapper || wrapper.Kind != WrapperUnlock {
-		return
-	}
-
-	selector := SelectorExpr(call)
-	if selector == nil {
-		return
-	}
-	receiver := RootSelector(selector)
-	if receiver == nil {
+// trackWrapperCalls starts or ends the lock scopes of the calls to wrapper methods made
+// by the statement, or by the parts of a compound statement executed before its body
+// (e.g., if ok := w.TryAcquire(); ok { ... }). Deferred unlock wrapper calls release the lock
+// at the end of the block.
+func (t *LockTracker) trackWrapperCalls(stmt ast.Stmt) {
+	if t.wrappers == nil {
 		return
 	}
 
-	effectiveSelector := receiver.Name + "." + wrapper.MutexField
-	t.AddDeferredUnlock(effectiveSelector)
-}
-
-// AnalyzeStatements recursively analyzes statements including nested blocks.
-func (t *WrapperAwareTracker) AnalyzeStatements(stmts []ast.Stmt) {
-	for _, stmt := range stmts {
-		t.TrackWithWrappers(stmt)
-		t.analyzeNestedStatements(stmt)
-	}
-}
-
-// analyzeNestedStatements handles statements that contain nested blocks.
-func (t *WrapperAwareTracker) analyzeNestedStatements(stmt ast.Stmt) {
 	switch s := stmt.(type) {
-	case *ast.IfStmt:
-		if s.Body != nil {
-			t.AnalyzeStatements(s.Body.List)
+	case *ast.DeferStmt:
+		if selector, _, ok := wrapperCallSelector(s.Call, t.wrappers, t.info, WrapperUnlock); ok {
+			t.AddDeferredUnlock(selector)
 		}
-		if s.Else != nil {
-			switch e := s.Else.(type) {
-			case *ast.BlockStmt:
-				t.AnalyzeStatements(e.List)
-			case *ast.IfStmt:
-				t.analyzeNestedStatements(e)
-			}
+	case *ast.AssignStmt:
+		for _, call := range wrapperCandidateCalls(s) {
+			t.trackWrapperCallExpr(call, call.Pos())
 		}
-	case *ast.ForStmt:
-		if s.Body != nil {
-			t.AnalyzeStatements(s.Body.List)
+	case *ast.ExprStmt:
+		if call, ok := s.X.(*ast.CallExpr); ok {
+			t.trackWrapperCallExpr(call, stmt.Pos())
 		}
-	case *ast.RangeStmt:
-		if s.Body != nil {
-			t.AnalyzeStatements(s.Body.List)
+	default:
+		for _, init := range prefixStmts(stmt) {
+			for _, call := range wrapperCandidateCalls(init) {
+				t.trackWrapperCallExpr(call, call.Pos())
+			}
 		}
-	case *ast.SwitchStmt:
-		// Switch cases are mutually exclusive - analyze each independently
-		t.analyzeMutuallyExclusiveCases(s.Body)
-	case *ast.SelectStmt:
-		// Select cases are mutually exclusive - analyze each independently
-		t.analyzeMutuallyExclusiveCommCases(s.Body)
-	case *ast.BlockStmt:
-		t.AnalyzeStatements(s.List)
-	}
-}
-
-// analyzeMutuallyExclusiveCases analyzes switch cases independently.
-// Each case starts with the current ongoing lock state but doesn't affect other cases.
-func (t *WrapperAwareTracker) analyzeMutuallyExclusiveCases(body *ast.BlockStmt) {
-	if body == nil {
-		return
-	}
-
-	// Save current state
-	savedOngoing := t.snapshotOngoing()
-
-	for _, clause := range body.List {
-		cc, ok := clause.(*ast.CaseClause)
-		if !ok {
-			continue
+		for _, expr := range prefixExprs(stmt) {
+			for _, call := range exprCalls(expr) {
+				t.trackWrapperCallExpr(call, call.Pos())
+			}
 		}
-
-		// Restore to state before switch for each case
-		t.restoreOngoing(savedOngoing)
-
-		// Analyze this case
-		t.AnalyzeStatements(cc.Body)
 	}
-
-	// After switch, restore to pre-switch state
-	// (conservative: we don't know which case ran)
-	t.restoreOngoing(savedOngoing)
 }
 
-// analyzeMutuallyExclusiveCommCases analyzes select cases independently.
-func (t *WrapperAwareTracker) analyzeMutuallyExclusiveCommCases(body *ast.BlockStmt) {
-	if body == nil {
-		return
-	}
-
-	savedOngoing := t.snapshotOngoing()
-
-	for _, clause := range body.List {
-		cc, ok := clause.(*ast.CommClause)
-		if !ok {
-			continue
+// wrapperCandidateCalls returns the calls of a simple statement that may be made to wrapper methods:
+// the calls of expression statements and the ones anywhere in the assigned values
+// (ok := w.TryAcquire() or ok := w.TryAcquire() && ready).
+func wrapperCandidateCalls(stmt ast.Stmt) []*ast.CallExpr {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		return exprCalls(s.X)
+	case *ast.AssignStmt:
+		var calls []*ast.CallExpr
+		for _, rhs := range s.Rhs {
+			calls = append(calls, exprCalls(rhs)...)
 		}
-
-		t.restoreOngoing(savedOngoing)
-		t.AnalyzeStatements(cc.Body)
+		return calls
 	}
-
-	t.restoreOngoing(savedOngoing)
-}
-
-// snapshotOngoing creates a copy of the current ongoing locks state.
-func (t *WrapperAwareTracker) snapshotOngoing() map[string]*MutexScope {
-	snapshot := make(map[string]*MutexScope, len(t.LockTracker.onGoing))
-	for k, v := range t.LockTracker.onGoing {
-		snapshot[k] = v
-	}
-	return snapshot
+	return nil
 }
 
-// restoreOngoing restores the ongoing locks state from a snapshot.
-func (t *WrapperAwareTracker) restoreOngoing(snapshot map[string]*MutexScope) {
-	t.LockTracker.onGoing = make(map[string]*MutexScope, len(snapshot))
-	for k, v := range snapshot {
-		t.LockTracker.onGoing[k] = v
+// trackWrapperCallExpr starts or ends the lock scope if the call is made to a wrapper method.
+func (t *LockTracker) trackWrapperCallExpr(call *ast.CallExpr, pos token.Pos) {
+	if selector, wrapper, ok := wrapperCallSelector(call, t.wrappers, t.info, WrapperLock); ok {
+		t.StartLockWithWrapper(selector, pos, &WrapperInfo{
+			FQN:     wrapper.FQN,
+			LockPos: wrapper.LockPos,
+			Read:    wrapper.Read,
+		})
+	} else if selector, _, ok := wrapperCallSelector(call, t.wrappers, t.info, WrapperUnlock); ok {
+		t.EndLock(selector)
 	}
 }
@@ -0,0 +1,64 @@
+package mulinttest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/palkan/mulint/mulint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// Suite is a fixture package exercising a set of checks.
+type Suite struct {
+	// Name is the package path within the data directory (e.g., "reentrant" for testdata/src/reentrant).
+	Name string
+	// Codes are the codes of the checks covered by the suite (e.g., "MU001");
+	// the diagnostics of other checks are ignored. The suites without codes are checked
+	// for all the diagnostics (the negative corpus must not produce any at all).
+	Codes []string
+	// Flags are the analyzer flags to run the suite with (e.g., to enable opt-in checks).
+	Flags map[string]string
+	// Fixes tells to check the suggested fixes against the .golden files, too.
+	Fixes bool
+}
+
+// RunCorpus runs each suite as a subtest on the packages of the analysistest data directory
+// (the suites and their dependencies are in dir/src, see analysistest.TestData).
+func RunCorpus(t *testing.T, dir string, suites []Suite) {
+	for _, suite := range suites {
+		t.Run(suite.Name, func(t *testing.T) {
+			WithFlags(t, suite.Flags)
+
+			run := analysistest.Run
+			if suite.Fixes {
+				run = analysistest.RunWithSuggestedFixes
+			}
+			for _, r := range run(t, dir, Only(suite.Codes...), suite.Name) {
+				if r.Err != nil {
+					t.Error(r.Err)
+				}
+			}
+		})
+	}
+}
+
+// Only returns the analyzer reporting only the diagnostics of the checks with the given codes.
+// Without codes, all the diagnostics are reported.
+func Only(codes ...string) *analysis.Analyzer {
+	if len(codes) == 0 {
+		return mulint.Mulint
+	}
+
+	filtered := *mulint.Mulint
+	filtered.Run = func(pass *analysis.Pass) (any, error) {
+		filteredPass := *pass
+		filteredPass.Report = func(d analysis.Diagnostic) {
+			if slices.Contains(codes, d.Category) {
+				pass.Report(d)
+			}
+		}
+		return mulint.Mulint.Run(&filteredPass)
+	}
+	return &filtered
+}
@@ -0,0 +1,124 @@
+// Package mulinttest provides helpers for testing the analyzer against fixture packages
+// and for maintaining the "// want" comments of the fixtures.
+package mulinttest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/palkan/mulint/mulint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// WithFlags sets the analyzer flags for the duration of the test.
+func WithFlags(t testing.TB, flags map[string]string) {
+	t.Helper()
+	for name, value := range flags {
+		f := mulint.Mulint.Flags.Lookup(name)
+		if f == nil {
+			t.Fatalf("unknown flag: %s", name)
+		}
+		defaultValue := f.DefValue
+		if err := f.Value.Set(value); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			_ = f.Value.Set(defaultValue)
+		})
+	}
+}
+
+// Finding is a diagnostic reported at a line of a file.
+type Finding struct {
+	Line    int
+	Code    string // the check code (the diagnostic category)
+	Message string
+}
+
+// Analyze loads the packages matching the patterns from the directory and runs the analyzer,
+// returning the findings by (absolute) file name, sorted by line.
+// Only the diagnostics of the checks with the given codes are returned (all without codes).
+// Diagnostics are rendered without colors.
+func Analyze(dir string, codes []string, patterns ...string) (map[string][]Finding, error) {
+	if color := mulint.Mulint.Flags.Lookup("color"); color != nil {
+		previous := color.Value.String()
+		_ = color.Value.Set(mulint.ColorNever)
+		defer func() { _ = color.Value.Set(previous) }()
+	}
+
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}
+	if root, ok := gopathRoot(dir); ok {
+		// Fixtures of analysistest data directories import each other by GOPATH paths
+		cfg.Env = append(os.Environ(), "GOPATH="+root, "GO111MODULE=off", "GOPROXY=off")
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("failed to load packages from %s", dir)
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{Only(codes...)}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make(map[string][]Finding)
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("%s: %w", act.Package.PkgPath, act.Err)
+		}
+		for _, d := range act.Diagnostics {
+			position := act.Package.Fset.Position(d.Pos)
+			filename, err := filepath.Abs(position.Filename)
+			if err != nil {
+				filename = position.Filename
+			}
+			findings[filename] = append(findings[filename], Finding{Line: position.Line, Code: d.Category, Message: d.Message})
+		}
+	}
+
+	for _, fileFindings := range findings {
+		sort.SliceStable(fileFindings, func(i, j int) bool {
+			return fileFindings[i].Line < fileFindings[j].Line
+		})
+	}
+	return findings, nil
+}
+
+// gopathRoot returns the analysistest data directory (see analysistest.TestData)
+// containing the directory, if any: the one with the src directory the directory is in.
+func gopathRoot(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	sep := string(filepath.Separator)
+	root, _, ok := strings.Cut(abs+sep, sep+"testdata"+sep+"src"+sep)
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(root, "testdata"), true
+}
+
+// Headline returns the first line of the message without the quoted source.
+func (f Finding) Headline() string {
+	return headline(f.Message)
+}
+
+// headline returns the first line of a diagnostic message without the quoted source
+// ("Mutex lock is acquired on this line: s.helper()" -> "Mutex lock is acquired on this line").
+func headline(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	if before, _, found := strings.Cut(line, ": "); found {
+		line = before
+	}
+	return strings.TrimSpace(line)
+}
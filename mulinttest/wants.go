@@ -0,0 +1,210 @@
+package mulinttest
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const wantMarker = "// want"
+
+// wantComment is a "// want" expectation found in a comment.
+type wantComment struct {
+	start, end int      // offsets of the expectation within the source (up to the end of the comment)
+	literals   []string // the pattern literals as written
+	patterns   []*regexp.Regexp
+	facts      []string // the fact expectations as written (name:"pattern"), always kept
+}
+
+// UpdateWants rewrites the "// want" comments of the source to match the findings:
+// the patterns matching a finding on their line are kept as written, the stale ones
+// are removed and the missing ones are added (see WantPattern).
+func UpdateWants(filename string, src []byte, findings []Finding) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	tokFile := fset.File(file.Pos())
+
+	wants := make(map[int]*wantComment)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			want, err := parseWant(tokFile, c)
+			if err != nil {
+				return nil, err
+			}
+			if want != nil {
+				wants[tokFile.Line(c.Pos())] = want
+			}
+		}
+	}
+
+	byLine := make(map[int][]Finding)
+	for _, f := range findings {
+		byLine[f.Line] = append(byLine[f.Line], f)
+	}
+
+	lines := make([]int, 0, len(wants)+len(byLine))
+	for line := range wants {
+		lines = append(lines, line)
+	}
+	for line := range byLine {
+		if _, ok := wants[line]; !ok {
+			lines = append(lines, line)
+		}
+	}
+	// Edit from the end, so that offsets of the preceding lines stay valid
+	slices.Sort(lines)
+	slices.Reverse(lines)
+
+	out := slices.Clone(src)
+	for _, line := range lines {
+		literals := wantLiterals(wants[line], byLine[line])
+
+		if want, ok := wants[line]; ok {
+			start := want.start
+			if len(literals) == 0 {
+				// Remove the whole comment if it only held the expectation
+				start = trimCommentStart(out, start)
+			}
+			replacement := ""
+			if len(literals) > 0 {
+				replacement = wantMarker + " " + strings.Join(literals, " ")
+			}
+			out = slices.Concat(out[:start], []byte(replacement), out[want.end:])
+			continue
+		}
+
+		if line > tokFile.LineCount() {
+			return nil, fmt.Errorf("%s: finding at line %d is out of range", filename, line)
+		}
+		end := lineEnd(out, tokFile.Offset(tokFile.LineStart(line)))
+		content := bytes.TrimRight(out[:end], " \t")
+		comment := " " + wantMarker + " " + strings.Join(literals, " ")
+		out = slices.Concat(content, []byte(comment), out[end:])
+	}
+
+	return out, nil
+}
+
+// wantLiterals returns the pattern literals for the line: the existing patterns matching
+// a finding first (each finding is matched once), then the patterns for the unmatched findings.
+func wantLiterals(want *wantComment, findings []Finding) []string {
+	matched := make([]bool, len(findings))
+	var literals []string
+
+	if want != nil {
+		literals = append(literals, want.facts...)
+		for i, pattern := range want.patterns {
+			for j, f := range findings {
+				if !matched[j] && pattern.MatchString(f.Message) {
+					matched[j] = true
+					literals = append(literals, want.literals[i])
+					break
+				}
+			}
+		}
+	}
+
+	for j, f := range findings {
+		if !matched[j] {
+			literals = append(literals, quotePattern(WantPattern(f.Message)))
+		}
+	}
+	return literals
+}
+
+// WantPattern returns the expectation pattern for a diagnostic message:
+// its headline without the quoted source line.
+func WantPattern(message string) string {
+	return regexp.QuoteMeta(headline(message))
+}
+
+// quotePattern formats a pattern as a Go string literal, preferring raw strings
+// for patterns with escapes.
+func quotePattern(pattern string) string {
+	if strings.Contains(pattern, `\`) && !strings.Contains(pattern, "`") {
+		return "`" + pattern + "`"
+	}
+	return strconv.Quote(pattern)
+}
+
+// parseWant parses the expectation of a comment ("// want "a" `b`"), if any.
+// As analysistest does, the expectation may follow other text in the comment.
+func parseWant(tokFile *token.File, c *ast.Comment) (*wantComment, error) {
+	idx := strings.Index(c.Text, wantMarker)
+	if idx < 0 || !strings.HasPrefix(c.Text, "//") {
+		return nil, nil
+	}
+	rest := c.Text[idx+len(wantMarker):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil, nil
+	}
+
+	start := tokFile.Offset(c.Pos()) + idx
+	want := &wantComment{start: start, end: tokFile.Offset(c.End())}
+
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", -1, len(rest)), []byte(rest), nil, 0)
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF || tok == token.SEMICOLON {
+			break
+		}
+		if tok == token.IDENT {
+			// A fact expectation: name:"pattern"
+			name := lit
+			if _, tok, lit = s.Scan(); tok == token.COLON {
+				_, tok, lit = s.Scan()
+			}
+			if tok != token.STRING {
+				return nil, fmt.Errorf("%s:%d: unexpected %q in want comment", tokFile.Name(), tokFile.Line(c.Pos()), lit)
+			}
+			want.facts = append(want.facts, name+":"+lit)
+			continue
+		}
+		if tok != token.STRING {
+			return nil, fmt.Errorf("%s:%d: unexpected %q in want comment", tokFile.Name(), tokFile.Line(c.Pos()), lit)
+		}
+		value, err := strconv.Unquote(lit)
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", tokFile.Name(), tokFile.Line(c.Pos()), err)
+		}
+		want.literals = append(want.literals, lit)
+		want.patterns = append(want.patterns, pattern)
+	}
+	return want, nil
+}
+
+// trimCommentStart moves the start of an expectation back over the comment opening
+// and whitespace when nothing else precedes it in the comment.
+func trimCommentStart(src []byte, start int) int {
+	lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+	before := bytes.TrimRight(src[lineStart:start], " \t")
+	if len(before) == 0 {
+		// The comment occupies the whole line
+		return lineStart
+	}
+	return lineStart + len(before)
+}
+
+// lineEnd returns the offset of the end of the line starting at offset.
+func lineEnd(src []byte, offset int) int {
+	if i := bytes.IndexByte(src[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(src)
+}
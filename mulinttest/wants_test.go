@@ -0,0 +1,95 @@
+package mulinttest
+
+import (
+	"testing"
+)
+
+func TestUpdateWants(t *testing.T) {
+	src := `package fixture
+
+func a() {
+	lock() // want "Mutex lock is acquired on this line"
+	stale() // want "Mutex lock must be released before this line"
+	missing()
+	both() // note // want ` + "`acquired`" + `
+	keep() // just a comment
+}
+`
+
+	findings := []Finding{
+		{Line: 4, Message: "Mutex lock is acquired on this line: lock()\n\tfixture.go:3: But the same lock was acquired here"},
+		{Line: 6, Message: "Mutex lock is acquired on this line: missing()"},
+		{Line: 7, Message: "Mutex lock is acquired on this line: both()"},
+		{Line: 7, Message: "Deferred unlock runs on an unlocked mutex (m.Unlock())"},
+	}
+
+	updated, err := UpdateWants("fixture.go", []byte(src), findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `package fixture
+
+func a() {
+	lock() // want "Mutex lock is acquired on this line"
+	stale()
+	missing() // want "Mutex lock is acquired on this line"
+	both() // note // want ` + "`acquired` `Deferred unlock runs on an unlocked mutex \\(m\\.Unlock\\(\\)\\)`" + `
+	keep() // just a comment
+}
+`
+
+	if string(updated) != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", updated, expected)
+	}
+}
+
+func TestUpdateWantsUpToDate(t *testing.T) {
+	src := `package fixture
+
+func a() {
+	lock() // want "acquired"
+}
+`
+
+	updated, err := UpdateWants("fixture.go", []byte(src), []Finding{{Line: 4, Message: "Mutex lock is acquired on this line"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(updated) != src {
+		t.Errorf("expected no changes, got:\n%s", updated)
+	}
+}
+
+func TestUpdateWantsKeepsFacts(t *testing.T) {
+	src := `package fixture
+
+func a() { // want a:"mayBlock" "stale"
+	lock()
+}
+`
+
+	updated, err := UpdateWants("fixture.go", []byte(src), []Finding{{Line: 4, Message: "Mutex lock is acquired on this line"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `package fixture
+
+func a() { // want a:"mayBlock"
+	lock() // want "Mutex lock is acquired on this line"
+}
+`
+
+	if string(updated) != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", updated, expected)
+	}
+}
+
+func TestWantPattern(t *testing.T) {
+	pattern := WantPattern("Mutex lock must be released before this line: return err\n\tfixture.go:3: Lock was acquired here")
+	if pattern != "Mutex lock must be released before this line" {
+		t.Errorf("unexpected pattern: %s", pattern)
+	}
+}
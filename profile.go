@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+)
+
+// registerProfileFlags registers the profiling flags for the CLI modes handled outside of singlechecker,
+// which provides the same flags itself.
+func registerProfileFlags() (cpuProfile, memProfile *string) {
+	cpuProfile = flag.String("cpuprofile", "", "write CPU profile to this file")
+	memProfile = flag.String("memprofile", "", "write memory profile to this file")
+	return
+}
+
+// startProfiling starts the CPU profiling if requested and returns the function
+// stopping it and writing the memory profile. The profiles are also written when
+// the process is interrupted (e.g., to stop the watch mode); stopping twice is a no-op.
+func startProfiling(cpuProfile, memProfile string) (func(), error) {
+	var cpuFile *os.File
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		cpuFile = f
+	}
+
+	// Both the interrupt handler and the caller may stop the profiling
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			if cpuFile != nil {
+				pprof.StopCPUProfile()
+				cpuFile.Close()
+			}
+			if memProfile != "" {
+				if err := writeMemProfile(memProfile); err != nil {
+					fmt.Fprintf(os.Stderr, "mulint: %v\n", err)
+				}
+			}
+		})
+	}
+
+	if cpuProfile != "" || memProfile != "" {
+		interrupted := make(chan os.Signal, 1)
+		signal.Notify(interrupted, os.Interrupt)
+		go func() {
+			<-interrupted
+			stop()
+			os.Exit(130)
+		}()
+	}
+
+	return stop, nil
+}
+
+func writeMemProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	runtime.GC()
+	return pprof.WriteHeapProfile(f)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStopProfilingTwice(t *testing.T) {
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.prof")
+	memProfile := filepath.Join(dir, "mem.prof")
+
+	stop, err := startProfiling(cpuProfile, memProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The interrupt handler may stop the profiling along with the caller
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stop()
+		}()
+	}
+	wg.Wait()
+	stop()
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("expected profile %s to be written: %v", path, err)
+		}
+	}
+}
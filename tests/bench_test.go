@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/palkan/mulint/mulint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Thousands of functions with short call chains
+func BenchmarkAnalyzeWide(b *testing.B) {
+	benchmarkAnalyze(b, 1000, 5)
+}
+
+// Deep call chains resolved transitively
+func BenchmarkAnalyzeDeep(b *testing.B) {
+	benchmarkAnalyze(b, 10, 300)
+}
+
+func benchmarkAnalyze(b *testing.B, types, depth int) {
+	pkgs := loadSynthetic(b, types, depth)
+	analyzers := []*analysis.Analyzer{mulint.Mulint}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		graph, err := checker.Analyze(analyzers, pkgs, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, act := range graph.Roots {
+			if act.Err != nil {
+				b.Fatal(act.Err)
+			}
+		}
+	}
+}
+
+// loadSynthetic writes the synthetic package into a temporary module and loads it.
+func loadSynthetic(b *testing.B, types, depth int) []*packages.Package {
+	dir := b.TempDir()
+	files := map[string]string{
+		"go.mod":       "module synthetic\n\ngo 1.24\n",
+		"synthetic.go": synthesize(types, depth),
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}, ".")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		b.Fatal("failed to load the synthetic package")
+	}
+	return pkgs
+}
+
+// synthesize generates a package with the given number of types, each with a chain
+// of depth methods calling the next one. The first method of a chain holds the lock
+// the last one acquires again, so every chain is resolved transitively to a finding.
+// Methods branch, loop and return early to exercise the control flow analysis,
+// and lock wrappers are used to exercise the wrappers inference.
+func synthesize(types, depth int) string {
+	var sb strings.Builder
+	sb.WriteString("package synthetic\n\nimport \"sync\"\n")
+
+	for t := range types {
+		fmt.Fprintf(&sb, `
+type T%[1]d struct {
+	mu sync.RWMutex
+	v  int
+}
+
+func (s *T%[1]d) lock()   { s.mu.Lock() }
+func (s *T%[1]d) unlock() { s.mu.Unlock() }
+
+func (s *T%[1]d) Wrapped(n int) {
+	s.lock()
+	if n > 0 {
+		s.v = n
+	}
+	s.unlock()
+}
+
+func (s *T%[1]d) M0(n int) int {
+	s.mu.Lock()
+	if n < 0 {
+		s.mu.Unlock()
+		return 0
+	}
+	r := s.M1(n)
+	s.mu.Unlock()
+	return r
+}
+`, t)
+
+		for d := 1; d < depth-1; d++ {
+			fmt.Fprintf(&sb, `
+func (s *T%[1]d) M%[2]d(n int) int {
+	if n%%2 == 0 {
+		return s.M%[3]d(n - 1)
+	}
+	switch n {
+	case 1:
+		return s.v
+	}
+	for i := 0; i < n; i++ {
+		s.v += i
+	}
+	return s.M%[3]d(n / 2)
+}
+`, t, d, d+1)
+		}
+
+		fmt.Fprintf(&sb, `
+func (s *T%[1]d) M%[2]d(n int) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.v + n
+}
+`, t, depth-1)
+	}
+
+	return sb.String()
+}
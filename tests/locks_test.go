@@ -1,50 +1,209 @@
 package tests
 
 import (
-	"fmt"
-	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/palkan/mulint/mulint"
+	"github.com/palkan/mulint/mulinttest"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
-func Test_MixedLocks(t *testing.T) {
+// Test_Corpus runs the per-check suites, each checked only for the diagnostics of its checks,
+// and the suites of the opt-in checks and output options, which are run with their flags
+// and checked for all the diagnostics. The negative corpus must not produce any diagnostics.
+func Test_Corpus(t *testing.T) {
+	suites := []mulinttest.Suite{
+		{Name: "reentrant", Codes: []string{mulint.CodeReentrantLock}},
+		{Name: "callbacks", Codes: []string{mulint.CodeReentrantLock}},
+		{Name: "callbacktakers", Codes: []string{mulint.CodeReentrantLock}},
+		{Name: "controlflow", Codes: []string{mulint.CodeReentrantLock, mulint.CodeMissingUnlock}},
+		{Name: "wrappers", Codes: []string{mulint.CodeReentrantLock, mulint.CodeMissingUnlock}},
+		{Name: "condwait", Codes: []string{mulint.CodeCondWait}},
+		{Name: "doublechecked", Codes: []string{mulint.CodeDoubleCheckedLock}},
+		{Name: "timers", Codes: []string{mulint.CodeTimerCallbackWait}},
+		{Name: "cycles", Codes: []string{mulint.CodeReentrantLock, mulint.CodeLockedCycle}},
+		{Name: "selects", Codes: []string{mulint.CodeSelectDeadlock}},
+		{Name: "earlyunlock", Codes: []string{mulint.CodeEarlyUnlock}},
+		{Name: "conditionalunlock", Codes: []string{mulint.CodeConditionalUnlock}},
+		{Name: "doubledefer", Codes: []string{mulint.CodeDoubleDeferUnlock}},
+		{Name: "suspectwrappers", Codes: []string{mulint.CodeSuspectWrapper}},
+		{Name: "mutexassign", Codes: []string{mulint.CodeMutexAssign}},
+		{Name: "mutexcopy", Codes: []string{mulint.CodeHeldMutexCopy, mulint.CodeCopiedElementLock}},
+		{Name: "loopvars", Codes: []string{mulint.CodeLoopVarLock}},
+		{Name: "negative"},
 
-	filemap := map[string]string{
-		"tests/mixed_locks.go":         LoadFile("mixed_locks.go"),
-		"tests/simple_rlock.go":        LoadFile("simple_rlock.go"),
-		"tests/transitive_lock.go":     LoadFile("transitive_lock.go"),
-		"tests/simple_wrapped_lock.go": LoadFile("simple_wrapped_lock.go"),
-		"tests/branching_locks.go":     LoadFile("branching_locks.go"),
-		"tests/async_callbacks.go":     LoadFile("async_callbacks.go"),
+		{Name: "handlers", Flags: map[string]string{"http-handlers": "true"}},
+		{Name: "entrypoints", Flags: map[string]string{"entrypoints": "service:Run"}},
+		{Name: "exportedsurface", Flags: map[string]string{"exported-only": "true"}},
+		{Name: "runfunc", Flags: map[string]string{"run-func": `(Queue|Cache)\.`}},
+		{Name: "summary", Flags: map[string]string{"summary": "true"}},
+		{Name: "criticalsections", Flags: map[string]string{"critical-sections": "true"}},
+		{Name: "lockmetrics", Flags: map[string]string{"lock-metrics": "true", "lock-metrics-fanin": "3"}},
+		{Name: "exported", Flags: map[string]string{"exported-calls": "true"}},
+		{Name: "lockedconvention", Flags: map[string]string{"locked-convention": "true"}},
+		{Name: "starvation", Flags: map[string]string{"writer-starvation": "true"}},
+		{Name: "unlockuse", Flags: map[string]string{"use-after-unlock": "true"}},
+		{Name: "guardedreturns", Flags: map[string]string{"guarded-returns": "true"}},
+		{Name: "closures", Flags: map[string]string{"escaping-closures": "true"}},
+		{Name: "redundant", Flags: map[string]string{"redundant-mutexes": "true"}},
+		{Name: "localmutexes", Flags: map[string]string{"local-mutexes": "true"}},
+		{Name: "hotmethods", Flags: map[string]string{"hot-method-locks": "true"}},
+		{Name: "assumesync", Flags: map[string]string{"assume-sync-callbacks": "true"}},
+		// The known asynchronous callback-takers are still skipped
+		{Name: "callbacktakers", Flags: map[string]string{"assume-sync-callbacks": "true"}},
+		{Name: "maybesync", Flags: map[string]string{"maybe-sync-callbacks": "maybesync.emitter:On,maybesync.future.Then"}},
+		{Name: "strict", Flags: map[string]string{"strict": "true"}},
+		{Name: "strictprofile", Flags: map[string]string{"strict-packages": "strictprofile"}},
+		{Name: "mayblock/...", Flags: map[string]string{"strict-packages": "mayblock"}},
+		{Name: "fqns/...", Flags: map[string]string{"strict-packages": "fqns/dotted"}},
+		{Name: "deferunlock", Flags: map[string]string{"require-defer-unlock": "true"}, Fixes: true},
+		{Name: "lockedvariants", Fixes: true},
+		{Name: "slowpaths", Fixes: true},
+		{Name: "custommutex", Flags: map[string]string{"mutex-types": "custommutex/xsync.Mutex,custommutex/xsync.TimedMutex"}},
+		{Name: "grouping", Flags: map[string]string{"group": "true"}},
+		{Name: "shortformat", Flags: map[string]string{"format": "short"}},
+		{Name: "color", Flags: map[string]string{"color": "always"}},
+		{Name: "externs"},
+		{Name: "thirdparty", Flags: map[string]string{"extern-summaries": "testdata/summaries.json"}},
+		{Name: "callgraph", Flags: map[string]string{"callgraph": "vta"}},
+		{Name: "callgraph/cha", Flags: map[string]string{"callgraph": "cha"}},
 	}
-	dir, cleanup, err := analysistest.WriteFiles(filemap)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer cleanup()
 
-	result := analysistest.Run(t, dir, mulint.Mulint, "tests")
+	mulinttest.RunCorpus(t, analysistest.TestData(), suites)
+}
 
-	failure := false
-	for _, r := range result {
+// The values of -run-func and -extern-summaries are loaded (and rejected) when the flags are set
+func Test_InvalidFlags(t *testing.T) {
+	for name, value := range map[string]string{"run-func": "(", "extern-summaries": "testdata/missing.json"} {
+		if err := mulint.Mulint.Flags.Set(name, value); err == nil {
+			t.Errorf("expected -%s=%s to be rejected", name, value)
+		}
+	}
+}
+
+// Loop variables are shared by all iterations depending on the Go version of the files:
+// the one of the module (go.mod) unless changed with a //go:build constraint
+func Test_GoVersions(t *testing.T) {
+	dir := filepath.Join(analysistest.TestData(), "modules", "go121")
+	for _, r := range analysistest.Run(t, dir, mulinttest.Only(mulint.CodeLoopVarLock), "./...") {
 		if r.Err != nil {
-			fmt.Println(r.Err)
-			failure = true
+			t.Error(r.Err)
+		}
+	}
+}
+
+// ignoreWants is an analysistest.Testing ignoring the expectations of the fixtures.
+type ignoreWants struct{}
+
+func (ignoreWants) Errorf(string, ...any) {}
+
+// The auto mode only colors the plain-text output printed by the standalone CLI to a terminal,
+// while JSON output is never colored
+func Test_ColorOutput(t *testing.T) {
+	cases := []struct {
+		name           string
+		color          string
+		json, terminal bool
+		colored        bool
+	}{
+		{name: "terminal", color: mulint.ColorAuto, terminal: true, colored: true},
+		{name: "pipe or other drivers", color: mulint.ColorAuto},
+		{name: "json", color: mulint.ColorAuto, json: true, terminal: true},
+		{name: "json always", color: mulint.ColorAlways, json: true, terminal: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mulinttest.WithFlags(t, map[string]string{"color": tc.color})
+			mulint.SetOutput(tc.json, tc.terminal)
+			t.Cleanup(func() { mulint.SetOutput(false, false) })
+
+			results := analysistest.Run(ignoreWants{}, analysistest.TestData(), mulint.Mulint, "color")
+			if len(results) == 0 || len(results[0].Diagnostics) == 0 {
+				t.Fatal("expected diagnostics")
+			}
+			for _, d := range results[0].Diagnostics {
+				if colored := strings.Contains(d.Message, "\x1b["); colored != tc.colored {
+					t.Errorf("expected colored=%v, got %q", tc.colored, d.Message)
+				}
+			}
+		})
+	}
+}
+
+func Test_WrapperLockRelated(t *testing.T) {
+	var lines []int
+	// The wrappers suite is only checked for the reentrant locks and missing unlocks (see Test_Corpus)
+	analyzer := mulinttest.Only(mulint.CodeReentrantLock, mulint.CodeMissingUnlock)
+	for _, r := range analysistest.Run(t, analysistest.TestData(), analyzer, "wrappers") {
+		for _, d := range r.Diagnostics {
+			for _, related := range d.Related {
+				if strings.HasPrefix(related.Message, "lock is acquired in wrapper ticket:hold") {
+					lines = append(lines, r.Pass.Fset.Position(related.Pos).Line)
+				}
+			}
 		}
 	}
 
-	if failure {
-		t.Fail()
+	if expected := []int{11, 11}; !slices.Equal(lines, expected) {
+		t.Errorf("expected wrapper lock related lines %v, got %v", expected, lines)
 	}
 }
 
-func LoadFile(path string) string {
-	contents, err := os.ReadFile(path)
-	if err != nil {
-		panic("Error loading file: " + err.Error())
+func Test_FunctionFQN(t *testing.T) {
+	var functions []string
+	for _, r := range analysistest.Run(t, analysistest.TestData(), mulint.Mulint, "structured") {
+		for _, d := range r.Diagnostics {
+			for _, related := range d.Related {
+				if fqn, ok := strings.CutPrefix(related.Message, "in function "); ok {
+					functions = append(functions, fqn)
+				}
+			}
+		}
 	}
 
-	return string(contents)
+	slices.Sort(functions)
+	expected := []string{"structured.Drain", "structured.Queue:Add"}
+	if !slices.Equal(functions, expected) {
+		t.Errorf("expected enclosing functions %v, got %v", expected, functions)
+	}
+}
+
+func Test_LockGraph(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), mulint.Mulint, "lockgraph")
+	graph, ok := results[0].Result.(*mulint.LockGraph)
+	if !ok {
+		t.Fatalf("expected the analyzer result to be a lock graph, got %T", results[0].Result)
+	}
+
+	if locks, expected := graph.LocksOf("lockgraph.Cache:Warm"), []string{"Cache.mu", "Cache.statsMu"}; !slices.Equal(locks, expected) {
+		t.Errorf("expected Warm to acquire %v, got %v", expected, locks)
+	}
+	if locks := graph.Acquires("lockgraph.Cache:Warm"); len(locks) != 0 {
+		t.Errorf("expected Warm to acquire no locks directly, got %v", locks)
+	}
+
+	expectedHolders := []mulint.FQN{"lockgraph.Cache:Get", "lockgraph.Cache:Put", "lockgraph.Cache:lock"}
+	if holders := graph.HoldersOf("Cache.mu"); !slices.Equal(holders, expectedHolders) {
+		t.Errorf("expected Cache.mu holders %v, got %v", expectedHolders, holders)
+	}
+	if mutexes, expected := graph.Mutexes(), []string{"Cache.mu", "Cache.statsMu"}; !slices.Equal(mutexes, expected) {
+		t.Errorf("expected mutexes %v, got %v", expected, mutexes)
+	}
+
+	paths := graph.PathsBetween("lockgraph.Cache:Warm", "lockgraph.Cache:record")
+	expectedPath := []mulint.FQN{"lockgraph.Cache:Warm", "lockgraph.Cache:Get", "lockgraph.Cache:record"}
+	if len(paths) != 1 || !slices.Equal(paths[0], expectedPath) {
+		t.Errorf("expected paths from Warm to record [%v], got %v", expectedPath, paths)
+	}
+	if paths := graph.PathsBetween("lockgraph.Cache:record", "lockgraph.Cache:Warm"); len(paths) != 0 {
+		t.Errorf("expected no paths from record to Warm, got %v", paths)
+	}
+
+	if wrapper, ok := graph.Wrapper("lockgraph.Cache:lock"); !ok || wrapper.Kind != mulint.WrapperLock || wrapper.MutexField != "mu" {
+		t.Errorf("expected lock to be a lock wrapper of mu, got %+v", wrapper)
+	}
 }
@@ -0,0 +1,19 @@
+package go121
+
+import "sync"
+
+type worker struct {
+	mu   sync.Mutex
+	jobs int
+}
+
+// Loop variables are shared by all iterations in the modules declaring Go 1.21
+func startAll(workers []*worker) {
+	for _, w := range workers {
+		go func() {
+			w.mu.Lock() // want "Goroutine locks the mutex of loop variable w shared by all iterations"
+			defer w.mu.Unlock()
+			w.jobs++
+		}()
+	}
+}
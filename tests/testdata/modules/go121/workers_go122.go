@@ -0,0 +1,14 @@
+//go:build go1.22
+
+package go121
+
+// The files upgrading the version to Go 1.22 get per-iteration loop variables
+func restartAll(workers []*worker) {
+	for _, w := range workers {
+		go func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			w.jobs = 0
+		}()
+	}
+}
@@ -0,0 +1,80 @@
+package assumesync
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type index struct {
+	mu      sync.Mutex
+	keys    []string
+	pending []func()
+}
+
+func (x *index) size() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.keys)
+}
+
+func each(keys []string, fn func(string)) {
+	for _, key := range keys {
+		fn(key)
+	}
+}
+
+func (x *index) Sort() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	sort.Slice(x.keys, func(i, j int) bool {
+		return x.size() > 0 && x.keys[i] < x.keys[j] // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (x *index) Visit() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	each(x.keys, func(string) {
+		x.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (x *index) visitAll() {
+	each(x.keys, func(string) {
+		x.size()
+	})
+}
+
+func (x *index) VisitAll() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.visitAll() // want "Mutex lock is acquired on this line"
+}
+
+// Known asynchronous callback-takers and goroutines run the literals later
+func (x *index) Schedule(g *errgroup.Group) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	time.AfterFunc(time.Second, func() {
+		x.size()
+	})
+	g.Go(func() error {
+		x.size()
+		return nil
+	})
+	go each(x.keys, func(string) {
+		x.size()
+	})
+}
+
+// Stored literals don't run under lock
+func (x *index) Defer() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.pending = append(x.pending, func() {
+		x.size()
+	})
+}
@@ -0,0 +1,66 @@
+package callbacks
+
+import (
+	"bytes"
+	"sync"
+)
+
+type pooled struct {
+	mu      sync.Mutex
+	bufs    sync.Pool
+	scratch sync.Pool
+	created int
+}
+
+func newPooled() *pooled {
+	p := &pooled{
+		scratch: sync.Pool{New: func() any { return new(bytes.Buffer) }},
+	}
+	p.bufs.New = p.newBuffer
+	return p
+}
+
+func (p *pooled) newBuffer() any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.created++
+	return new(bytes.Buffer)
+}
+
+func (p *pooled) Render() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	buf := p.bufs.Get() // want "Mutex lock is acquired on this line"
+	p.bufs.Put(buf)
+}
+
+func (p *pooled) RenderScratch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	buf := p.scratch.Get() // Should NOT be flagged - New does not lock
+	p.scratch.Put(buf)
+}
+
+var counterMu sync.Mutex
+var counted int
+
+var counterPool = sync.Pool{
+	New: func() any {
+		counterMu.Lock()
+		defer counterMu.Unlock()
+
+		counted++
+		return new(bytes.Buffer)
+	},
+}
+
+func countedRender() {
+	counterMu.Lock()
+	defer counterMu.Unlock()
+
+	buf := counterPool.Get() // want "Mutex lock is acquired on this line"
+	counterPool.Put(buf)
+}
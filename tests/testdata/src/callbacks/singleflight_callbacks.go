@@ -0,0 +1,61 @@
+package callbacks
+
+import (
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type flight struct {
+	mu    sync.Mutex
+	sf    singleflight.Group
+	cache map[string]string
+}
+
+func (f *flight) Fetch(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sf.Do(key, func() (interface{}, error) {
+		f.mu.Lock() // want "Mutex lock is acquired on this line"
+		defer f.mu.Unlock()
+		return f.cache[key], nil
+	})
+}
+
+func (f *flight) FetchTransitive(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sf.Do(key, func() (interface{}, error) {
+		return f.lookup(key), nil // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (f *flight) FetchMethodValue(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sf.Do(key, f.load) // want "Mutex lock is acquired on this line"
+}
+
+func (f *flight) FetchChan(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sf.DoChan(key, func() (interface{}, error) {
+		return f.lookup(key), nil // Should NOT be flagged - DoChan runs fn in a goroutine
+	})
+}
+
+func (f *flight) lookup(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cache[key]
+}
+
+func (f *flight) load() (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.cache), nil
+}
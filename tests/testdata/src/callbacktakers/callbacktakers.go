@@ -0,0 +1,259 @@
+package callbacktakers
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+type tree struct {
+	mu    sync.Mutex
+	once  sync.Once
+	wg    sync.WaitGroup
+	sf    singleflight.Group
+	paths []string
+}
+
+func (t *tree) size() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.paths)
+}
+
+// Synchronous callback-takers run the literals under the caller's lock
+
+func (t *tree) SortSlice() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sort.Slice(t.paths, func(i, j int) bool {
+		return t.size() > i+j // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SortSliceStable() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sort.SliceStable(t.paths, func(i, j int) bool {
+		return t.size() > i+j // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SortSearch() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return sort.Search(len(t.paths), func(i int) bool {
+		return t.size() > i // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SortFind() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i, _ := sort.Find(len(t.paths), func(i int) int {
+		return t.size() - i // want "Mutex lock is acquired on this line"
+	})
+	return i
+}
+
+func (t *tree) SlicesSortFunc() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slices.SortFunc(t.paths, func(a, b string) int {
+		return t.size() + strings.Compare(a, b) // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SlicesSortStableFunc() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slices.SortStableFunc(t.paths, func(a, b string) int {
+		return t.size() + strings.Compare(a, b) // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SlicesBinarySearchFunc(path string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i, _ := slices.BinarySearchFunc(t.paths, path, func(a, b string) int {
+		return t.size() + strings.Compare(a, b) // want "Mutex lock is acquired on this line"
+	})
+	return i
+}
+
+func (t *tree) SlicesIndexFunc() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.IndexFunc(t.paths, func(p string) bool {
+		return len(p) > t.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SlicesContainsFunc() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.ContainsFunc(t.paths, func(p string) bool {
+		return len(p) > t.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SlicesDeleteFunc() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paths = slices.DeleteFunc(t.paths, func(p string) bool {
+		return len(p) > t.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) StringsMap(s string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.Map(func(r rune) rune {
+		return r + rune(t.size()) // want "Mutex lock is acquired on this line"
+	}, s)
+}
+
+func (t *tree) StringsFieldsFunc(s string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return int(r) == t.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) StringsIndexFunc(s string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.IndexFunc(s, func(r rune) bool {
+		return int(r) == t.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) FilepathWalk(root string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return filepath.Walk(root, func(path string, _ os.FileInfo, err error) error {
+		t.size() // want "Mutex lock is acquired on this line"
+		return err
+	})
+}
+
+func (t *tree) FilepathWalkDir(root string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return filepath.WalkDir(root, func(path string, _ fs.DirEntry, err error) error {
+		t.size() // want "Mutex lock is acquired on this line"
+		return err
+	})
+}
+
+func (t *tree) FSWalkDir(fsys fs.FS) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return fs.WalkDir(fsys, ".", func(path string, _ fs.DirEntry, err error) error {
+		t.size() // want "Mutex lock is acquired on this line"
+		return err
+	})
+}
+
+func (t *tree) OnceDo() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.once.Do(func() {
+		t.size() // want "Mutex lock is acquired on this line"
+	})
+}
+
+func (t *tree) SingleflightDo() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sf.Do("size", func() (interface{}, error) {
+		return t.size(), nil // want "Mutex lock is acquired on this line"
+	})
+}
+
+// Asynchronous callback-takers run the literals later
+
+func (t *tree) SingleflightDoChan() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sf.DoChan("size", func() (interface{}, error) {
+		return t.size(), nil
+	})
+}
+
+func (t *tree) TimeAfterFunc() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	time.AfterFunc(time.Second, func() {
+		t.size()
+	})
+}
+
+func (t *tree) ContextAfterFunc(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		t.size()
+	})
+}
+
+func (t *tree) WaitGroupGo() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.wg.Go(func() {
+		t.size()
+	})
+}
+
+func (t *tree) ErrgroupGo(g *errgroup.Group) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	g.Go(func() error {
+		t.size()
+		return nil
+	})
+}
+
+func (t *tree) ErrgroupTryGo(g *errgroup.Group) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	g.TryGo(func() error {
+		t.size()
+		return nil
+	})
+}
+
+func (t *tree) HTTPHandleFunc() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	http.HandleFunc("/size", func(http.ResponseWriter, *http.Request) {
+		t.size()
+	})
+}
+
+func (t *tree) ServeMuxHandleFunc(mux *http.ServeMux) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	mux.HandleFunc("/size", func(http.ResponseWriter, *http.Request) {
+		t.size()
+	})
+}
+
+func (t *tree) RuntimeSetFinalizer(v *int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	runtime.SetFinalizer(v, func(*int) {
+		t.size()
+	})
+}
@@ -0,0 +1,59 @@
+package callgraph
+
+import "sync"
+
+type notifier interface {
+	Notify()
+}
+
+type Service struct {
+	mu       sync.Mutex
+	self     notifier
+	log      notifier
+	onChange func()
+	version  int
+}
+
+func NewService() *Service {
+	s := &Service{log: &logNotifier{}}
+	s.self = s
+	s.onChange = s.refresh
+	return s
+}
+
+func (s *Service) Update() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version++
+	s.self.Notify() // want `(?s)Mutex lock is acquired on this line.*Lock is acquired in Service:Notify`
+}
+
+func (s *Service) Change() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onChange() // want `(?s)Mutex lock is acquired on this line.*Lock is acquired in Service:refresh`
+}
+
+func (s *Service) Log() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.log.Notify()
+}
+
+func (s *Service) Notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+}
+
+func (s *Service) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+}
+
+type logNotifier struct{}
+
+func (l *logNotifier) Notify() {}
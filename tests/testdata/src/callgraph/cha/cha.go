@@ -0,0 +1,34 @@
+package cha
+
+import "sync"
+
+type notifier interface {
+	Notify()
+}
+
+type Service struct {
+	mu  sync.Mutex
+	log notifier
+}
+
+// CHA considers all the implementations of the interface,
+// even though only logNotifier is ever assigned to s.log.
+func (s *Service) Log() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.log.Notify() // want `(?s)Mutex lock is acquired on this line.*Lock is acquired in Service:Notify`
+}
+
+func (s *Service) Notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+}
+
+type logNotifier struct{}
+
+func (l *logNotifier) Notify() {}
+
+func NewService() *Service {
+	return &Service{log: &logNotifier{}}
+}
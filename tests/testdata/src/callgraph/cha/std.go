@@ -0,0 +1,17 @@
+package cha
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// The standard library packages are only analyzed for their facts:
+// their call graphs aren't built (and the SSA builder fails on some of them)
+type stdNotifier struct {
+	prefix string
+}
+
+func (n *stdNotifier) Notify() {
+	log.Print(fmt.Sprintf("%s: %d", n.prefix, os.Getpid()))
+}
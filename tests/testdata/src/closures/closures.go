@@ -0,0 +1,70 @@
+package closures
+
+import "sync"
+
+type counter struct {
+	mu       sync.Mutex
+	count    int
+	name     string
+	onChange func()
+	hooks    map[string]func() int
+	updates  chan func()
+}
+
+var lastReset func()
+
+func (c *counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+}
+
+func (c *counter) Reader() func() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return func() int { // want "Func literal is returned and accesses c.count guarded by counter.mu without holding the lock"
+		return c.count
+	}
+}
+
+func (c *counter) Watch() {
+	c.onChange = func() { // want "Func literal is stored and accesses c.count guarded by counter.mu"
+		c.count = 0
+	}
+	c.hooks["count"] = func() int { return c.count } // want "Func literal is stored"
+	lastReset = func() { c.count = 0 }               // want "Func literal is stored"
+}
+
+func (c *counter) Publish() {
+	c.updates <- func() { c.count++ } // want "Func literal is sent"
+}
+
+// Literals acquiring the lock themselves are fine
+func (c *counter) SafeReader() func() int {
+	return func() int {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.count
+	}
+}
+
+// Unguarded fields and values declared within the literal are fine
+func (c *counter) Name() func() string {
+	return func() string { return c.name }
+}
+
+func (c *counter) Factory() func() *counter {
+	return func() *counter {
+		fresh := &counter{}
+		fresh.count = 1
+		return fresh
+	}
+}
+
+// Local literals run while the function runs
+func (c *counter) Local() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	get := func() int { return c.count }
+	return get()
+}
@@ -0,0 +1,25 @@
+package color
+
+import "sync"
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.mu.Lock() // want `(?s)^\x1b\[1;31mMutex lock is acquired on this line\x1b\[0m\n\x1b\[34m   14\x1b\[0m \x1b\[34m\|\x1b\[0m \tc.mu.Lock\(\) //[^\n]*\n *\x1b\[34m\|\x1b\[0m \t\x1b\[1;31m\^{11}\x1b\[0m\n.*color.go:11: .*But the same lock was acquired here.*\n\x1b\[34m   11\x1b\[0m \x1b\[34m\|\x1b\[0m \tc.mu.Lock\(\)\n *\x1b\[34m\|\x1b\[0m \t\x1b\[36m-{11}\x1b\[0m\n$`
+	c.n++
+}
+
+func (c *counter) Get() int {
+	c.mu.Lock()
+	if c.n < 0 {
+		return 0 // want `(?s)^\x1b\[1;31mMutex lock must be released before this line\x1b\[0m\n.*\t\t\x1b\[1;31m\^{6}\x1b\[0m\n.*color.go:19: .*Lock was acquired here.*\t\x1b\[36m-{11}\x1b\[0m\n$`
+	}
+	c.mu.Unlock()
+	return c.n
+}
@@ -0,0 +1,79 @@
+package conditionalunlock
+
+import "sync"
+
+type buffer struct {
+	mu    sync.RWMutex
+	items []string
+}
+
+func (b *buffer) append(item string, lock bool) {
+	if lock {
+		b.mu.Lock()
+	}
+	b.items = append(b.items, item)
+	b.mu.Unlock() // want "Mutex is unlocked unconditionally but locked only when lock"
+}
+
+func (b *buffer) prepend(item string, locked bool) {
+	if !locked {
+		b.mu.Lock()
+	}
+	defer b.mu.Unlock() // want "Mutex is unlocked unconditionally but locked only when !locked"
+
+	b.items = append([]string{item}, b.items...)
+}
+
+func (b *buffer) count(lock bool) int {
+	if lock {
+		b.mu.RLock()
+	}
+	n := len(b.items)
+	b.mu.RUnlock() // want "Mutex is unlocked unconditionally but locked only when lock"
+	return n
+}
+
+// Unlocked under the same condition
+func (b *buffer) truncate(n int, lock bool) {
+	if lock {
+		b.mu.Lock()
+	}
+	b.items = b.items[:n]
+	if lock {
+		b.mu.Unlock()
+	}
+}
+
+// Released within the branch
+func (b *buffer) reset(lock bool) {
+	if lock {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+	}
+	b.items = nil
+}
+
+// Locked on both branches
+func (b *buffer) first(exclusive bool) string {
+	if exclusive {
+		b.mu.Lock()
+	} else {
+		b.mu.Lock()
+	}
+	defer b.mu.Unlock()
+	return b.items[0]
+}
+
+// Locked on the other path, too
+func (b *buffer) last(ready bool) string {
+	if !ready {
+		b.mu.Lock()
+		b.wait()
+	} else {
+		b.mu.Lock()
+	}
+	defer b.mu.Unlock()
+	return b.items[len(b.items)-1]
+}
+
+func (b *buffer) wait() {}
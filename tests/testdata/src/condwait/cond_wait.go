@@ -0,0 +1,61 @@
+package condwait
+
+import "sync"
+
+type condQueue struct {
+	mu      sync.Mutex
+	otherMu sync.Mutex
+	ready   *sync.Cond
+	items   []int
+}
+
+func newCondQueue() *condQueue {
+	q := &condQueue{}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *condQueue) Pop() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) == 0 {
+		q.ready.Wait()
+	}
+	item := q.items[0]
+	q.items = q.items[1:]
+	return item
+}
+
+func (q *condQueue) PopUnlocked() {
+	for len(q.items) == 0 {
+		q.ready.Wait() // want "Cond.Wait is called without holding its lock condQueue.mu"
+	}
+}
+
+func (q *condQueue) PopWrongLock() {
+	q.otherMu.Lock()
+	defer q.otherMu.Unlock()
+
+	for len(q.items) == 0 {
+		q.ready.Wait() // want "Cond.Wait is called while holding condQueue.otherMu, but the condition variable is bound to condQueue.mu"
+	}
+}
+
+func (q *condQueue) Drain() {
+	q.mu.Lock()
+	q.waitItems()
+	q.items = nil
+	q.mu.Unlock()
+}
+
+func (q *condQueue) waitItems() {
+	for len(q.items) == 0 {
+		q.ready.Wait() // Should NOT be flagged - called under lock
+	}
+}
+
+//mulint:requires mu
+func (q *condQueue) waitAnnotated() {
+	q.ready.Wait() // Should NOT be flagged - requires annotation
+}
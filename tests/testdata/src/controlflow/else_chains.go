@@ -0,0 +1,75 @@
+package controlflow
+
+import "sync"
+
+type ledger struct {
+	mu      sync.Mutex
+	entries []int
+}
+
+func (l *ledger) total() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sum := 0
+	for _, e := range l.entries {
+		sum += e
+	}
+	return sum
+}
+
+// Every branch of the chain unlocks
+func (l *ledger) Record(kind, amount int) int {
+	l.mu.Lock()
+	if kind == 0 {
+		l.mu.Unlock()
+	} else if kind == 1 {
+		l.entries = append(l.entries, amount)
+		l.mu.Unlock()
+	} else {
+		l.entries = append(l.entries, -amount)
+		l.mu.Unlock()
+	}
+	return l.total()
+}
+
+// Every branch locks
+func (l *ledger) Apply(credit bool, amount int) int {
+	if credit {
+		l.mu.Lock()
+	} else {
+		l.mu.Lock()
+		amount = -amount
+	}
+	l.entries = append(l.entries, amount)
+	sum := l.total() // want "Mutex lock is acquired on this line"
+	l.mu.Unlock()
+	return sum
+}
+
+// The branches leaving the function aren't joined
+func (l *ledger) Revert(n int) int {
+	l.mu.Lock()
+	if n < 0 {
+		l.mu.Unlock()
+		return 0
+	} else if n > len(l.entries) {
+		l.entries = nil
+	} else {
+		l.entries = l.entries[:len(l.entries)-n]
+	}
+	sum := l.total() // want "Mutex lock is acquired on this line"
+	l.mu.Unlock()
+	return sum
+}
+
+// Some branch holds the lock without releasing it
+func (l *ledger) Trim(n int) int {
+	l.mu.Lock()
+	if n == 0 {
+		l.mu.Unlock()
+	} else {
+		l.entries = l.entries[n:]
+	}
+	return 0 // want "Mutex lock must be released before this line"
+}
@@ -0,0 +1,47 @@
+package controlflow
+
+import "sync"
+
+type stages struct {
+	mu    sync.Mutex
+	stage int
+}
+
+func (s *stages) Advance(force bool) {
+	switch {
+	case force:
+		s.mu.Lock()
+		s.stage++
+		fallthrough
+	case s.stage > 0:
+		s.mu.Lock() // want "Mutex lock is acquired on this line"
+		s.stage++
+		s.mu.Unlock()
+	}
+}
+
+func (s *stages) Reset(kind int) int {
+	switch kind {
+	case 0:
+		s.mu.Lock()
+		fallthrough
+	case 1:
+		return s.stage // want "Mutex lock must be released before this line"
+	}
+	return 0
+}
+
+// Releasing the lock before falling through is fine
+func (s *stages) Bump(kind int) {
+	switch kind {
+	case 0:
+		s.mu.Lock()
+		s.stage = 0
+		s.mu.Unlock()
+		fallthrough
+	case 1:
+		s.mu.Lock()
+		s.stage++
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,42 @@
+package controlflow
+
+import "sync"
+
+type cursor struct {
+	mu    sync.Mutex
+	items []int
+}
+
+func (c *cursor) next(i int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return i + 1
+}
+
+func (c *cursor) Walk() {
+	c.mu.Lock()
+	for i := 0; i < len(c.items); i = c.next(i) { // want "Mutex lock is acquired on this line"
+		c.items[i] = 0
+	}
+	c.mu.Unlock()
+}
+
+func (c *cursor) Rotate() {
+	c.mu.Lock()
+	for i := 0; i < 3; c.mu.Lock() {
+		i++
+		c.mu.Unlock()
+	}
+	c.next(0) // want "Mutex lock is acquired on this line"
+	c.mu.Unlock()
+}
+
+func (c *cursor) Find(v int) bool {
+	for i := 0; i < len(c.items); c.mu.Lock() {
+		if c.items[i] == v {
+			return true // want "Mutex lock must be released before this line"
+		}
+		i++
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package controlflow
+
+import "sync"
+
+type labeled struct {
+	mu    sync.Mutex
+	items []int
+}
+
+func (l *labeled) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.items)
+}
+
+func (l *labeled) Retry(attempts int) {
+retry:
+	l.mu.Lock()
+	if l.size() == 0 { // want "Mutex lock is acquired on this line"
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+
+	attempts--
+	if attempts > 0 {
+		goto retry
+	}
+}
+
+func (l *labeled) Scan() {
+outer:
+	for i := 0; i < 3; i++ {
+		l.mu.Lock()
+		for _, item := range l.items {
+			if item == i {
+				l.mu.Unlock()
+				continue outer
+			}
+		}
+		l.size() // want "Mutex lock is acquired on this line"
+		l.mu.Unlock()
+	}
+}
+
+func (l *labeled) Pop() (int, bool) {
+	l.mu.Lock()
+	if len(l.items) == 0 {
+		goto empty
+	}
+	l.mu.Unlock()
+	return l.items[0], true
+
+empty:
+	return 0, false // want "Mutex lock must be released before this line"
+}
+
+// Jumping to a label after the unlock is fine
+func (l *labeled) Drop() bool {
+	l.mu.Lock()
+	if len(l.items) == 0 {
+		goto done
+	}
+	l.items = l.items[1:]
+
+done:
+	l.mu.Unlock()
+	return true
+}
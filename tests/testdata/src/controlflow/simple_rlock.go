@@ -1,4 +1,4 @@
-package tests
+package controlflow
 
 import (
 	"fmt"
@@ -13,7 +13,7 @@ func (a *another) Test() {
 	a.m.RLock()
 	defer a.m.RUnlock()
 
-	a.m.Lock() // want "Mutex lock is acquired on this line"
+	a.m.Lock() // want "Mutex write lock is acquired on this line while holding the read lock"
 	a.m.Unlock()
 }
 
@@ -0,0 +1,150 @@
+package controlflow
+
+import (
+	"errors"
+	"sync"
+)
+
+type journal struct {
+	mu      sync.Mutex
+	pending []string
+	flushed int
+}
+
+func (j *journal) write(lines []string) {
+	j.flushed += len(lines)
+}
+
+// The flag mirrors the lock state: every path releases the lock exactly once
+func (j *journal) Flush(async bool) {
+	j.mu.Lock()
+	unlocked := false
+	if async {
+		lines := j.pending
+		j.pending = nil
+		j.mu.Unlock()
+		unlocked = true
+		go j.write(lines)
+	}
+	if !unlocked {
+		j.write(j.pending)
+		j.pending = nil
+		j.mu.Unlock()
+	}
+}
+
+// The path with the flag set has already released the lock
+func (j *journal) Drain(limit int) int {
+	j.mu.Lock()
+	unlocked := false
+	if len(j.pending) > limit {
+		j.pending = j.pending[:limit]
+		j.mu.Unlock()
+		unlocked = true
+	}
+	if unlocked {
+		return limit
+	}
+	n := len(j.pending)
+	j.mu.Unlock()
+	return n
+}
+
+// The flag tracks the lock being held instead
+func (j *journal) Append(line string, sync bool) {
+	locked := true
+	j.mu.Lock()
+	j.pending = append(j.pending, line)
+	if !sync {
+		j.mu.Unlock()
+		locked = false
+	}
+	if locked {
+		j.write(j.pending)
+		j.pending = nil
+		j.mu.Unlock()
+	}
+}
+
+// The deferred unlock runs only if the lock is still held
+func (j *journal) Rotate(keep bool) int {
+	j.mu.Lock()
+	unlocked := false
+	defer func() {
+		if !unlocked {
+			j.mu.Unlock()
+		}
+	}()
+	if !keep {
+		j.pending = nil
+		j.mu.Unlock()
+		unlocked = true
+		return 0
+	}
+	return len(j.pending)
+}
+
+// The flag is set without releasing the lock, so the deferred unlock is skipped
+func (j *journal) Truncate(n int) {
+	j.mu.Lock()
+	unlocked := false
+	defer func() {
+		if !unlocked {
+			j.mu.Unlock()
+		}
+	}()
+	if n < len(j.pending) {
+		j.pending = j.pending[:n]
+		unlocked = true
+	}
+} // want `Mutex lock must be released before this line(.|\n)*not released when n < len\(j.pending\)`
+
+// The flag is set without releasing the lock
+func (j *journal) Reset(force bool) {
+	j.mu.Lock()
+	unlocked := false
+	if force {
+		j.pending = nil
+		unlocked = true
+	}
+	if !unlocked {
+		j.mu.Unlock()
+	}
+} // want `Mutex lock must be released before this line(.|\n)*not released when force and unlocked`
+
+// The deferred unlock is skipped once the lock is handed off, which it is in the error branch
+func (j *journal) Commit(n int) error {
+	j.mu.Lock()
+	handedOff := false
+	defer func() {
+		if handedOff {
+			return
+		}
+		j.mu.Unlock()
+	}()
+	if n > len(j.pending) {
+		j.mu.Unlock()
+		handedOff = true
+		return errors.New("not enough entries")
+	}
+	j.pending = j.pending[n:]
+	return nil
+}
+
+// The lock is marked as handed off without being released
+func (j *journal) Advance(n int) int {
+	j.mu.Lock()
+	handedOff := false
+	defer func() {
+		if handedOff {
+			return
+		}
+		j.mu.Unlock()
+	}()
+	if n > len(j.pending) {
+		handedOff = true
+		return 0 // want `^Mutex lock must be released before this line`
+	}
+	j.pending = j.pending[n:]
+	return n
+}
@@ -0,0 +1,74 @@
+package controlflow
+
+import (
+	"errors"
+	"sync"
+)
+
+type Ledger struct {
+	mu      sync.Mutex
+	entries []int
+	closed  bool
+}
+
+// The implicit return at the end of the function is reached without unlocking
+func (l *Ledger) Append(entry int) {
+	l.mu.Lock()
+	if entry > 0 {
+		l.entries = append(l.entries, entry)
+		l.mu.Unlock()
+	}
+} // want `Mutex lock must be released before this line(.|\n)*not released when entry <= 0`
+
+func (l *Ledger) Settle(mode string) {
+	l.mu.Lock()
+	switch mode {
+	case "all":
+		l.entries = nil
+		l.mu.Unlock()
+	case "last":
+		l.entries = l.entries[:len(l.entries)-1]
+	default:
+		l.mu.Unlock()
+	}
+} // want `Mutex lock must be released before this line(.|\n)*not released when case "last"`
+
+// Every path releases the lock
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errors.New("already closed")
+	}
+	l.closed = true
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *Ledger) Total() (total int) {
+	l.mu.Lock()
+	if len(l.entries) == 0 {
+		defer l.mu.Unlock()
+		return 0
+	}
+	for _, e := range l.entries {
+		total += e
+	}
+	l.mu.Unlock()
+	return total
+}
+
+// Panicking paths don't return
+func (l *Ledger) MustAppend(entry int) {
+	l.mu.Lock()
+	if entry < 0 {
+		panic("negative entry")
+	}
+	l.entries = append(l.entries, entry)
+	l.mu.Unlock()
+}
+
+// Locks never released are left to the wrapper detection
+func (l *Ledger) lock() {
+	l.mu.Lock()
+}
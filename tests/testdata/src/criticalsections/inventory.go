@@ -0,0 +1,45 @@
+package criticalsections
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Registry struct {
+	mu    sync.Mutex
+	items *list.List
+	names map[string]int
+	log   strings.Builder
+}
+
+func (r *Registry) Add(name string) {
+	r.mu.Lock() // want `External calls under lock r.mu\n\tcontainer/list.List:Len\n\tcontainer/list.List:PushBack\n\tfmt.Sprintf\n\tstrings.Builder:WriteString\n\tstrings.ToUpper\n`
+	defer r.mu.Unlock()
+
+	r.items.PushBack(name)
+	r.names[name] = r.items.Len()
+	r.log.WriteString(fmt.Sprintf("added %s\n", name))
+	r.log.WriteString(strings.ToUpper(name))
+}
+
+// Calls made after the unlock aren't part of the critical section
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	delete(r.names, name)
+	r.count()
+	r.mu.Unlock()
+
+	fmt.Println("removed", name)
+}
+
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count()
+}
+
+func (r *Registry) count() int {
+	return len(r.names)
+}
@@ -0,0 +1,71 @@
+package custommutex
+
+import (
+	"time"
+
+	"custommutex/xsync"
+)
+
+type registry struct {
+	mu    xsync.Mutex
+	names []string
+}
+
+func (r *registry) Register(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add(name) // want "Mutex lock is acquired on this line"
+}
+
+func (r *registry) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.names = append(r.names, name)
+}
+
+func (r *registry) Acquire() {
+	r.mu.Lock()
+}
+
+func (r *registry) Release() {
+	r.mu.Unlock()
+}
+
+func (r *registry) Reset() {
+	r.Acquire()
+
+	if len(r.names) == 0 {
+		return // want "Mutex lock must be released before this line"
+	}
+
+	r.names = nil
+	r.Release()
+}
+
+type counter struct {
+	mu     xsync.TimedMutex
+	waited time.Duration
+	hits   int
+}
+
+// Lock calls assigning the results are tracked, too
+func (c *counter) Hit(n int) {
+	waited := c.mu.Lock()
+
+	if n < 0 {
+		return // want "Mutex lock must be released before this line"
+	}
+
+	c.waited += waited
+	c.hits += n
+	c.mu.Unlock()
+}
+
+func (c *counter) Hits() int {
+	_ = c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.hits
+}
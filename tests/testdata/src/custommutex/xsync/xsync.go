@@ -0,0 +1,34 @@
+package xsync
+
+import (
+	"sync"
+	"time"
+)
+
+// Mutex is a drop-in replacement of sync.Mutex (e.g. instrumented with metrics).
+type Mutex struct {
+	mu sync.Mutex
+}
+
+func (m *Mutex) Lock() {
+	m.mu.Lock()
+}
+
+func (m *Mutex) Unlock() {
+	m.mu.Unlock()
+}
+
+// TimedMutex is a mutex reporting how long the lock has been waited for.
+type TimedMutex struct {
+	mu sync.Mutex
+}
+
+func (m *TimedMutex) Lock() time.Duration {
+	start := time.Now()
+	m.mu.Lock()
+	return time.Since(start)
+}
+
+func (m *TimedMutex) Unlock() {
+	m.mu.Unlock()
+}
@@ -0,0 +1,51 @@
+package cycles
+
+import "sync"
+
+type tree struct {
+	mu       sync.Mutex
+	children []*tree
+	visits   int
+}
+
+func (t *tree) Update() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.walk(0) // want "Mutex lock is acquired on this line" `(?s)Call under lock enters a recursion cycle: tree:walk -> tree:visit -> tree:walk.*Lock is acquired again in tree:visit`
+}
+
+func (t *tree) walk(depth int) {
+	if depth > 10 {
+		return
+	}
+	t.visit(depth)
+}
+
+func (t *tree) visit(depth int) {
+	t.mu.Lock()
+	t.visits++
+	t.mu.Unlock()
+
+	t.walk(depth + 1)
+}
+
+// Recursion without locks of the held mutex is fine
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *counter) Count(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.n = c.fib(n)
+}
+
+func (c *counter) fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return c.fib(n-1) + c.fib(n-2)
+}
@@ -0,0 +1,65 @@
+package deferunlock
+
+import "sync"
+
+type cache struct {
+	mu    sync.RWMutex
+	items map[string]string
+}
+
+func (c *cache) Get(key string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.items[key]
+}
+
+func (c *cache) Set(key, value string) {
+	c.mu.Lock() // want `Mutex lock is not followed by defer c.mu.Unlock\(\)`
+	c.items[key] = value
+	c.mu.Unlock()
+}
+
+func (c *cache) Len() int {
+	c.mu.RLock() // want `Mutex lock is not followed by defer c.mu.RUnlock\(\)`
+	n := len(c.items)
+	c.mu.RUnlock()
+	return n
+}
+
+func (c *cache) Delete(keys []string) {
+	for _, key := range keys {
+		c.mu.Lock() // want `Mutex lock is not followed by defer c.mu.Unlock\(\)`
+		delete(c.items, key)
+		c.mu.Unlock()
+	}
+}
+
+func (c *cache) Reset() {
+	c.mu.Lock()
+	defer func() {
+		c.mu.Unlock()
+	}()
+
+	c.items = make(map[string]string)
+}
+
+// Lock wrappers and annotated functions are exempt
+func (c *cache) lock() {
+	c.mu.Lock()
+}
+
+func (c *cache) unlock() {
+	c.mu.Unlock()
+}
+
+//mulint:manual-unlock
+func (c *cache) Load(load func() map[string]string) {
+	c.mu.Lock()
+	if c.items != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.items = load()
+	c.mu.Unlock()
+}
@@ -0,0 +1,45 @@
+package doublechecked
+
+import "sync"
+
+type lazyConfig struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (c *lazyConfig) Values() map[string]string {
+	if c.values == nil { // want "Guarded field lazyConfig.values is read without holding lazyConfig.mu"
+		c.mu.Lock()
+		if c.values == nil {
+			c.values = make(map[string]string)
+		}
+		c.mu.Unlock()
+	}
+	return c.values
+}
+
+func (c *lazyConfig) Reload(force bool) {
+	if force { // Should NOT be flagged - no guarded field is read
+		c.mu.Lock()
+		if c.values != nil {
+			c.values = make(map[string]string)
+		}
+		c.mu.Unlock()
+	}
+}
+
+var settings = struct {
+	mu     sync.Mutex
+	values map[string]string
+}{}
+
+func Setting(key string) string {
+	if settings.values == nil { // want "Guarded field settings.values is read without holding settings.mu"
+		settings.mu.Lock()
+		if settings.values == nil {
+			settings.values = map[string]string{}
+		}
+		settings.mu.Unlock()
+	}
+	return settings.values[key]
+}
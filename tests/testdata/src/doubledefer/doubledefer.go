@@ -0,0 +1,102 @@
+package doubledefer
+
+import "sync"
+
+type registry struct {
+	mu    sync.RWMutex
+	idxMu sync.Mutex
+	items map[string]int
+	index []string
+}
+
+func (r *registry) add(key string, value int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer r.mu.Unlock() // want "Unlock of r.mu is deferred more times than it's locked"
+
+	r.items[key] = value
+}
+
+func (r *registry) get(key string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defer r.mu.RUnlock() // want "Unlock of r.mu is deferred more times than it's locked"
+
+	return r.items[key]
+}
+
+func (r *registry) replace(key string, value int, reindex bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reindex {
+		defer r.mu.Unlock() // want "Unlock of r.mu is deferred more times than it's locked"
+		r.index = nil
+	}
+	r.items[key] = value
+}
+
+func (r *registry) remove(key string) {
+	r.mu.Lock()
+	defer func() {
+		r.index = nil
+		r.mu.Unlock()
+	}()
+	defer r.mu.Unlock() // want "Unlock of r.mu is deferred more times than it's locked"
+
+	delete(r.items, key)
+}
+
+func (r *registry) lock()   { r.mu.Lock() }
+func (r *registry) unlock() { r.mu.Unlock() }
+
+func (r *registry) clear() {
+	r.lock()
+	defer r.unlock()
+	defer r.unlock() // want "Unlock of r.mu is deferred more times than it's locked"
+
+	r.items = nil
+}
+
+// Different mutexes
+func (r *registry) reindex() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.idxMu.Lock()
+	defer r.idxMu.Unlock()
+
+	r.index = r.index[:0]
+	for key := range r.items {
+		r.index = append(r.index, key)
+	}
+}
+
+// Releases the lock acquired by the caller
+func (r *registry) releaseAfter(key string) {
+	defer r.mu.Unlock()
+
+	delete(r.items, key)
+}
+
+// Each branch defers the unlock of its own acquisition
+func (r *registry) upsert(key string, value int, exists bool) {
+	if exists {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.items[key] = value
+	} else {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.items[key] = value
+		r.index = append(r.index, key)
+	}
+}
+
+// Each iteration defers the unlock of its own acquisition
+func mergeAll(registries []*registry, key string) {
+	for _, r := range registries {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.items[key]++
+	}
+}
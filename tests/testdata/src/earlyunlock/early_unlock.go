@@ -0,0 +1,118 @@
+package earlyunlock
+
+import (
+	"errors"
+	"sync"
+)
+
+type relocker struct {
+	mu    sync.Mutex
+	state int
+}
+
+func (r *relocker) fetch() (int, error) { return 0, nil }
+
+func (r *relocker) Refresh() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mu.Unlock()
+	v, err := r.fetch()
+	if err != nil {
+		return err // want `(?s)Deferred unlock runs on an unlocked mutex when returning here.*Lock was released here and not re-acquired: r.mu.Unlock\(\)`
+	}
+	r.mu.Lock()
+
+	r.state = v
+	return nil
+}
+
+func (r *relocker) Forget() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.state = 0
+	r.mu.Unlock()
+} // want "Deferred unlock runs on an unlocked mutex when returning here"
+
+// Re-locking before returning is fine
+func (r *relocker) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mu.Unlock()
+	v, err := r.fetch()
+	r.mu.Lock()
+
+	if err != nil {
+		return errors.New("failed")
+	}
+	r.state = v
+	return nil
+}
+
+func (r *relocker) Drop(force bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if force {
+		r.mu.Unlock()
+	}
+	return r.state // want `(?s)Deferred unlock runs on an unlocked mutex when returning here.*Lock was released here and not re-acquired: r.mu.Unlock\(\)`
+}
+
+func (r *relocker) Reset(mode int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch mode {
+	case 0:
+		r.state = 0
+	case 1:
+		r.mu.Unlock()
+	}
+} // want "Deferred unlock runs on an unlocked mutex when returning here"
+
+func (r *relocker) lock()   { r.mu.Lock() }
+func (r *relocker) unlock() { r.mu.Unlock() }
+
+func (r *relocker) Swap(v int) int {
+	r.lock()
+	defer r.unlock()
+
+	prev := r.state
+	r.state = v
+	r.unlock()
+	return prev // want "Deferred unlock runs on an unlocked mutex when returning here"
+}
+
+// Re-locking within the branch is fine
+func (r *relocker) Sync(slow bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slow {
+		r.mu.Unlock()
+		v, err := r.fetch()
+		r.mu.Lock()
+		if err != nil {
+			return err
+		}
+		r.state = v
+	}
+	return nil
+}
+
+// Every case returns, the end of the function isn't reached
+func (r *relocker) Get(mode int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch mode {
+	case 0:
+		return r.state
+	default:
+		r.mu.Unlock()
+		return 0 // want "Deferred unlock runs on an unlocked mutex when returning here"
+	}
+}
@@ -0,0 +1,50 @@
+package entrypoints
+
+import "sync"
+
+type service struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (s *service) Run() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add("run") // want "Mutex lock is acquired on this line"
+}
+
+//mulint:entrypoint
+func (s *service) Serve() {
+	s.process()
+}
+
+func (s *service) process() {
+	s.mu.Lock()
+	s.add("serve") // want "Mutex lock is acquired on this line"
+	s.mu.Unlock()
+}
+
+// Unreachable from entry points - should NOT be flagged
+func (s *service) legacy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add("legacy")
+}
+
+// Unreachable from entry points - should NOT be flagged
+func (s *service) leak() {
+	s.mu.Lock()
+	if len(s.items) > 0 {
+		return
+	}
+	s.mu.Unlock()
+}
+
+func (s *service) add(item string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = append(s.items, item)
+}
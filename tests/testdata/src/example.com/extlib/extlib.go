@@ -0,0 +1,4 @@
+package extlib
+
+// Notify is an external function without a declared summary.
+func Notify(event string) {}
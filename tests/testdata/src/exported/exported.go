@@ -0,0 +1,56 @@
+package exported
+
+import "sync"
+
+type Cache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func (c *Cache) Get(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.items[key]
+}
+
+func (c *Cache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = value
+}
+
+func (c *Cache) Update(key string, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Get(key) // want "Mutex lock is acquired on this line" "Exported method Cache:Get locks the same mutex and is called under lock"
+	c.items[key] = value
+}
+
+func (c *Cache) Store(key string, value string, lock bool) {
+	if lock {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	c.items[key] = value
+}
+
+func (c *Cache) Replace(key string, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Store(key, value, false) // want "Exported method Cache:Store locks the same mutex and is called under lock"
+}
+
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clear() // Should NOT be flagged by the advisory - unexported helper
+}
+
+func (c *Cache) clear() {
+	c.items = make(map[string]string)
+}
@@ -0,0 +1,78 @@
+package exportedsurface
+
+import "sync"
+
+type Store struct {
+	mu   sync.Mutex
+	data map[string]int
+}
+
+func (s *Store) Set(key string, value int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = value
+	s.notify() // want "Mutex lock is acquired on this line"
+}
+
+func (s *Store) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+}
+
+func Compact(s *Store) {
+	s.compact()
+}
+
+func (s *Store) compact() {
+	s.mu.Lock()
+	s.notify() // want "Mutex lock is acquired on this line"
+	s.mu.Unlock()
+}
+
+type shard struct {
+	mu    sync.Mutex
+	count int
+}
+
+// Exported methods of unexported types may implement interfaces
+func (s *shard) Inc() {
+	s.mu.Lock()
+	if s.count < 0 {
+		return // want "Mutex lock must be released before this line"
+	}
+	s.count++
+	s.mu.Unlock()
+}
+
+var global Store
+
+func init() {
+	global.mu.Lock()
+	global.warmup() // want "Mutex lock is acquired on this line"
+	global.mu.Unlock()
+}
+
+func (s *Store) warmup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+}
+
+// Not reachable from the exported surface - should NOT be flagged
+func (s *Store) legacySet(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = 0
+	s.notify()
+}
+
+// Not reachable from the exported surface - should NOT be flagged
+func (s *shard) reset() {
+	s.mu.Lock()
+	if s.count == 0 {
+		return
+	}
+	s.count = 0
+	s.mu.Unlock()
+}
@@ -0,0 +1,64 @@
+package externs
+
+import "sync"
+
+//mulint:summary blocks
+func park(ch uintptr)
+
+//mulint:summary calls-back
+func each(items []int, fn func(int))
+
+//mulint:summary locks-nothing
+func nanotime() int64
+
+type scheduler struct {
+	mu    sync.Mutex
+	items []int
+	sum   int
+	at    int64
+}
+
+func (s *scheduler) Wait() { // want Wait:`mayBlock\(externs\.park\)`
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	park(0) // want "Blocking call park while holding lock"
+}
+
+func (s *scheduler) Idle() { // want Idle:`mayBlock\(externs\.park\)`
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sleep() // want `(?s)Blocking call scheduler:sleep while holding lock.*It calls park, which blocks`
+}
+
+func (s *scheduler) sleep() { // want sleep:`mayBlock\(externs\.park\)`
+	park(1)
+}
+
+func (s *scheduler) Sum() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	each(s.items, func(item int) { // want "Mutex lock is acquired on this line"
+		s.add(item)
+	})
+	each(s.items, s.add) // want "Mutex lock is acquired on this line"
+}
+
+func (s *scheduler) add(item int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sum += item
+}
+
+func (s *scheduler) Touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.at = nanotime()
+}
+
+func (s *scheduler) Unlocked() { // want Unlocked:`mayBlock\(externs\.park\)`
+	park(2)
+}
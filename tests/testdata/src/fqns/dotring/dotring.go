@@ -0,0 +1,10 @@
+package dotring
+
+// Buffer is named after ring.Buffer, but its resets block
+type Buffer struct {
+	done chan struct{}
+}
+
+func (b *Buffer) Reset() { // want Reset:`mayBlock\(fqns/dotring\.Buffer:Reset\)`
+	<-b.done
+}
@@ -0,0 +1,30 @@
+package dotted
+
+import (
+	"sync"
+
+	. "fqns/dotring"
+	"fqns/ring"
+)
+
+type store struct {
+	mu      sync.Mutex
+	pending *Buffer
+	done    *ring.Buffer
+}
+
+// The dot-imported Buffer is told apart from ring.Buffer
+func (s *store) Clear() { // want Clear:`mayBlock\(fqns/dotring\.Buffer:Reset\)`
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done.Reset()
+	s.pending.Reset() // want `^Blocking call Buffer:Reset while holding lock\n`
+}
+
+func (s *store) Truncate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done.Reset()
+}
@@ -0,0 +1,37 @@
+package fqns
+
+import (
+	"sync"
+
+	"fqns/ring"
+)
+
+type Buffer struct {
+	mu    sync.Mutex
+	items []string
+	ring  *ring.Buffer
+}
+
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.items = nil
+}
+
+// The methods of the same-named types of other packages are not the ones of Buffer
+func (b *Buffer) Rotate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ring.Reset()
+	b.items = nil
+}
+
+// Neither are the package-level functions named after them
+func (b *Buffer) Drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ring.Reset(b.ring)
+}
@@ -0,0 +1,17 @@
+package ring
+
+// Buffer never blocks
+type Buffer struct {
+	items []string
+}
+
+func (b *Buffer) Reset() {
+	b.items = b.items[:0]
+}
+
+// Reset is a package-level function named after the methods
+func Reset(buffers ...*Buffer) {
+	for _, b := range buffers {
+		b.Reset()
+	}
+}
@@ -0,0 +1,21 @@
+// Package errgroup is a minimal stub of golang.org/x/sync/errgroup for analysis tests.
+package errgroup
+
+type Group struct {
+	errs chan error
+}
+
+func (g *Group) Go(f func() error) {
+	go func() {
+		g.errs <- f()
+	}()
+}
+
+func (g *Group) Wait() error {
+	return <-g.errs
+}
+
+func (g *Group) TryGo(f func() error) bool {
+	g.Go(f)
+	return true
+}
@@ -0,0 +1,24 @@
+// Package singleflight is a minimal stub of golang.org/x/sync/singleflight for analysis tests.
+package singleflight
+
+type Result struct {
+	Val    interface{}
+	Err    error
+	Shared bool
+}
+
+type Group struct{}
+
+func (g *Group) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	v, err = fn()
+	return v, err, false
+}
+
+func (g *Group) DoChan(key string, fn func() (interface{}, error)) <-chan Result {
+	ch := make(chan Result, 1)
+	go func() {
+		v, err := fn()
+		ch <- Result{Val: v, Err: err}
+	}()
+	return ch
+}
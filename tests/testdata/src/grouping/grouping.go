@@ -0,0 +1,37 @@
+package grouping
+
+import "sync"
+
+type hot struct {
+	mu    sync.Mutex
+	other sync.Mutex
+	n     int
+}
+
+func (h *hot) Process() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.inc() // want `(?s)Mutex lock h.mu is acquired again at 3 places in hot:Process.*grouping.go:16: Also acquired on this line: h.dec\(\).*grouping.go:17: Also acquired on this line: h.inc\(\)`
+	h.dec()
+	h.inc()
+}
+
+func (h *hot) Single() {
+	h.other.Lock()
+	defer h.other.Unlock()
+
+	h.other.Lock() // want "Mutex lock is acquired on this line"
+}
+
+func (h *hot) inc() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.n++
+}
+
+func (h *hot) dec() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.n--
+}
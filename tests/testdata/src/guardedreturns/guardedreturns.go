@@ -0,0 +1,86 @@
+package guardedreturns
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+type stats struct {
+	hits int
+}
+
+type cache struct {
+	mu      sync.RWMutex
+	entries map[string]string
+	order   []string
+	stats   stats
+	last    *string
+	size    int
+}
+
+func (c *cache) Entries() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.entries // want `c.entries is guarded by c.mu and returned under lock(?s).*maps.Clone\(c.entries\)`
+}
+
+func (c *cache) Order() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.order // want `c.order is guarded by c.mu and returned under lock(?s).*slices.Clone\(c.order\)`
+}
+
+func (c *cache) Recent(n int) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if n > len(c.order) {
+		return c.order[:] // want "c.order\\[:\\] is guarded by c.mu and returned under lock"
+	}
+	return c.order[len(c.order)-n:] // want "is guarded by c.mu and returned under lock"
+}
+
+func (c *cache) Stats() *stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.hits++
+	return &c.stats // want "&c.stats is guarded by c.mu and returned under lock"
+}
+
+func (c *cache) Last() (*string, bool) {
+	c.mu.Lock()
+	last := c.last
+	c.mu.Unlock()
+	return last, last != nil
+}
+
+func (c *cache) Put(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+	c.order = append(c.order, key)
+	c.last = &value
+	c.size++
+}
+
+// Copies and values are fine
+func (c *cache) Snapshot() (map[string]string, []string, int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return maps.Clone(c.entries), slices.Clone(c.order), c.size
+}
+
+// Returned after releasing the lock (the caller is expected to copy it under lock)
+func (c *cache) Keys() []string {
+	c.mu.RLock()
+	keys := c.order
+	c.mu.RUnlock()
+	return keys
+}
+
+// Another cache's data is guarded by another mutex
+func (c *cache) Other(other *cache) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return other.order
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+)
+
+type server struct {
+	mu    sync.Mutex
+	mux   *http.ServeMux
+	stats map[string]int
+}
+
+func newServer() *server {
+	s := &server{mux: http.NewServeMux(), stats: make(map[string]int)}
+	s.mux.HandleFunc("/index", s.handleIndex)
+	s.mux.Handle("/stats", logging(http.HandlerFunc(s.handleStats)))
+	s.mux.HandleFunc("/health", s.handleHealth)
+	return s
+}
+
+func logging(next http.Handler) http.Handler {
+	return next
+}
+
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.track(r.URL.Path) // want "Shared handler helper server:track is called under lock"
+}
+
+func (s *server) handleStats(w http.ResponseWriter, r *http.Request) {
+	s.track("stats")
+
+	s.mu.Lock()
+	s.stats["stats"]++
+	s.mu.Unlock()
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status() // Should NOT be flagged - no other handler uses status()
+}
+
+func (s *server) track(path string) {
+	s.stats[path]++
+}
+
+func (s *server) status() int {
+	return len(s.stats)
+}
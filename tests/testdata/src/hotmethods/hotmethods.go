@@ -0,0 +1,102 @@
+package hotmethods
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type Counter struct {
+	mu    sync.Mutex
+	name  string
+	value int
+}
+
+func (c *Counter) String() string { // want `Method Counter:String acquires locks, though it's commonly called implicitly or on hot paths\n\t[^\n]*hotmethods.go:16: Lock is acquired here: c.mu.Lock\(\)`
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return fmt.Sprintf("%s=%d", c.name, c.value)
+}
+
+func (c *Counter) snapshot() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+type LimitError struct {
+	counter *Counter
+	limit   int
+}
+
+// The lock is acquired by the callee
+func (e *LimitError) Error() string { // want `Method LimitError:Error acquires locks(.|\n)*hotmethods.go:22: Lock is acquired in Counter:snapshot: c.mu.Lock\(\)`
+	return fmt.Sprintf("limit %d exceeded: %d", e.limit, e.counter.snapshot())
+}
+
+type Entries struct {
+	mu    sync.RWMutex
+	items []*Counter
+}
+
+func (s *Entries) Len() int { return len(s.items) }
+
+func (s *Entries) Less(i, j int) bool { // want `Method Entries:Less acquires locks(.|\n)*hotmethods.go:45: Lock is acquired here: s.mu.RLock\(\)`
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.items[i].value < s.items[j].value
+}
+
+func (s *Entries) Swap(i, j int) { s.items[i], s.items[j] = s.items[j], s.items[i] }
+
+// No locks
+func (c Counter) Hash() uint64 {
+	return uint64(len(c.name))
+}
+
+// Locks of the values created by the method aren't held by the callers
+func (e *LimitError) Hash() uint64 {
+	c := &Counter{name: "tmp"}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(e.limit)
+}
+
+// Functions aren't methods
+func String(c *Counter) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.name
+}
+
+type Ranking struct {
+	mu     sync.Mutex
+	scores []int
+}
+
+func (r *Ranking) Len() int { return len(r.scores) }
+
+func (r *Ranking) Less(i, j int) bool { return r.scores[i] > r.scores[j] }
+
+// sort.Sort calls Swap many times
+func (r *Ranking) Swap(i, j int) { // want `Method Ranking:Swap acquires locks(.|\n)*Lock is acquired here: r.mu.Lock\(\)`
+	r.mu.Lock()
+	r.scores[i], r.scores[j] = r.scores[j], r.scores[i]
+	r.mu.Unlock()
+}
+
+func rank(r *Ranking) {
+	sort.Sort(sort.Reverse(r))
+}
+
+type Pair struct {
+	mu    sync.Mutex
+	items [2]string
+}
+
+// Not sorted
+func (p *Pair) Swap(i, j int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.items[i], p.items[j] = p.items[j], p.items[i]
+}
@@ -0,0 +1,90 @@
+package localmutexes
+
+import (
+	"os"
+	"sync"
+)
+
+// The mutex is locked in Rotate only, and the fields it guards aren't used elsewhere
+type Logger struct {
+	mu       sync.Mutex
+	rotateMu sync.Mutex
+	file     *os.File
+	rotated  int
+	lines    []string
+}
+
+func (l *Logger) Rotate(name string) error {
+	l.rotateMu.Lock() // want `Mutex Logger.rotateMu is only locked in Logger:Rotate and guards no fields accessed elsewhere(.|\n)*Fields accessed under the lock: Logger.rotated`
+	defer l.rotateMu.Unlock()
+	l.rotated++
+	return os.Rename(name, name+".1")
+}
+
+// The guarded fields are accessed elsewhere (without the lock)
+func (l *Logger) Write(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+}
+
+func (l *Logger) Lines() []string {
+	return l.lines
+}
+
+// The mutex is locked in one function and unlocked in another one
+type Session struct {
+	mu     sync.Mutex
+	active bool
+}
+
+func (s *Session) begin() {
+	s.mu.Lock()
+}
+
+func (s *Session) end() {
+	s.mu.Unlock()
+}
+
+// The mutex serializes the writes, guarding no fields
+type Console struct {
+	writeMu sync.Mutex
+}
+
+func (c *Console) Print(msg string) error {
+	c.writeMu.Lock() // want `Mutex Console.writeMu is only locked in Console:Print and guards no fields accessed elsewhere(.|\n)*No fields are accessed under the lock`
+	defer c.writeMu.Unlock()
+	_, err := os.Stdout.WriteString(msg)
+	return err
+}
+
+// The address of the mutex is taken
+type Queue struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	items []string
+}
+
+func (q *Queue) Init() {
+	q.cond = sync.NewCond(&q.mu)
+}
+
+func (q *Queue) pop() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item := q.items[0]
+	q.items = q.items[1:]
+	return item
+}
+
+// Exported mutexes may be locked by other packages
+type Registry struct {
+	Mu    sync.Mutex
+	names []string
+}
+
+func (r *Registry) Add(name string) {
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	r.names = append(r.names, name)
+}
@@ -0,0 +1,118 @@
+package lockedconvention
+
+import "sync"
+
+type Buffer struct {
+	mu      sync.Mutex
+	flushMu sync.Mutex
+	data    []byte
+	out     [][]byte
+}
+
+func (b *Buffer) flushLocked() {
+	b.out = append(b.out, b.data)
+	b.data = nil
+}
+
+func (b *Buffer) Write(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	b.flushLocked()
+}
+
+func (b *Buffer) Flush() {
+	b.flushLocked() // want "Function Buffer:flushLocked is called without holding the lock"
+}
+
+// The lock is released before the call
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	b.data = nil
+	b.mu.Unlock()
+	b.flushLocked() // want "Function Buffer:flushLocked is called without holding the lock"
+}
+
+// Holding the lock of another buffer doesn't count
+func (b *Buffer) Copy(other *Buffer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	other.flushLocked() // want "Function Buffer:flushLocked is called without holding the lock"
+}
+
+// *Locked functions call each other with the lock held by their callers
+func (b *Buffer) resetLocked() {
+	b.data = nil
+	b.flushLocked()
+}
+
+func (b *Buffer) sizeLocked() int {
+	b.mu.Lock() // want "Function Buffer:sizeLocked acquires the lock it must be called with"
+	defer b.mu.Unlock()
+	return len(b.data)
+}
+
+// Inner mutexes not declared as required can be acquired
+//
+//mulint:requires mu
+func (b *Buffer) syncLocked() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.out = nil
+}
+
+// Functions declaring the required lock are expected to be called under it
+//
+//mulint:requires mu
+func (b *Buffer) drain() {
+	b.flushLocked()
+}
+
+// New values haven't escaped yet, so nobody else can access them
+func NewBuffer(data []byte) *Buffer {
+	b := &Buffer{data: data}
+	b.flushLocked()
+	return b
+}
+
+// Predicates are not lock-requiring helpers
+func (b *Buffer) IsLocked() bool {
+	if !b.mu.TryLock() {
+		return true
+	}
+	b.mu.Unlock()
+	return false
+}
+
+func (b *Buffer) Check() bool {
+	return b.IsLocked()
+}
+
+// Calls in func literals may run with a different lock state
+func (b *Buffer) Later() func() {
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.flushLocked()
+	}
+}
+
+var registryMu sync.Mutex
+
+var registry = map[string]*Buffer{}
+
+func registerLocked(name string, b *Buffer) {
+	registryMu.Lock() // want "Function registerLocked acquires the lock it must be called with"
+	registry[name] = b
+	registryMu.Unlock()
+}
+
+func Register(name string, b *Buffer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registerLocked(name, b) // want "Mutex lock is acquired on this line"
+}
+
+func RegisterUnsafe(name string, b *Buffer) {
+	registerLocked(name, b) // want "Function registerLocked is called without holding the lock"
+}
@@ -0,0 +1,63 @@
+package lockedvariants
+
+import "sync"
+
+type Buffer struct {
+	mu   sync.Mutex
+	data []byte
+	out  [][]byte
+}
+
+func (b *Buffer) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked()
+}
+
+func (b *Buffer) flushLocked() {
+	b.out = append(b.out, b.data)
+	b.data = nil
+}
+
+func (b *Buffer) Write(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	b.flush() // want "Mutex lock is acquired on this line"
+}
+
+func (b *Buffer) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = nil
+}
+
+// No Locked variant to suggest
+func (b *Buffer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reset() // want "Mutex lock is acquired on this line"
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Buffer{}
+)
+
+func register(name string, b *Buffer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registerLocked(name, b)
+}
+
+func registerLocked(name string, b *Buffer) {
+	registry[name] = b
+}
+
+func RegisterAll(buffers map[string]*Buffer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for name, b := range buffers {
+		register(name, b) // want "Mutex lock is acquired on this line"
+	}
+}
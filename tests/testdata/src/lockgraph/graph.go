@@ -0,0 +1,56 @@
+package lockgraph
+
+import "sync"
+
+type Cache struct {
+	mu      sync.Mutex
+	statsMu sync.Mutex
+	items   map[string]string
+	hits    int
+}
+
+func (c *Cache) Get(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.record()
+	return c.items[key]
+}
+
+func (c *Cache) Put(key, value string) {
+	c.lock()
+	c.items[key] = value
+	c.unlock()
+}
+
+func (c *Cache) Stats() int {
+	return c.stats()
+}
+
+// Warm reaches record through Get and stats through Stats
+func (c *Cache) Warm(keys []string) int {
+	for _, key := range keys {
+		c.Get(key)
+	}
+	return c.Stats()
+}
+
+func (c *Cache) lock() {
+	c.mu.Lock()
+}
+
+func (c *Cache) unlock() {
+	c.mu.Unlock()
+}
+
+func (c *Cache) record() {
+	c.statsMu.Lock()
+	c.hits++
+	c.statsMu.Unlock()
+}
+
+func (c *Cache) stats() int {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.hits
+}
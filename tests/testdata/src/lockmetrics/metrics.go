@@ -0,0 +1,47 @@
+package lockmetrics
+
+import "sync"
+
+type Store struct {
+	mu      sync.Mutex
+	indexMu sync.Mutex
+	items   map[string]string
+	index   []string
+}
+
+func (s *Store) Get(key string) string {
+	s.mu.Lock() // want `Lock metrics for Store.mu\n\tacquiring functions: 3\n\tmax held set: 1\n\tHigh fan-in \(3 functions\); consider splitting the mutex`
+	defer s.mu.Unlock()
+	return s.items[key]
+}
+
+func (s *Store) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[key] = value
+	s.reindex(key)
+}
+
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	delete(s.items, key)
+	s.mu.Unlock()
+}
+
+// The lock of the caller is held, too
+func (s *Store) reindex(key string) {
+	s.indexMu.Lock() // want `Lock metrics for Store.indexMu\n\tacquiring functions: 1\n\tmax held set: 2\n$`
+	defer s.indexMu.Unlock()
+	s.index = append(s.index, key)
+}
+
+// Locks of the values created by the function aren't counted
+func NewStore(keys []string) *Store {
+	s := &Store{items: make(map[string]string)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		s.items[key] = ""
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+//go:build go1.21
+
+package loopvars
+
+import "sync"
+
+type item struct {
+	mu    sync.Mutex
+	count int
+}
+
+func touchAll(items []*item) {
+	for _, it := range items {
+		go func() {
+			it.mu.Lock() // want "Goroutine locks the mutex of loop variable it shared by all iterations"
+			defer it.mu.Unlock()
+			it.count++
+		}()
+	}
+}
+
+type node struct {
+	mu    sync.Mutex
+	count int
+	next  *node
+}
+
+func touchList(head *node) {
+	for n := head; n != nil; n = n.next {
+		go func() {
+			n.mu.Lock() // want "Goroutine locks the mutex of loop variable n shared by all iterations"
+			defer n.mu.Unlock()
+			n.count++
+		}()
+	}
+}
+
+func touchValues(items []item) {
+	for _, it := range items {
+		go func() {
+			it.mu.Lock() // want "Goroutine locks the mutex of loop variable it shared by all iterations"
+			it.count++
+			it.mu.Unlock()
+		}()
+	}
+}
+
+// The variable is copied for each iteration
+func touchCopied(items []*item) {
+	for _, it := range items {
+		it := it
+		go func() {
+			it.mu.Lock()
+			defer it.mu.Unlock()
+			it.count++
+		}()
+	}
+}
+
+// The variable is passed as an argument
+func touchPassed(items []*item) {
+	for _, it := range items {
+		go func(it *item) {
+			it.mu.Lock()
+			defer it.mu.Unlock()
+			it.count++
+		}(it)
+	}
+}
+
+// Locks outside goroutines happen within the iteration
+func touchSync(items []*item) {
+	for _, it := range items {
+		it.mu.Lock()
+		it.count++
+		it.mu.Unlock()
+	}
+}
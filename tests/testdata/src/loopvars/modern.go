@@ -0,0 +1,12 @@
+package loopvars
+
+// Loop variables are per-iteration since Go 1.22
+func touchAllModern(items []*item) {
+	for _, it := range items {
+		go func() {
+			it.mu.Lock()
+			defer it.mu.Unlock()
+			it.count++
+		}()
+	}
+}
@@ -0,0 +1,67 @@
+package maybesync
+
+import "sync"
+
+type emitter struct {
+	handlers map[string][]func()
+}
+
+func (e *emitter) On(event string, fn func()) {
+	e.handlers[event] = append(e.handlers[event], fn)
+}
+
+type future struct {
+	callbacks []func(string)
+}
+
+func (f *future) Then(fn func(string)) *future {
+	f.callbacks = append(f.callbacks, fn)
+	return f
+}
+
+type session struct {
+	mu      sync.Mutex
+	emitter *emitter
+	result  *future
+	state   string
+	log     []string
+}
+
+func (s *session) setState(state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
+
+func (s *session) Subscribe() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emitter.On("close", func() { // want "Callback registered under lock acquires the same lock and deadlocks if emitter:On invokes it synchronously"
+		s.setState("closed")
+	})
+}
+
+func (s *session) Await() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.result.Then(s.setState) // want "deadlocks if future:Then invokes it synchronously"
+}
+
+// Callbacks not acquiring the lock are fine
+func (s *session) Trace() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emitter.On("data", func() {
+		println("data")
+	})
+}
+
+// Registered without holding the lock
+func (s *session) Reset() {
+	s.emitter.On("reset", func() {
+		s.setState("")
+	})
+}
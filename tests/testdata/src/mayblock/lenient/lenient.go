@@ -0,0 +1,27 @@
+package lenient
+
+import (
+	"sync"
+
+	"mayblock/queue"
+)
+
+type worker struct {
+	mu sync.Mutex
+	q  *queue.Queue
+}
+
+// Without the strict profile, only the calls reaching functions declared as blocking are reported
+func (w *worker) Close() { // want Close:`mayBlock\(.*queue\.park\)`
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.q.Drain() // want `^Blocking call Queue:Drain while holding lock\n(.*\n)*\tIt calls park, which blocks\n`
+}
+
+func (w *worker) Submit(item string) { // want Submit:`mayBlock\(.*queue\.Queue:Put\)`
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.q.Put(item)
+}
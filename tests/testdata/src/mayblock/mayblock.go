@@ -0,0 +1,78 @@
+package mayblock
+
+import (
+	"sync"
+
+	"mayblock/queue"
+)
+
+type worker struct {
+	mu      sync.Mutex
+	q       *queue.Queue
+	pending int
+}
+
+// The functions of other packages that may block are known from their facts,
+// computed the same way whether the strict profile is enabled for the packages or not
+func (w *worker) Submit(item string) { // want Submit:`mayBlock\(.*queue\.Queue:Put\)`
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending++
+	w.q.Put(item) // want `^Blocking call Queue:Put while holding lock\n`
+}
+
+func (w *worker) Poll() string { // want Poll:`mayBlock\(.*queue\.Queue:Take\)`
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending--
+	return w.q.Take() // want `^Blocking call Queue:Take while holding lock\n`
+}
+
+// Facts are transitive: Retry sleeps through Backoff
+func (w *worker) Resubmit(item string) bool { // want Resubmit:`mayBlock\(time\.Sleep\)`
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return queue.Retry(3, func() bool { // want `^Blocking call Retry while holding lock\n(.*\n)*\tIt calls Sleep, which blocks\n`
+		return w.q.TryPut(item)
+	})
+}
+
+// So are the package functions calling the ones of other packages
+func (w *worker) drain() { // want drain:`mayBlock\(.*queue\.Queue:Take\)`
+	for w.q.Len() > 0 {
+		w.q.Take()
+	}
+}
+
+func (w *worker) Close() { // want Close:`mayBlock\(.*queue\.Queue:Take\)`
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.drain() // want `^Blocking call worker:drain while holding lock\n(.*\n)*\tIt calls Queue:Take, which blocks\n`
+}
+
+// Non-blocking functions and the blocking calls of goroutines are fine
+func (w *worker) Offer(item string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.q.Len() > w.pending {
+		return false
+	}
+	go w.q.Put(item)
+	return w.q.TryPut(item)
+}
+
+// Calls made without the lock are fine
+//
+//mulint:manual-unlock
+func (w *worker) Wait() string { // want Wait:`mayBlock\(.*queue\.Queue:Take\)`
+	w.mu.Lock()
+	w.pending++
+	w.mu.Unlock()
+
+	return w.q.Take()
+}
@@ -0,0 +1,58 @@
+package queue
+
+import "time"
+
+type Queue struct {
+	items chan string
+}
+
+func New(size int) *Queue {
+	return &Queue{items: make(chan string, size)}
+}
+
+// Put blocks until there is room for the item
+func (q *Queue) Put(item string) { // want Put:`mayBlock\(.*queue\.Queue:Put\)`
+	q.items <- item
+}
+
+// Take blocks until an item is available
+func (q *Queue) Take() string { // want Take:`mayBlock\(.*queue\.Queue:Take\)`
+	return <-q.items
+}
+
+// TryPut never blocks
+func (q *Queue) TryPut(item string) bool {
+	select {
+	case q.items <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *Queue) Len() int {
+	return len(q.items)
+}
+
+//mulint:summary blocks
+func park(q *Queue)
+
+// Drain parks until the queue is drained
+func (q *Queue) Drain() { // want Drain:`mayBlock\(.*queue\.park\)`
+	park(q)
+}
+
+func Backoff(attempt int) { // want Backoff:`mayBlock\(time\.Sleep\)`
+	time.Sleep(time.Duration(attempt) * time.Millisecond)
+}
+
+// Retry blocks through Backoff
+func Retry(attempts int, fn func() bool) bool { // want Retry:`mayBlock\(time\.Sleep\)`
+	for i := range attempts {
+		if fn() {
+			return true
+		}
+		Backoff(i)
+	}
+	return false
+}
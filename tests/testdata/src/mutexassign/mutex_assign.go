@@ -0,0 +1,60 @@
+package mutexassign
+
+import "sync"
+
+type Pool struct {
+	mu    sync.Mutex
+	rw    *sync.RWMutex
+	items []string
+}
+
+func NewPool() *Pool {
+	p := &Pool{}
+	p.rw = &sync.RWMutex{}
+	return p
+}
+
+// Constructors may initialize mutexes of values they've got from elsewhere
+func newPoolFrom(p Pool) Pool {
+	p.mu = sync.Mutex{}
+	p.rw = new(sync.RWMutex)
+	return p
+}
+
+func (p *Pool) Reset() {
+	p.mu.Lock()
+	p.items = nil
+	p.mu = sync.Mutex{} // want "Mutex p.mu is assigned outside of a constructor"
+}
+
+func (p *Pool) Reinit() {
+	p.rw = &sync.RWMutex{} // want "Mutex p.rw is assigned outside of a constructor"
+}
+
+// New values can be set up before they escape
+func (p *Pool) Clone() *Pool {
+	clone := &Pool{}
+	clone.mu = sync.Mutex{}
+	clone.items = p.items
+	return clone
+}
+
+var pools []*Pool
+
+// The new value is shared before its mutex is set
+func (p *Pool) Spawn() *Pool {
+	child := &Pool{}
+	pools = append(pools, child)
+	child.rw = &sync.RWMutex{} // want "Mutex child.rw is assigned outside of a constructor"
+	return child
+}
+
+type Cache struct {
+	mu sync.Mutex
+}
+
+// Constructors of other types don't count
+func NewPoolWithCache(c *Cache) *Pool {
+	c.mu = sync.Mutex{} // want "Mutex c.mu is assigned outside of a constructor"
+	return &Pool{}
+}
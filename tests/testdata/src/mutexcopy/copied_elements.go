@@ -0,0 +1,75 @@
+package mutexcopy
+
+import "sync"
+
+type entry struct {
+	mu    sync.Mutex
+	hits  int
+	state *shared
+}
+
+type shared struct {
+	mu sync.RWMutex
+}
+
+type registry struct {
+	entries  map[string]entry
+	pointers map[string]*entry
+	list     []entry
+}
+
+func (r *registry) Hit(key string) {
+	e := r.entries[key]
+	e.mu.Lock() // want "Mutex e.mu is locked on a copy of a map value"
+	e.hits++
+	e.mu.Unlock()
+}
+
+func (r *registry) HitIfExists(key string) {
+	e, ok := r.entries[key]
+	if !ok {
+		return
+	}
+	e.mu.Lock() // want "Mutex e.mu is locked on a copy of a map value"
+	defer e.mu.Unlock()
+	e.hits++
+}
+
+func (r *registry) HitAll() {
+	for _, e := range r.list {
+		e.mu.Lock() // want "Mutex e.mu is locked on a copy of a slice element"
+		e.hits++
+		e.mu.Unlock()
+	}
+}
+
+func (r *registry) HitFirst() {
+	e := r.list[0]
+	e.mu.Lock() // want "Mutex e.mu is locked on a copy of a slice element"
+	defer e.mu.Unlock()
+	e.hits++
+}
+
+// Pointers share the mutex
+func (r *registry) HitPointer(key string) {
+	e := r.pointers[key]
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hits++
+}
+
+// Indexing the slice locks the stored element
+func (r *registry) HitInPlace() {
+	for i := range r.list {
+		r.list[i].mu.Lock()
+		r.list[i].hits++
+		r.list[i].mu.Unlock()
+	}
+}
+
+// The copy points to the shared state
+func (r *registry) Inspect(key string) {
+	e := r.entries[key]
+	e.state.mu.RLock()
+	defer e.state.mu.RUnlock()
+}
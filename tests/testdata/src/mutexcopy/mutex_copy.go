@@ -0,0 +1,98 @@
+package mutexcopy
+
+import "sync"
+
+type stats struct {
+	mu   sync.Mutex
+	hits int
+}
+
+func (s stats) report() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.hits
+}
+
+func (s *stats) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hits = 0
+}
+
+func publish(s stats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.hits
+}
+
+type server struct {
+	mu    sync.Mutex
+	stats stats
+	ref   *stats
+	out   chan stats
+}
+
+func (s *stats) Publish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	go publish(*s) // want "Value containing the held mutex s.mu is copied into a goroutine"
+}
+
+func (s *stats) Report() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	go s.report() // want "Value containing the held mutex s.mu is copied into a goroutine"
+}
+
+func (s *server) PublishStats() {
+	s.stats.mu.Lock()
+	defer s.stats.mu.Unlock()
+
+	go publish(s.stats) // want "Value containing the held mutex s.stats.mu is copied into a goroutine"
+}
+
+func (s *server) SendStats() {
+	s.stats.mu.Lock()
+	defer s.stats.mu.Unlock()
+
+	s.out <- s.stats // want "Value containing the held mutex s.stats.mu is sent on a channel"
+}
+
+// The copied value doesn't contain the held mutex
+func (s *server) PublishUnrelated() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	go publish(s.stats)
+}
+
+// Pointers share the mutex
+func (s *server) FlushShared() {
+	s.ref.mu.Lock()
+	defer s.ref.mu.Unlock()
+
+	go s.ref.flush()
+}
+
+// The value is copied after unlocking
+func (s *server) PublishUnlocked() {
+	s.stats.mu.Lock()
+	s.stats.hits++
+	s.stats.mu.Unlock()
+
+	go publish(s.stats)
+}
+
+// Goroutine bodies run with a different lock state
+func (s *server) PublishLater() {
+	s.stats.mu.Lock()
+	defer s.stats.mu.Unlock()
+
+	go func() {
+		s.stats.mu.Lock()
+		defer s.stats.mu.Unlock()
+		_ = s.stats.hits
+	}()
+}
@@ -0,0 +1,83 @@
+package negative
+
+import (
+	"sync"
+	"time"
+)
+
+type queue struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	items []int
+
+	once   sync.Once
+	config map[string]string
+
+	results chan int
+	timer   *time.Timer
+}
+
+func newQueue() *queue {
+	q := &queue{results: make(chan int, 1)}
+	q.cond = sync.NewCond(&q.mu)
+	q.timer = time.AfterFunc(time.Second, q.expire)
+	return q
+}
+
+func (q *queue) expire() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = nil
+}
+
+// Cond.Wait is called with the lock it was created with held
+func (q *queue) Pop() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 {
+		q.cond.Wait()
+	}
+	item := q.items[0]
+	q.items = q.items[1:]
+	return item
+}
+
+// Lazy initialization with sync.Once instead of double-checked locking
+func (q *queue) Config() map[string]string {
+	q.once.Do(func() {
+		q.config = map[string]string{}
+	})
+	return q.config
+}
+
+// A select with a default case doesn't block under lock
+func (q *queue) Poll() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	select {
+	case v := <-q.results:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func (q *queue) produce() {
+	q.mu.Lock()
+	n := len(q.items)
+	q.mu.Unlock()
+	q.results <- n
+}
+
+// Waiting for the goroutine happens without holding the lock
+func (q *queue) Size() int {
+	go q.produce()
+	return <-q.results
+}
+
+// The timer is stopped without waiting for its callback
+func (q *queue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.timer.Stop()
+}
@@ -0,0 +1,42 @@
+package negative
+
+import "sync"
+
+type registry struct {
+	mu    sync.Mutex
+	items map[string]*registry
+}
+
+// The new value hasn't escaped yet, so nobody else can hold its lock
+func newRegistry() *registry {
+	r := &registry{}
+	r.mu.Lock()
+	r.items = make(map[string]*registry)
+	r.mu.Unlock()
+	return r
+}
+
+// Callers holding the lock of another registry can create new ones
+func (r *registry) Child(name string) *registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	child := newRegistry()
+	r.items[name] = child
+	return child
+}
+
+// Setting fields doesn't make the value escape
+func newNamedRegistry(name string, parent *registry) *registry {
+	var r registry
+	r.items = map[string]*registry{name: parent}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[""] = parent
+	return &r
+}
+
+func (r *registry) Named(name string) *registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return newNamedRegistry(name, r)
+}
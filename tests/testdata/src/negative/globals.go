@@ -0,0 +1,39 @@
+package negative
+
+import "sync"
+
+var (
+	primary = struct {
+		mu    sync.Mutex
+		count int
+	}{}
+	secondary = struct {
+		mu    sync.Mutex
+		count int
+	}{}
+)
+
+func incrSecondary() {
+	secondary.mu.Lock()
+	defer secondary.mu.Unlock()
+	secondary.count++
+}
+
+// Package-level variables of the same anonymous type hold different mutexes
+func IncrBoth() {
+	primary.mu.Lock()
+	defer primary.mu.Unlock()
+	primary.count++
+	incrSecondary()
+}
+
+// Reading a field of another variable of the same type isn't double-checked locking
+func InitPrimary() {
+	if secondary.count == 0 {
+		primary.mu.Lock()
+		if primary.count == 0 {
+			primary.count = 1
+		}
+		primary.mu.Unlock()
+	}
+}
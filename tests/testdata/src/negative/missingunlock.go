@@ -0,0 +1,69 @@
+package negative
+
+import (
+	"errors"
+	"sync"
+)
+
+type store struct {
+	mu    sync.RWMutex
+	items map[string]int
+}
+
+func (s *store) Get(key string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.items[key]
+	if !ok {
+		return 0, errors.New("not found")
+	}
+	return v, nil
+}
+
+func (s *store) Put(key string, v int) error {
+	s.mu.Lock()
+	if _, ok := s.items[key]; ok {
+		s.mu.Unlock()
+		return errors.New("exists")
+	}
+	s.items[key] = v
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *store) Delete(key string) bool {
+	s.mu.Lock()
+	switch _, ok := s.items[key]; {
+	case ok:
+		delete(s.items, key)
+		s.mu.Unlock()
+		return true
+	default:
+		s.mu.Unlock()
+		return false
+	}
+}
+
+func (s *store) Drop(key string) bool {
+	s.mu.Lock()
+	if _, ok := s.items[key]; !ok {
+		goto done
+	}
+	delete(s.items, key)
+
+done:
+	s.mu.Unlock()
+	return true
+}
+
+// Releasing and re-acquiring a lock with a deferred unlock is fine
+func (s *store) Refresh(load func() map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mu.Unlock()
+	items := load()
+	s.mu.Lock()
+
+	s.items = items
+}
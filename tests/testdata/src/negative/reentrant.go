@@ -0,0 +1,60 @@
+package negative
+
+import "sync"
+
+type counter struct {
+	mu    sync.Mutex
+	value int
+}
+
+func (c *counter) get() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// The lock is released before calling the locking helper
+func (c *counter) Double() int {
+	c.mu.Lock()
+	c.value *= 2
+	c.mu.Unlock()
+	return c.get()
+}
+
+// Locking another instance is fine
+func (c *counter) Merge(other *counter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value += other.get()
+}
+
+// Goroutines run after the lock is released
+func (c *counter) Report(out chan<- int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	go func() {
+		out <- c.get()
+	}()
+}
+
+// Returned func literals run outside of the lock
+func (c *counter) Getter() func() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return func() int { return c.get() }
+}
+
+func (c *counter) add(delta int, lock bool) {
+	if lock {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	c.value += delta
+}
+
+// Conditional locks are not taken when the argument says so
+func (c *counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(-c.value, false)
+}
@@ -0,0 +1,58 @@
+package negative
+
+import (
+	"errors"
+	"sync"
+)
+
+var errCanceled = errors.New("canceled")
+
+type persistConn struct {
+	mu          sync.Mutex
+	numExpected int
+	canceled    bool
+	reused      bool
+	broken      bool
+}
+
+// Releasing the lock to wait and re-acquiring it before the final unlock (as net/http's
+// persistConn.roundTrip does) doesn't make the function a lock wrapper
+func (pc *persistConn) roundTrip(wait func() error) error {
+	pc.mu.Lock()
+	pc.numExpected++
+	if pc.canceled {
+		pc.mu.Unlock()
+		if err := wait(); err != nil {
+			return err
+		}
+		pc.mu.Lock()
+	}
+	pc.numExpected--
+	pc.mu.Unlock()
+	return nil
+}
+
+func (pc *persistConn) shouldRetryRequest(err error) bool {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.reused && !errors.Is(err, errCanceled)
+}
+
+func (pc *persistConn) closeLocked() {
+	pc.broken = true
+}
+
+func (pc *persistConn) close() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.closeLocked()
+}
+
+func (pc *persistConn) RoundTrip(wait func() error) error {
+	err := pc.roundTrip(wait)
+	if err != nil && pc.shouldRetryRequest(err) {
+		return nil
+	}
+	pc.close()
+	return err
+}
@@ -0,0 +1,116 @@
+package redundant
+
+import "sync"
+
+type Cache struct {
+	mu      sync.Mutex
+	statsMu sync.Mutex
+	items   map[string]string
+	hits    int
+	misses  int
+}
+
+func (c *Cache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.items[key]
+	c.statsMu.Lock() // want `Mutex Cache.statsMu is only locked while holding Cache.mu, so it may be redundant(.|\n)*All 2 locks of Cache.statsMu are nested in Cache.mu sections`
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	c.statsMu.Unlock()
+	return v, ok
+}
+
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	c.items = make(map[string]string)
+	c.statsMu.Lock()
+	c.hits, c.misses = 0, 0
+	c.statsMu.Unlock()
+	c.mu.Unlock()
+}
+
+// The stats mutex is also locked on its own, so it's not redundant
+type Pool struct {
+	mu      sync.Mutex
+	statsMu sync.Mutex
+	conns   []int
+	served  int
+}
+
+func (p *Pool) Acquire() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conn := p.conns[0]
+	p.conns = p.conns[1:]
+	p.statsMu.Lock()
+	p.served++
+	p.statsMu.Unlock()
+	return conn
+}
+
+func (p *Pool) Served() int {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	return p.served
+}
+
+// Read locks don't serialize the nested sections
+type Index struct {
+	mu     sync.RWMutex
+	hitsMu sync.Mutex
+	keys   map[string]int
+	hits   int
+}
+
+func (i *Index) Lookup(key string) int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	i.hitsMu.Lock()
+	i.hits++
+	i.hitsMu.Unlock()
+	return i.keys[key]
+}
+
+// The nested mutex is released after the outer one
+type Queue struct {
+	mu     sync.Mutex
+	sendMu sync.Mutex
+	items  []string
+}
+
+func (q *Queue) Send(send func(string)) {
+	q.mu.Lock()
+	item := q.items[0]
+	q.sendMu.Lock()
+	q.items = q.items[1:]
+	q.mu.Unlock()
+	send(item)
+	q.sendMu.Unlock()
+}
+
+// The mutex is used by a condition variable
+type Broker struct {
+	mu     sync.Mutex
+	waitMu sync.Mutex
+	cond   *sync.Cond
+	ready  bool
+}
+
+func NewBroker() *Broker {
+	b := &Broker{}
+	b.cond = sync.NewCond(&b.waitMu)
+	return b
+}
+
+func (b *Broker) Publish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.waitMu.Lock()
+	b.ready = true
+	b.waitMu.Unlock()
+	b.cond.Broadcast()
+}
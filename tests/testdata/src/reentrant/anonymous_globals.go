@@ -0,0 +1,35 @@
+package reentrant
+
+import "sync"
+
+var stats = struct {
+	mu   sync.Mutex
+	hits int
+}{}
+
+func recordHit() {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	stats.hits++
+}
+
+func RecordHits(n int) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	for i := 0; i < n; i++ {
+		recordHit() // want "Mutex lock is acquired on this line"
+	}
+}
+
+type tracker struct{}
+
+func (t *tracker) track() {
+	recordHit()
+}
+
+// The receiver of the call doesn't matter for package-level mutexes
+func (t *tracker) Flush() {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	t.track() // want "Mutex lock is acquired on this line"
+}
@@ -0,0 +1,157 @@
+package reentrant
+
+import (
+	"strconv"
+	"sync"
+)
+
+type conn struct {
+	mu    sync.Mutex
+	token string
+	peer  *conn
+}
+
+func (c *conn) getLocked() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+func (c *conn) self() *conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c
+}
+
+func (c *conn) process(string) {}
+
+func quoted(v string) string { return v }
+
+func (c *conn) Argument() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.process(c.getLocked()) // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) NestedArgument() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.process(quoted(quoted(c.getLocked()))) // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) VariadicArgument() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.token, c.getLocked()) // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) Chained() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.self().token // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) ConvertedArgument() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, _ := strconv.Atoi(c.getLocked()) // want "Mutex lock is acquired on this line"
+	return n
+}
+
+// The helpers lock the mutex only within an argument expression
+func (c *conn) forward() {
+	c.process(c.getLocked())
+}
+
+func (c *conn) forwardNested() {
+	c.process(quoted(c.getLocked()))
+}
+
+func (c *conn) forwardChained() string {
+	return c.self().token
+}
+
+func (c *conn) forwardInBranch(ok bool) {
+	if ok {
+		c.process(c.getLocked())
+	}
+}
+
+func (c *conn) forwardInCondition() bool {
+	if c.getLocked() != "" {
+		return true
+	}
+	return false
+}
+
+func (c *conn) forwardInLiteral() []string {
+	return []string{strconv.Quote(c.getLocked())}
+}
+
+func (c *conn) Forward() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forward() // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) ForwardNested() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forwardNested() // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) ForwardChained() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.forwardChained() // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) ForwardInBranch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forwardInBranch(true) // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) ForwardInCondition() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.forwardInCondition() // want "Mutex lock is acquired on this line"
+}
+
+func (c *conn) ForwardInLiteral() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.forwardInLiteral() // want "Mutex lock is acquired on this line"
+}
+
+// Arguments evaluated by the caller of a goroutine, the goroutine itself runs without the lock
+func (c *conn) forwardAsync() {
+	go c.process(c.getLocked())
+	go func() {
+		c.process(c.getLocked())
+	}()
+}
+
+func (c *conn) ForwardAsync() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forwardAsync() // want "Mutex lock is acquired on this line"
+}
+
+// Func literals passed around may run later
+func (c *conn) deferred() func() string {
+	return func() string { return c.getLocked() }
+}
+
+func (c *conn) Deferred() func() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deferred()
+}
+
+// Another connection's mutex is a different lock
+func (c *conn) Peer() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.process(c.peer.token)
+}
@@ -0,0 +1,56 @@
+package reentrant
+
+import "sync"
+
+type roster struct {
+	mu    sync.Mutex
+	names []string
+}
+
+// eachName calls fn synchronously for every name
+func eachName(names []string, fn func(string)) {
+	for _, name := range names {
+		fn(name)
+	}
+}
+
+// later calls fn in a goroutine
+func later(name string, fn func(string)) {
+	go fn(name)
+}
+
+func (r *roster) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+}
+
+// The method value is called by eachName while the lock is held
+func (r *roster) Copy(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	eachName(names, r.add) // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*callback_params.go:30: But the same lock`
+}
+
+// So is the func literal
+func (r *roster) Extend(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	eachName(names, func(name string) { // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*callback_params.go:37: But the same lock`
+		r.add(name)
+	})
+}
+
+// The callback runs asynchronously
+func (r *roster) Schedule(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	later(name, r.add)
+}
+
+// The callback doesn't acquire the lock
+func (r *roster) Print(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	eachName(names, func(name string) { println(name) })
+}
@@ -0,0 +1,156 @@
+package reentrant
+
+import "sync"
+
+type ledger struct {
+	mu      sync.RWMutex
+	balance int
+}
+
+type ledgerState struct {
+	Balance int
+	Valid   bool
+}
+
+func (l *ledger) current() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.balance
+}
+
+func (l *ledger) valid() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.balance >= 0
+}
+
+func (l *ledger) Pair() (int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.current(), nil // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) State() ledgerState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return ledgerState{
+		Balance: l.balance,
+		Valid:   l.valid(), // want "Mutex lock is acquired on this line"
+	}
+}
+
+func (l *ledger) StatePtr() *ledgerState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return &ledgerState{Valid: l.valid()} // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Map() map[string]int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return map[string]int{"balance": l.current()} // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Keys() map[int]bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return map[int]bool{l.current(): true} // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Slice() []int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return []int{l.balance, l.current()} // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Indexed(values []int) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return values[l.current()] // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Sliced(values []int) []int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return values[:l.current()] // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Binary() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.balance + l.current() // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Negated() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return !l.valid() // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Converted() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return int64(l.current()) // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Asserted(v any) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return v.(func() int)() + l.current() // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Nested() [][]bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return [][]bool{{true}, {l.valid()}} // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Called() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return func() int { return l.current() }() // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Parenthesized() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return (l.current()) // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Channel(ch chan int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	ch <- l.current() // want "Mutex lock is acquired on this line"
+}
+
+func (l *ledger) Incremented(counts map[int]int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	counts[l.current()]++ // want "Mutex lock is acquired on this line"
+}
+
+// Returned func literals run after the lock is released
+func (l *ledger) Getter() func() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return func() int { return l.current() }
+}
+
+func (l *ledger) process(int) {}
+
+func (l *ledger) Classify() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	switch l.balance {
+	case l.current(): // want "Mutex lock is acquired on this line"
+		return "same"
+	}
+	return "other"
+}
+
+// Arguments of a go statement are evaluated by the caller
+func (l *ledger) Spawn() {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	go l.process(l.current()) // want "Mutex lock is acquired on this line"
+}
@@ -0,0 +1,104 @@
+package reentrant
+
+import "sync"
+
+type lockOptions struct {
+	NoLock  bool
+	Retries int
+}
+
+type inventory struct {
+	mu    sync.Mutex
+	items map[string]int
+}
+
+// Conditional lock tests - lock is guarded by a field of an options struct
+
+func (v *inventory) put(key string, opts lockOptions) {
+	if !opts.NoLock {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+	}
+	v.items[key]++
+}
+
+func (v *inventory) PutLocked(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.put(key, lockOptions{NoLock: true}) // Should NOT be flagged - the options disable the lock
+}
+
+func (v *inventory) PutPositional(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.put(key, lockOptions{true, 3}) // Should NOT be flagged - the options disable the lock
+}
+
+func (v *inventory) PutWithLock(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.put(key, lockOptions{NoLock: false}) // want "Mutex lock is acquired on this line"
+}
+
+func (v *inventory) PutDefaults(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.put(key, lockOptions{Retries: 3}) // want "Mutex lock is acquired on this line"
+}
+
+// The value isn't known statically
+func (v *inventory) PutWith(key string, opts lockOptions) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.put(key, opts) // want "Mutex lock is acquired on this line"
+}
+
+func (v *inventory) drop(key string, opts *lockOptions) {
+	if !opts.NoLock {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+	}
+	delete(v.items, key)
+}
+
+func (v *inventory) DropLocked(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.drop(key, &lockOptions{NoLock: true}) // Should NOT be flagged - the options disable the lock
+}
+
+// Propagated conditional lock tests - options passed through intermediate functions
+
+func (v *inventory) putAll(keys []string, opts lockOptions) {
+	for _, key := range keys {
+		v.put(key, opts)
+	}
+}
+
+func (v *inventory) PutAllLocked(keys []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.putAll(keys, lockOptions{NoLock: true}) // Should NOT be flagged - the options propagate
+}
+
+func (v *inventory) PutAllWithLock(keys []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.putAll(keys, lockOptions{}) // want "Mutex lock is acquired on this line"
+}
+
+func (v *inventory) putUnless(key string, locked bool) {
+	v.put(key, lockOptions{NoLock: locked})
+}
+
+func (v *inventory) PutUnlessLocked(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.putUnless(key, true) // Should NOT be flagged - the bool param sets the option
+}
+
+func (v *inventory) PutUnlessWithLock(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.putUnless(key, false) // want "Mutex lock is acquired on this line"
+}
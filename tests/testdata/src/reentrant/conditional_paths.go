@@ -0,0 +1,83 @@
+package reentrant
+
+import "sync"
+
+type shard struct {
+	mu    sync.Mutex
+	items map[string]int
+}
+
+func (s *shard) lookup(key string, lock bool) int {
+	if lock {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return s.items[key]
+}
+
+func (s *shard) store(key string, value int, opts storeOptions) {
+	if !opts.Locked {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	s.items[key] = value
+}
+
+type storeOptions struct {
+	Locked bool
+}
+
+// The branch condition implies the argument is false
+func (s *shard) Get(key string, locked bool) int {
+	if !locked {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.lookup(key, locked)
+	}
+	return s.lookup(key, false)
+}
+
+// The guard clause implies the argument is true
+func (s *shard) Put(key string, value int, locked bool) {
+	if !locked {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(key, value, storeOptions{Locked: locked})
+}
+
+func (s *shard) Swap(key string, value int, fresh, locked bool) int {
+	if fresh && !locked {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		prev := s.lookup(key, !fresh)
+		s.items[key] = value
+		return prev
+	}
+	return 0
+}
+
+// Within the else branch, the condition doesn't hold
+func (s *shard) Update(key string, value int, unlocked bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if unlocked {
+		s.items[key] = value
+	} else {
+		s.lookup(key, unlocked)
+		s.lookup(key, !unlocked) // want "Mutex lock is acquired on this line"
+	}
+	s.lookup(key, unlocked) // want "Mutex lock is acquired on this line"
+}
+
+// Reassigned variables aren't resolved
+func (s *shard) Touch(key string, lock bool) {
+	if !lock {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		lock = s.items[key] > 0
+		s.lookup(key, lock) // want "Mutex lock is acquired on this line"
+	}
+}
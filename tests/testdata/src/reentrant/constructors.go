@@ -0,0 +1,42 @@
+package reentrant
+
+import "sync"
+
+type session struct {
+	mu    sync.Mutex
+	peers []*session
+}
+
+var sessions []*session
+
+// The new session is registered before being locked, so another goroutine may hold its lock
+func newSession() *session {
+	s := &session{}
+	sessions = append(sessions, s)
+	s.mu.Lock()
+	s.peers = sessions
+	s.mu.Unlock()
+	return s
+}
+
+func (s *session) Reconnect() *session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return newSession() // want "Mutex lock is acquired on this line"
+}
+
+// Locking a new session is fine, but calling the locking helper on it is not
+func newSessionWithPeer(peer *session) *session {
+	s := new(session)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peers = append(s.peers, peer)
+	s.addPeer(peer) // want "Mutex lock is acquired on this line"
+	return s
+}
+
+func (s *session) addPeer(peer *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peers = append(s.peers, peer)
+}
@@ -0,0 +1,54 @@
+package reentrant
+
+import "sync"
+
+type journal struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (j *journal) snapshot() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]string(nil), j.entries...)
+}
+
+func (j *journal) log(entries []string) {}
+
+func (j *journal) flush() {
+	j.mu.Lock()
+	j.entries = nil
+	j.mu.Unlock()
+}
+
+// Arguments of a deferred call are evaluated at the defer statement
+func (j *journal) Append(entry string) {
+	j.mu.Lock()
+	defer j.log(j.snapshot()) // want "Mutex lock is acquired on this line"
+	j.entries = append(j.entries, entry)
+	j.mu.Unlock()
+}
+
+// Deferred calls run before the deferred unlocks registered earlier
+func (j *journal) Truncate() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	defer j.flush() // want "Mutex lock is acquired on this line"
+	j.entries = j.entries[:0]
+}
+
+// The lock is released by the time the deferred call runs
+func (j *journal) Rotate() {
+	j.mu.Lock()
+	defer j.flush()
+	j.entries = j.entries[:0]
+	j.mu.Unlock()
+}
+
+// Deferred unlocks registered later run first
+func (j *journal) Reset() {
+	defer j.flush()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = nil
+}
@@ -0,0 +1,57 @@
+package reentrant
+
+import "sync"
+
+type core struct {
+	mu      sync.Mutex
+	version int
+}
+
+func (c *core) refresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+}
+
+type server struct {
+	*core
+	name string
+}
+
+// The promoted mutex of the embedded struct is the one its promoted method locks
+func (s *server) Reload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh() // want "Mutex lock is acquired on this line"
+}
+
+func (s *server) ReloadCore() {
+	s.core.mu.Lock()
+	defer s.core.mu.Unlock()
+	s.refresh() // want "Mutex lock is acquired on this line"
+}
+
+type cluster struct {
+	server
+	mu sync.Mutex
+}
+
+// Embedding chains: the method is promoted through server
+func (l *cluster) Reload() {
+	l.server.mu.Lock()
+	defer l.server.mu.Unlock()
+	l.refresh() // want "Mutex lock is acquired on this line"
+}
+
+func (l *cluster) ReloadCore() {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.refresh() // want "Mutex lock is acquired on this line"
+}
+
+// The own mutex shadows the promoted one
+func (l *cluster) Restart() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refresh()
+}
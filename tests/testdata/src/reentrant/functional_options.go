@@ -0,0 +1,103 @@
+package reentrant
+
+import "sync"
+
+type writeOptions struct {
+	noLock bool
+	sync   bool
+}
+
+type writeOption func(*writeOptions)
+
+func WithoutLocking() writeOption {
+	return func(o *writeOptions) { o.noLock = true }
+}
+
+func WithLocking() writeOption {
+	return func(o *writeOptions) {
+		o.noLock = false
+	}
+}
+
+func WithSync() writeOption {
+	return func(o *writeOptions) { o.sync = true }
+}
+
+func WithLockingIf(lock bool) writeOption {
+	return func(o *writeOptions) { o.noLock = !lock }
+}
+
+type store struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// Conditional lock tests - lock is guarded by functional options
+
+func (s *store) write(key, value string, opts ...writeOption) {
+	o := writeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.noLock {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	s.data[key] = value
+}
+
+func (s *store) WriteLocked(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.write(key, value, WithSync(), WithoutLocking()) // Should NOT be flagged - the option disables the lock
+}
+
+func (s *store) WriteDefaults(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.write(key, value) // want "Mutex lock is acquired on this line"
+}
+
+func (s *store) WriteWithLock(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.write(key, value, WithoutLocking(), WithLocking()) // want "Mutex lock is acquired on this line"
+}
+
+// The values set by the options aren't known statically
+func (s *store) WriteIf(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.write(key, value, WithoutLocking(), WithLockingIf(false)) // want "Mutex lock is acquired on this line"
+}
+
+func (s *store) WriteWith(key, value string, opts ...writeOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.write(key, value, opts...) // want "Mutex lock is acquired on this line"
+}
+
+// The options may be applied to the defaults disabling the lock
+func (s *store) erase(key string, opts ...writeOption) {
+	var o = writeOptions{noLock: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.noLock {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	delete(s.data, key)
+}
+
+func (s *store) Erase(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.erase(key) // Should NOT be flagged - the lock is disabled by default
+}
+
+func (s *store) EraseWithLock(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.erase(key, WithLocking()) // want "Mutex lock is acquired on this line"
+}
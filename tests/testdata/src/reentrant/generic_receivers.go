@@ -0,0 +1,64 @@
+package reentrant
+
+import "sync"
+
+type set[T comparable] struct {
+	mu    sync.Mutex
+	items map[T]bool
+}
+
+func (s *set[T]) has(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items[v]
+}
+
+func (s *set[T]) Add(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.has(v) { // want "Mutex lock is acquired on this line"
+		s.items[v] = true
+	}
+}
+
+type pair[K comparable, V any] struct {
+	mu   sync.Mutex
+	key  K
+	val  V
+	seen set[K]
+}
+
+func (p *pair[K, V]) get() V {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.val
+}
+
+func (p *pair[K, V]) Swap(val V) V {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	old := p.get() // want "Mutex lock is acquired on this line"
+	p.val = val
+	return old
+}
+
+// Methods of different generic types with the same name are distinct functions
+type stack[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (s *stack[T]) has(T) bool {
+	return len(s.items) > 0
+}
+
+func (s *stack[T]) Push(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.has(v) {
+		s.items = append(s.items, v)
+	}
+}
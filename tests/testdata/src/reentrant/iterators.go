@@ -0,0 +1,100 @@
+package reentrant
+
+import (
+	"iter"
+	"sync"
+)
+
+type registry struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *registry) Each(yield func(string) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range r.names {
+		if !yield(name) {
+			return
+		}
+	}
+}
+
+func (r *registry) Has(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n := range r.Each { // want "Mutex lock is acquired on this line"
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *registry) collect() []string {
+	var names []string
+	for name := range r.Each {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (r *registry) Snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.collect() // want "Mutex lock is acquired on this line"
+}
+
+// The loop body runs as the yield callback under the caller's locks
+func (r *registry) Merge(seq iter.Seq[string]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name := range seq {
+		if r.Has(name) { // want "Mutex lock is acquired on this line"
+			continue
+		}
+		r.names = append(r.names, name)
+	}
+}
+
+func (r *registry) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, name := range r.names {
+			if !yield(name) {
+				return
+			}
+		}
+	}
+}
+
+func (r *registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for range r.All() { // want `(?s)Mutex lock is acquired on this line.*Lock is acquired in registry:All`
+		n++
+	}
+	return n
+}
+
+func (r *registry) first() string {
+	for name := range r.All() {
+		return name
+	}
+	return ""
+}
+
+func (r *registry) First() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.first() // want "Mutex lock is acquired on this line"
+}
+
+// Calling the iterator constructor doesn't acquire the lock
+func (r *registry) Names() iter.Seq[string] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.All()
+}
@@ -0,0 +1,61 @@
+package reentrant
+
+import "sync"
+
+func With[T any](mu *sync.Mutex, fn func() T) T {
+	mu.Lock()
+	defer mu.Unlock()
+	return fn()
+}
+
+// The callback is called after releasing the lock
+func after[T any](mu *sync.Mutex, fn func() T) T {
+	mu.Lock()
+	mu.Unlock()
+	return fn()
+}
+
+type tally struct {
+	mu      sync.Mutex
+	aux     sync.Mutex
+	entries []string
+}
+
+func (l *tally) Len() int {
+	return With(&l.mu, func() int { return len(l.entries) })
+}
+
+func (l *tally) Last() string {
+	return With(&l.mu, func() string {
+		l.mu.Lock() // want "Mutex lock is acquired on this line"
+		defer l.mu.Unlock()
+		return l.entries[len(l.entries)-1]
+	})
+}
+
+func (l *tally) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+func (l *tally) Count() int {
+	return With[int](&l.mu, l.count) // want "Mutex lock is acquired on this line"
+}
+
+func (l *tally) Reset() {
+	withLock(&l.mu, func() {
+		if l.count() > 0 { // want "Mutex lock is acquired on this line"
+			l.entries = nil
+		}
+	})
+}
+
+// Another mutex is passed
+func (l *tally) CountAux() int {
+	return With(&l.aux, l.count)
+}
+
+func (l *tally) CountAfter() int {
+	return after(&l.mu, l.count)
+}
@@ -0,0 +1,69 @@
+package reentrant
+
+import "sync"
+
+type outbox struct {
+	mu      sync.Mutex
+	aux     sync.Mutex
+	entries []string
+}
+
+func withLock(mu *sync.Mutex, fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	fn()
+}
+
+func withLocker(l sync.Locker, fn func()) {
+	l.Lock()
+	defer l.Unlock()
+	fn()
+}
+
+// The mutex is passed further before being locked
+func guarded(mu *sync.Mutex, fn func()) {
+	withLock(mu, fn)
+}
+
+func (l *outbox) Append(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	withLock(&l.mu, func() { // want "Mutex lock is acquired on this line"
+		l.entries = append(l.entries, entry)
+	})
+}
+
+func (l *outbox) AppendLocker(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	withLocker(&l.mu, func() { // want "Mutex lock is acquired on this line"
+		l.entries = append(l.entries, entry)
+	})
+}
+
+func (l *outbox) AppendGuarded(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	guarded(&l.mu, func() { // want "Mutex lock is acquired on this line"
+		l.entries = append(l.entries, entry)
+	})
+}
+
+// Another mutex is passed
+func (l *outbox) AppendAux(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	withLock(&l.aux, func() {
+		l.entries = append(l.entries, entry)
+	})
+}
+
+// The mutex is passed after unlocking
+func (l *outbox) AppendUnlocked(entry string) {
+	l.mu.Lock()
+	l.entries = append(l.entries, "")
+	l.mu.Unlock()
+	withLock(&l.mu, func() {
+		l.entries = append(l.entries, entry)
+	})
+}
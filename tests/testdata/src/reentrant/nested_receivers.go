@@ -0,0 +1,71 @@
+package reentrant
+
+import "sync"
+
+type inner struct {
+	m     sync.Mutex
+	count int
+}
+
+func (n *inner) helper() {
+	n.m.Lock()
+	defer n.m.Unlock()
+	n.count++
+}
+
+type outer struct {
+	mu     sync.Mutex
+	nested inner
+	other  inner
+}
+
+func (b *outer) Increment() {
+	b.nested.m.Lock()
+	defer b.nested.m.Unlock()
+	b.nested.helper() // want "Mutex lock is acquired on this line"
+}
+
+// The helper locks the mutex of another nested struct
+func (b *outer) IncrementOther() {
+	b.nested.m.Lock()
+	defer b.nested.m.Unlock()
+	b.other.helper()
+}
+
+// Nested structs don't lock the mutex of the outer one
+func (b *outer) IncrementAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nested.helper()
+	b.other.helper()
+}
+
+type cell struct {
+	mu    sync.Mutex
+	value int
+}
+
+// The receiver is named as the outer one's, but the mutex is different
+func (b *cell) touch() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.value++
+}
+
+type grid struct {
+	mu   sync.Mutex
+	cell cell
+}
+
+func (b *grid) Touch() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cell.touch()
+}
+
+// Receiver names of the callee don't have to match
+func (g *grid) Reset() {
+	g.cell.mu.Lock()
+	defer g.cell.mu.Unlock()
+	g.cell.touch() // want "Mutex lock is acquired on this line"
+}
@@ -0,0 +1,42 @@
+package reentrant
+
+import "sync"
+
+var plugins = struct {
+	mu    sync.Mutex
+	names []string
+}{}
+
+type plugin struct {
+	name string
+}
+
+func (p *plugin) register() {
+	plugins.mu.Lock()
+	defer plugins.mu.Unlock()
+	plugins.names = append(plugins.names, p.name)
+}
+
+// Promoted methods are named after the embedded type declaring them
+type cachePlugin struct {
+	plugin
+	size int
+}
+
+func (c *cachePlugin) Init() {
+	plugins.mu.Lock()
+	defer plugins.mu.Unlock()
+
+	c.register() // want "Mutex lock is acquired on this line"
+}
+
+type tracePlugin struct {
+	*cachePlugin
+}
+
+func (t *tracePlugin) Init() {
+	plugins.mu.Lock()
+	defer plugins.mu.Unlock()
+
+	t.register() // want "Mutex lock is acquired on this line"
+}
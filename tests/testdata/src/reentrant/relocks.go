@@ -0,0 +1,88 @@
+package reentrant
+
+import "sync"
+
+type changelog struct {
+	mu      sync.Mutex
+	entries []string
+	dirty   bool
+}
+
+func (c *changelog) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *changelog) flush() {}
+
+// The re-lock in the branch doesn't dominate the call, the first lock does
+func (c *changelog) Append(entry string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dirty {
+		c.mu.Unlock()
+		c.flush()
+		c.mu.Lock()
+	}
+	c.entries = append(c.entries, entry)
+	return c.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*relocks.go:21: But the same lock`
+}
+
+func (c *changelog) Compact() int {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+
+	c.flush()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*relocks.go:40: But the same lock`
+}
+
+// Every branch unlocks, so the re-lock is fine and is the origin of the following call
+func (c *changelog) Rotate() int {
+	c.mu.Lock()
+	if c.dirty {
+		c.mu.Unlock()
+		c.flush()
+	} else {
+		c.mu.Unlock()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*relocks.go:54: But the same lock`
+}
+
+func (c *changelog) Drain(entries []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, entry := range entries {
+		if entry == "" {
+			c.mu.Unlock()
+			c.flush()
+			c.mu.Lock()
+			continue
+		}
+		c.entries = append(c.entries, entry)
+		c.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*relocks.go:60: But the same lock`
+	}
+}
+
+func (c *changelog) Switch(mode int) int {
+	c.mu.Lock()
+	switch mode {
+	case 1:
+		c.mu.Unlock()
+		c.flush()
+		c.mu.Lock()
+	case 2:
+		c.mu.Unlock()
+		c.mu.Lock()
+	}
+	defer c.mu.Unlock()
+	return c.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*relocks.go:76: But the same lock`
+}
@@ -0,0 +1,53 @@
+package reentrant
+
+import "sync"
+
+type catalog struct {
+	mu    sync.RWMutex
+	items map[string]int
+}
+
+func (c *catalog) Get(name string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.items[name]; !ok {
+		c.ensure(name) // want "Mutex write lock is acquired on this line while holding the read lock"
+	}
+	return c.items[name]
+}
+
+func (c *catalog) Refresh(name string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.reload(name) // want `(?s)Mutex write lock is acquired on this line while holding the read lock.*Lock is acquired in catalog:ensure`
+}
+
+// Read locks under the read lock are recursive RLocks
+func (c *catalog) Count() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.size() // want "^Mutex lock is acquired on this line"
+}
+
+// Read locks under the write lock aren't upgrades
+func (c *catalog) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size() // want "^Mutex lock is acquired on this line"
+}
+
+func (c *catalog) reload(name string) {
+	c.ensure(name)
+}
+
+func (c *catalog) ensure(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[name] = len(c.items)
+}
+
+func (c *catalog) size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
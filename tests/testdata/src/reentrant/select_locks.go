@@ -0,0 +1,71 @@
+package reentrant
+
+import (
+	"sync"
+	"time"
+)
+
+type mailbox struct {
+	mu     sync.Mutex
+	queue  []string
+	notify chan struct{}
+}
+
+func (m *mailbox) size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.queue)
+}
+
+// The lock acquired by the default case is held after the select
+func (m *mailbox) Poll() int {
+	select {
+	case <-m.notify:
+		return 0
+	default:
+		m.mu.Lock()
+	}
+	n := m.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*select_locks.go:26: But the same lock`
+	m.mu.Unlock()
+	return n
+}
+
+// Acquired by every case, the first acquisition is reported
+func (m *mailbox) Take(timeout <-chan time.Time) int {
+	select {
+	case <-m.notify:
+		m.mu.Lock()
+	case <-timeout:
+		m.mu.Lock()
+		m.queue = m.queue[:0]
+	}
+	defer m.mu.Unlock()
+	return m.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*select_locks.go:37: But the same lock`
+}
+
+// Released by every case
+func (m *mailbox) Drain() int {
+	m.mu.Lock()
+	select {
+	case <-m.notify:
+		m.mu.Unlock()
+	default:
+		m.queue = nil
+		m.mu.Unlock()
+	}
+	return m.size()
+}
+
+// Break leaves the select, not the function
+func (m *mailbox) Push(item string) {
+	select {
+	case <-m.notify:
+		m.mu.Lock()
+		break
+	default:
+		return
+	}
+	m.queue = append(m.queue, item)
+	m.size() // want `(?s)Mutex lock is acquired on this line.*\n\t[^\n]*select_locks.go:63: But the same lock`
+	m.mu.Unlock()
+}
@@ -0,0 +1,53 @@
+package reentrant
+
+import "sync"
+
+type node struct {
+	mu       sync.Mutex
+	value    int
+	children []*node
+}
+
+func (s *node) bump() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.value++
+}
+
+// The shadowing s is a different node
+func (s *node) MergeFirst() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s := s.children[0]; s != nil {
+		s.mu.Lock()
+		s.value++
+		s.mu.Unlock()
+		s.bump()
+	}
+	s.bump() // want "Mutex lock is acquired on this line"
+}
+
+func (s *node) MergeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, s := range s.children {
+		s.bump()
+	}
+	s.value = 0
+}
+
+// Closures may shadow the receiver, too
+func (s *node) Visit(fn func(*node)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, child := range s.children {
+		func(s *node) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			fn(s)
+		}(child)
+	}
+}
@@ -0,0 +1,27 @@
+package reentrant
+
+import "sync"
+
+type transfer struct {
+	from sync.Mutex
+	to   sync.Mutex
+	sum  int
+}
+
+func (t *transfer) lockBoth() {
+	t.from.Lock()
+	t.to.Lock()
+	t.sum = 0
+	t.to.Unlock()
+	t.from.Unlock()
+}
+
+// The call acquires both held mutexes, which are distinct issues at the same position
+func (t *transfer) Apply() {
+	t.from.Lock()
+	defer t.from.Unlock()
+	t.to.Lock()
+	defer t.to.Unlock()
+
+	t.lockBoth() // want `(?s)Mutex lock is acquired on this line.*acquired here: t.from.Lock` `(?s)Mutex lock is acquired on this line.*acquired here: t.to.Lock`
+}
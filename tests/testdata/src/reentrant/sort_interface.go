@@ -0,0 +1,90 @@
+package reentrant
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+type leaderboard struct {
+	mu     sync.Mutex
+	scores []int
+}
+
+func (b *leaderboard) Len() int { return len(b.scores) }
+
+func (b *leaderboard) Less(i, j int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.scores[i] > b.scores[j]
+}
+
+func (b *leaderboard) Swap(i, j int) { b.scores[i], b.scores[j] = b.scores[j], b.scores[i] }
+
+func (b *leaderboard) Add(score int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.scores = append(b.scores, score)
+	sort.Sort(b) // want "Mutex lock is acquired on this line"
+}
+
+func (b *leaderboard) Ascending() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sort.Stable(sort.Reverse(b)) // want "Mutex lock is acquired on this line"
+}
+
+// The lock is released before sorting
+func (b *leaderboard) Reset(scores []int) {
+	b.mu.Lock()
+	b.scores = scores
+	b.mu.Unlock()
+	sort.Sort(b)
+}
+
+type taskQueue struct {
+	mu    sync.Mutex
+	tasks []int
+}
+
+func (q *taskQueue) Len() int           { return len(q.tasks) }
+func (q *taskQueue) Less(i, j int) bool { return q.tasks[i] < q.tasks[j] }
+func (q *taskQueue) Swap(i, j int)      { q.tasks[i], q.tasks[j] = q.tasks[j], q.tasks[i] }
+
+func (q *taskQueue) Push(x any) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.tasks = append(q.tasks, x.(int))
+}
+
+func (q *taskQueue) Pop() any {
+	n := len(q.tasks)
+	task := q.tasks[n-1]
+	q.tasks = q.tasks[:n-1]
+	return task
+}
+
+type scheduler struct {
+	mu    sync.Mutex
+	queue taskQueue
+}
+
+func (s *scheduler) Schedule(task int) {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	heap.Push(&s.queue, task) // want "Mutex lock is acquired on this line"
+}
+
+// Pop doesn't lock
+func (s *scheduler) Next() int {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	return heap.Pop(&s.queue).(int)
+}
+
+// Other mutexes are fine
+func (s *scheduler) Reschedule() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	heap.Init(&s.queue)
+}
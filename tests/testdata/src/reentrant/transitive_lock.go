@@ -1,4 +1,4 @@
-package tests
+package reentrant
 
 import (
 	"fmt"
@@ -21,7 +21,7 @@ func (s *some) Entry() {
 	s.sm["lalala"] = 2
 	noneStructMethod()
 	s.recursiveRLock() // want "Mutex lock is acquired on this line"
-	s.deepLock()       // want "Mutex lock is acquired on this line"
+	s.deepLock()       // want `(?s)Mutex lock is acquired on this line.*Lock is acquired in some:recursiveRLock`
 }
 
 func (s *some) ShouldNotDetectDeadLock() {
@@ -0,0 +1,80 @@
+package runfunc
+
+import "sync"
+
+type Queue struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (q *Queue) Push(item string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = append(q.items, item)
+	q.Len() // want "Mutex lock is acquired on this line"
+}
+
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+type Cache struct {
+	mu    sync.Mutex
+	queue *Queue
+	data  map[string]string
+}
+
+func (c *Cache) Get(key string) string {
+	c.mu.Lock()
+	if v, ok := c.data[key]; ok {
+		return v // want "Mutex lock must be released before this line"
+	}
+	c.mu.Unlock()
+	return ""
+}
+
+// Callees outside of the matching functions are still followed
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clear() // want "Mutex lock is acquired on this line"
+}
+
+func (c *Cache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = nil
+}
+
+type Journal struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+// Not matching the pattern - should NOT be flagged
+func (j *Journal) Append(entry string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, entry)
+	j.Size()
+}
+
+func (j *Journal) Size() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.entries)
+}
+
+// Not matching the pattern - should NOT be flagged
+func flush(j *Journal) {
+	j.mu.Lock()
+	if len(j.entries) == 0 {
+		return
+	}
+	j.mu.Unlock()
+}
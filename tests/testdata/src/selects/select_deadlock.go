@@ -0,0 +1,115 @@
+package selects
+
+import (
+	"sync"
+	"time"
+)
+
+type collector struct {
+	mu      sync.Mutex
+	results chan int
+	errs    chan error
+	done    chan struct{}
+	total   int
+}
+
+func (c *collector) Collect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	go c.produce()
+	go func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.errs <- nil
+	}()
+
+	select { // want `(?s)Select waits under lock for channels fed only by goroutines that need the same lock.*Goroutine sending to c.results needs the lock: go c.produce\(\)`
+	case v := <-c.results:
+		c.total += v
+	case <-c.errs:
+	}
+}
+
+func (c *collector) produce() {
+	c.mu.Lock()
+	n := c.total
+	c.mu.Unlock()
+
+	c.results <- n
+}
+
+func LocalChannel() {
+	var mu sync.Mutex
+	ch := make(chan int)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+		ch <- 1
+	}()
+
+	select { // want "Select waits under lock"
+	case <-ch:
+	}
+}
+
+// A default case makes the select non-blocking
+func (c *collector) Poll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	go c.produce()
+
+	select {
+	case v := <-c.results:
+		c.total += v
+	default:
+	}
+}
+
+// A timeout case doesn't depend on the goroutines
+func (c *collector) Wait() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	go c.produce()
+
+	select {
+	case v := <-c.results:
+		c.total += v
+	case <-time.After(time.Second):
+	}
+}
+
+// The channel is also fed by a goroutine which doesn't need the lock
+func (c *collector) Finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	go func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		close(c.done)
+	}()
+	go func() { c.done <- struct{}{} }()
+
+	select {
+	case <-c.done:
+	}
+}
+
+// The lock is released before waiting
+func (c *collector) Release() {
+	c.mu.Lock()
+	go c.produce()
+	c.mu.Unlock()
+
+	select {
+	case v := <-c.results:
+		c.total = v
+	}
+}
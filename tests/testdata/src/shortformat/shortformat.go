@@ -0,0 +1,40 @@
+package shortformat
+
+import "sync"
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.mu.Lock() // want `^Mutex lock is acquired while already held \(acquired at .*shortformat.go:11\) \[MU001\]$`
+	c.n++
+}
+
+func (c *counter) Add(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reset() // want `^Mutex lock is acquired while already held \(acquired at .*shortformat.go:19\), locked in counter:reset at .*shortformat.go:27 \[MU001\]$`
+	c.n += n
+}
+
+func (c *counter) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.n = 0
+}
+
+func (c *counter) Get() int {
+	c.mu.Lock()
+	if c.n < 0 {
+		return 0 // want `^Mutex lock must be released before this line \(acquired at .*shortformat.go:34\) \[MU002\]$`
+	}
+	c.mu.Unlock()
+	return c.n
+}
@@ -0,0 +1,106 @@
+package slowpaths
+
+import (
+	"strings"
+	"sync"
+)
+
+type Entry struct {
+	name string
+}
+
+type Registry struct {
+	mu    sync.Mutex
+	cache sync.Map
+	items map[string]*Entry
+}
+
+// The cache is checked without the lock first
+func (r *Registry) lookup(name string) *Entry {
+	if e, ok := r.cache.Load(name); ok {
+		return e.(*Entry)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.items[name]; ok {
+		return e
+	}
+	e := &Entry{name: name}
+	r.items[name] = e
+	r.cache.Store(name, e)
+	return e
+}
+
+func (r *Registry) Register(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.lookup(name) // want `\n\tlookup acquires the lock only if the check made without it fails[^\n]*\n\tMove the locked part of lookup to lookupLocked and call it instead\n$`
+	}
+}
+
+// The locked part uses the name normalized before the lock
+func (r *Registry) resolve(alias string) *Entry {
+	name := strings.ToLower(alias)
+	if e, ok := r.cache.Load(name); ok {
+		return e.(*Entry)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.items[name]; ok {
+		return e
+	}
+	return nil
+}
+
+func (r *Registry) Resolve(aliases ...string) []*Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var entries []*Entry
+	for _, alias := range aliases {
+		entries = append(entries, r.resolve(alias)) // want `\n\tresolve acquires the lock only if the check made without it fails[^\n]*\n$`
+	}
+	return entries
+}
+
+type Settings struct {
+	values map[string]string
+}
+
+type Config struct {
+	mu       sync.Mutex
+	settings *Settings
+	path     string
+}
+
+func (c *Config) load() *Settings {
+	if c.settings == nil { // want "Guarded field Config.settings is read without holding Config.mu"
+		c.mu.Lock()
+		if c.settings == nil {
+			c.settings = &Settings{values: map[string]string{}}
+		}
+		c.mu.Unlock()
+	}
+	return c.settings
+}
+
+func (c *Config) Reload(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.path = path
+	c.settings = nil
+	c.load() // want `\n\tload acquires the lock only if the check made without it fails[^\n]*\n$`
+}
+
+// No check is made without the lock
+func (r *Registry) get(name string) *Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.items[name]
+}
+
+func (r *Registry) Get(names ...string) *Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.get(names[0]) // want `^Mutex lock is acquired on this line[^\n]*\n[^\n]*\n[^\n]*\n$`
+}
@@ -0,0 +1,94 @@
+package starvation
+
+import "sync"
+
+//mulint:summary blocks
+func fetch(key string) int
+
+type registry struct {
+	mu      sync.RWMutex
+	plain   sync.Mutex
+	entries map[string]int
+}
+
+func (r *registry) Total() int {
+	r.mu.RLock() // want "Read lock is held over a loop, which may starve writers"
+	defer r.mu.RUnlock()
+
+	total := 0
+	for _, v := range r.entries {
+		total += v
+	}
+	return total
+}
+
+func (r *registry) Lookup(key string) int { // want Lookup:`mayBlock\(starvation\.fetch\)`
+	r.mu.RLock() // want "Read lock is held over blocking call fetch, which may starve writers"
+	defer r.mu.RUnlock()
+
+	if _, ok := r.entries[key]; !ok {
+		return r.load(key) // want "Blocking call registry:load while holding lock"
+	}
+	return r.entries[key]
+}
+
+func (r *registry) load(key string) int { // want load:`mayBlock\(starvation\.fetch\)`
+	v := fetch(key)
+	return v
+}
+
+// The read lock is released before the loop
+func (r *registry) Keys() []string {
+	r.mu.RLock()
+	n := len(r.entries)
+	r.mu.RUnlock()
+
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, "")
+	}
+	return keys
+}
+
+// The read lock is acquired within each iteration
+func (r *registry) Sample(keys []string) int {
+	sum := 0
+	for _, key := range keys {
+		r.mu.RLock()
+		sum += r.entries[key]
+		r.mu.RUnlock()
+	}
+	return sum
+}
+
+// Write locks exclude the readers anyway
+func (r *registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.entries {
+		delete(r.entries, key)
+	}
+}
+
+func (r *registry) Count() int {
+	r.plain.Lock()
+	defer r.plain.Unlock()
+
+	count := 0
+	for range r.entries {
+		count++
+	}
+	return count
+}
+
+// Loops in goroutines don't run under the lock
+func (r *registry) Watch(done chan struct{}) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	go func() {
+		for range done {
+		}
+	}()
+}
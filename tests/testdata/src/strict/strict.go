@@ -0,0 +1,73 @@
+package strict
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"example.com/extlib"
+)
+
+//mulint:summary locks-nothing
+func hashKey(key string) int
+
+type listener interface {
+	OnEvent(event string)
+}
+
+type hub struct {
+	mu        sync.Mutex
+	listeners []listener
+	hook      func(string)
+	events    []string
+}
+
+func (h *hub) Publish(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, l := range h.listeners {
+		l.OnEvent(event) // want `Unverifiable call listener:OnEvent \(interface method\) under lock`
+	}
+	h.hook(event)        // want `Unverifiable call h.hook \(function value\) under lock`
+	extlib.Notify(event) // want `Unverifiable call Notify \(external function without a summary\) under lock`
+}
+
+// Standard library, package functions, builtins and conversions are verifiable
+func (h *hub) Record(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events = append(h.events, strings.ToUpper(event))
+	_ = len(h.events) + hashKey(event)
+	_ = []byte(event)
+	h.reset()
+	func() {
+		h.events = h.events[:0]
+	}()
+}
+
+// Neither formatting nor logging blocks: the channel operations internal to the standard library
+// (and the runtime) aren't considered
+func (h *hub) Fail(event string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	log.Printf("failed: %s", event)
+	data, _ := json.Marshal(h.events)
+	h.events = append(h.events, fmt.Sprint(len(data)))
+	return fmt.Errorf("failed: %s", event)
+}
+
+func (h *hub) reset() {
+	h.events = nil
+}
+
+// Calls outside of lock scopes aren't reported
+func (h *hub) Notify(event string) {
+	for _, l := range h.listeners {
+		l.OnEvent(event)
+	}
+}
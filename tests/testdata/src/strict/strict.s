@@ -0,0 +1,2 @@
+// The bodiless declarations in strict.go are analyzed via their //mulint:summary
+// directives; this file only allows the package to compile.
@@ -0,0 +1,120 @@
+package strictprofile
+
+import (
+	"sync"
+	"time"
+)
+
+type queue struct {
+	mu      sync.Mutex
+	items   []string
+	ready   chan string
+	done    chan struct{}
+	pending sync.WaitGroup
+}
+
+func (q *queue) Push(item string) { // want Push:`mayBlock\(strictprofile\.queue:Push\)`
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = append(q.items, item)
+	q.ready <- item // want `Blocking channel send while holding lock`
+}
+
+func (q *queue) Pop() string { // want Pop:`mayBlock\(strictprofile\.queue:Pop\)`
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item := <-q.ready // want `Blocking channel receive while holding lock`
+	return item
+}
+
+func (q *queue) Drain() { // want Drain:`mayBlock\(strictprofile\.queue:Drain\)`
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for item := range q.ready { // want `Blocking range over channel while holding lock`
+		q.items = append(q.items, item)
+	}
+}
+
+func (q *queue) Await() { // want Await:`mayBlock\(strictprofile\.queue:Await\)`
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select { // want `Blocking select without default while holding lock`
+	case item := <-q.ready:
+		q.items = append(q.items, item)
+	case <-q.done:
+	}
+}
+
+// Selects with a default case and goroutines don't block the lock holder
+func (q *queue) TryPush(item string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	go func() {
+		q.ready <- item
+	}()
+
+	select {
+	case q.ready <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *queue) Flush() { // want Flush:`mayBlock\(sync\.WaitGroup:Wait\)`
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	time.Sleep(time.Millisecond) // want `Blocking call Sleep while holding lock`
+	q.pending.Wait()             // want `Blocking call WaitGroup:Wait while holding lock`
+	q.items = nil
+}
+
+//mulint:requires mu
+func (q *queue) compactLocked() {
+	q.items = q.items[:0]
+}
+
+//mulint:requires mu
+func (q *queue) resetLocked() {
+	q.compactLocked()
+}
+
+//mulint:manual-unlock
+func (q *queue) Compact() {
+	q.mu.Lock()
+	q.compactLocked()
+	q.mu.Unlock()
+
+	q.compactLocked() // want `Function queue:compactLocked requires mu to be held, but it's called without it`
+}
+
+func newQueue() *queue {
+	q := &queue{ready: make(chan string)}
+	q.resetLocked()
+	return q
+}
+
+// Operations outside of lock scopes aren't reported
+func (q *queue) Send(item string) { // want Send:`mayBlock\(strictprofile\.queue:Send\)`
+	q.ready <- item
+	time.Sleep(time.Millisecond)
+}
+
+// Promoted methods of embedded types are named after the type declaring them
+type batch struct {
+	sync.WaitGroup
+	mu sync.Mutex
+}
+
+func (b *batch) Close() { // want Close:`mayBlock\(sync\.WaitGroup:Wait\)`
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.Wait() // want `Blocking call WaitGroup:Wait while holding lock`
+}
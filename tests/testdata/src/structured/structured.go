@@ -0,0 +1,30 @@
+package structured
+
+import "sync"
+
+type Queue struct {
+	mu    sync.Mutex
+	items []int
+}
+
+func (q *Queue) Add(item int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = append(q.items, item)
+	q.notify() // want "Mutex lock is acquired on this line"
+}
+
+func (q *Queue) notify() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+}
+
+func Drain(q *Queue) {
+	q.mu.Lock()
+	if len(q.items) == 0 {
+		return // want "Mutex lock must be released before this line"
+	}
+	q.items = nil
+	q.mu.Unlock()
+}
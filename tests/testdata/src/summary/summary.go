@@ -0,0 +1,47 @@
+package summary
+
+import "sync"
+
+type Queue struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (q *Queue) Add(item string) { // want `Lock summary for Queue:Add\n\tacquires: Queue.mu\n\treturns holding: none\n\trequires: none`
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = append(q.items, item)
+}
+
+func (q *Queue) Flush() { // want `Lock summary for Queue:Flush\n\tacquires: Queue.mu\n\treturns holding: none\n\trequires: none`
+	q.flushAll()
+}
+
+func (q *Queue) Acquire() { // want `Lock summary for Queue:Acquire\n\tacquires: Queue.mu\n\treturns holding: Queue.mu\n\trequires: none`
+	q.mu.Lock()
+}
+
+//mulint:requires mu
+func (q *Queue) Len() int { // want `Lock summary for Queue:Len\n\tacquires: none\n\treturns holding: none\n\trequires: mu`
+	return len(q.items)
+}
+
+func (q *Queue) flushAll() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = nil
+}
+
+func (q *Queue) Release() { // want `Lock summary for Queue:Release\n\tacquires: none\n\treturns holding: none\n\trequires: none`
+	q.mu.Unlock()
+}
+
+// Deferred unlock wrappers release the lock acquired via the lock wrapper
+func (q *Queue) Reset() { // want `Lock summary for Queue:Reset\n\tacquires: Queue.mu\n\treturns holding: none\n\trequires: none`
+	q.Acquire()
+	defer q.Release()
+
+	q.items = q.items[:0]
+}
@@ -0,0 +1,88 @@
+package suspectwrappers
+
+import "sync"
+
+type store struct {
+	mu    sync.Mutex
+	items map[string]string
+	hits  map[string]int
+}
+
+// Missing the unlock: none of the callers release the lock
+func (s *store) touch(key string) {
+	s.mu.Lock() // want "Function store:touch returns holding the lock, but none of its callers release it"
+	s.hits[key]++
+}
+
+func (s *store) Get(key string) string {
+	s.touch(key)
+	return s.items[key]
+}
+
+func (s *store) Bump(key string) {
+	s.touch(key)
+}
+
+func (s *store) lock() {
+	s.mu.Lock()
+}
+
+func (s *store) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *store) Put(key, value string) {
+	s.lock()
+	defer s.unlock()
+
+	s.items[key] = value
+}
+
+// Only some callers release the lock
+func (s *store) begin() {
+	s.mu.Lock()
+}
+
+func (s *store) Delete(key string) {
+	s.begin()
+	delete(s.items, key)
+	s.mu.Unlock()
+}
+
+func (s *store) Clear() {
+	s.begin()
+	s.items = nil
+}
+
+// Exported wrappers may be released by the callers in other packages
+func (s *store) Acquire() {
+	s.mu.Lock()
+}
+
+func (s *store) Reset() {
+	s.Acquire()
+	s.hits = nil
+}
+
+type queue struct {
+	mu    sync.Mutex
+	items []string
+}
+
+// Passing the lock on to the callers, which may release it
+func (q *queue) grab() {
+	q.mu.Lock()
+}
+
+func (q *queue) take() {
+	q.grab()
+}
+
+func (q *queue) Pop() string {
+	q.take()
+	defer q.mu.Unlock()
+
+	item := q.items[0]
+	q.items = q.items[1:]
+	return item
+}
@@ -0,0 +1,60 @@
+package thirdparty
+
+import (
+	"sync"
+
+	. "thirdparty/netclient"
+	nc "thirdparty/netclient"
+)
+
+// Functions of external packages are identified by the package path, however imported
+type pinger struct {
+	mu     sync.Mutex
+	client *nc.Client
+	state  string
+}
+
+func (p *pinger) Aliased() { // want Aliased:`mayBlock\(.*netclient\.Client:Do\)`
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nc.Ping()        // want "Blocking call Ping while holding lock"
+	p.client.Do("X") // want "Blocking call Client:Do while holding lock"
+}
+
+func (p *pinger) DotImported() { // want DotImported:`mayBlock\(.*netclient\.Ping\)`
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	Ping() // want "Blocking call Ping while holding lock"
+}
+
+func (p *pinger) Parenthesized() { // want Parenthesized:`mayBlock\(.*netclient\.Ping\)`
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	(nc.Ping)() // want "Blocking call Ping while holding lock"
+	(Ping)()    // want "Blocking call Ping while holding lock"
+}
+
+func (p *pinger) Instantiated() { // want Instantiated:`mayBlock\(.*netclient\.Await\)`
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nc.Await(p.state)      // want "Blocking call Await while holding lock"
+	nc.Await[string]("ok") // want "Blocking call Await while holding lock"
+	Await[int](1)          // want "Blocking call Await while holding lock"
+}
+
+func (p *pinger) DotImportedCallback() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	Each([]string{p.state}, p.set) // want "Mutex lock is acquired on this line"
+}
+
+func (p *pinger) set(state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state = state
+}
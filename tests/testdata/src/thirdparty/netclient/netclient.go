@@ -0,0 +1,17 @@
+package netclient
+
+type Client struct{}
+
+func (c *Client) Do(cmd string) error { return nil }
+
+func (c *Client) Name() string { return "client" }
+
+func Each(items []string, fn func(string)) {
+	for _, item := range items {
+		fn(item)
+	}
+}
+
+func Ping() error { return nil }
+
+func Await[T any](v T) T { return v }
@@ -0,0 +1,52 @@
+package thirdparty
+
+import (
+	"sync"
+
+	"thirdparty/netclient"
+)
+
+type cache struct {
+	mu     sync.Mutex
+	client *netclient.Client
+	keys   []string
+	seen   map[string]bool
+}
+
+func (c *cache) Flush() { // want Flush:`mayBlock\(.*netclient\.Client:Do\)`
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.client.Do("FLUSH") // want "Blocking call Client:Do while holding lock"
+}
+
+func (c *cache) Sync() { // want Sync:`mayBlock\(.*netclient\.Client:Do\)`
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.push() // want `(?s)Blocking call cache:push while holding lock.*It calls Client:Do, which blocks`
+}
+
+func (c *cache) push() { // want push:`mayBlock\(.*netclient\.Client:Do\)`
+	c.client.Do("PUSH")
+}
+
+func (c *cache) MarkAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	netclient.Each(c.keys, c.mark) // want "Mutex lock is acquired on this line"
+}
+
+func (c *cache) mark(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen[key] = true
+}
+
+func (c *cache) Name() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.client.Name()
+}
@@ -0,0 +1,65 @@
+package timers
+
+import (
+	"sync"
+	"time"
+)
+
+type ticker struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  chan struct{}
+	ticks int
+}
+
+func (t *ticker) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done = make(chan struct{})
+	t.timer = time.AfterFunc(time.Second, func() {
+		t.mu.Lock() // Should NOT be flagged - runs asynchronously
+		defer t.mu.Unlock()
+
+		t.ticks++
+		close(t.done)
+	})
+}
+
+func (t *ticker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.timer.Stop() {
+		<-t.done // want "Waiting for a timer callback that needs the held lock"
+	}
+}
+
+func (t *ticker) StopUnlocked() {
+	if !t.timer.Stop() {
+		<-t.done // Should NOT be flagged - the lock is not held
+	}
+}
+
+type plainTicker struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  chan struct{}
+}
+
+func (t *plainTicker) Start() {
+	t.timer = time.AfterFunc(time.Second, t.fire)
+}
+
+func (t *plainTicker) fire() {
+	t.done <- struct{}{}
+}
+
+func (t *plainTicker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.timer.Stop() {
+		<-t.done // Should NOT be flagged - the callback does not lock
+	}
+}
@@ -0,0 +1,117 @@
+package unlockuse
+
+import "sync"
+
+type queue struct {
+	mu      sync.Mutex
+	items   []string
+	limit   int
+	pending sync.WaitGroup
+	name    string
+}
+
+func (q *queue) Push(item string) {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+
+	if len(q.items) > q.limit { // want "q.items is guarded by q.mu and accessed after releasing the lock"
+		q.trim()
+	}
+}
+
+func (q *queue) trim() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = q.items[1:]
+	q.limit--
+}
+
+func (q *queue) Pop() (string, bool) {
+	q.mu.Lock()
+	if len(q.items) == 0 {
+		q.mu.Unlock()
+		return "", false
+	}
+	item := q.items[0]
+	q.mu.Unlock()
+	q.items = q.items[1:] // want "q.items is guarded by q.mu and accessed after releasing the lock"
+	return item, true
+}
+
+// Values copied under lock are fine, as are the fields never accessed under lock
+func (q *queue) Len() int {
+	q.mu.Lock()
+	n := len(q.items)
+	q.mu.Unlock()
+
+	println(q.name)
+	q.pending.Wait()
+	return n
+}
+
+// The lock is acquired again before the access
+func (q *queue) Drain() []string {
+	q.mu.Lock()
+	items := q.items
+	q.mu.Unlock()
+
+	q.process(items)
+
+	q.mu.Lock()
+	q.items = nil
+	q.mu.Unlock()
+	return items
+}
+
+func (q *queue) process([]string) {}
+
+// Another queue's fields are guarded by another mutex
+func (q *queue) Merge(other *queue) {
+	q.mu.Lock()
+	items := q.items
+	q.mu.Unlock()
+
+	other.Append(items)
+}
+
+func (q *queue) Append(items []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, items...)
+}
+
+// Func literals may run later
+func (q *queue) Later() func() int {
+	q.mu.Lock()
+	q.limit++
+	q.mu.Unlock()
+
+	return func() int {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.limit
+	}
+}
+
+// The deferred function registered first runs last, after the deferred unlock
+func (q *queue) Shift() string {
+	defer func() {
+		println(len(q.items)) // want "q.items is guarded by q.mu and accessed by a deferred function after releasing the lock"
+	}()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item := q.items[0]
+	q.items = q.items[1:]
+	return item
+}
+
+// Deferred after the unlock, the function runs first, under the lock
+func (q *queue) Peek() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer func() {
+		println(len(q.items))
+	}()
+	return q.items[0]
+}
@@ -0,0 +1,44 @@
+package wrappers
+
+import "sync"
+
+type pool struct {
+	mu    sync.Mutex
+	conns []string
+}
+
+func (p *pool) lock() {
+	p.mu.Lock()
+}
+
+func (p *pool) unlock() bool {
+	p.mu.Unlock()
+	return true
+}
+
+func (p *pool) size() int {
+	return len(p.conns)
+}
+
+// Released via the wrapper within a parallel assignment
+func (p *pool) Take() string {
+	p.mu.Lock()
+	if len(p.conns) == 0 {
+		n, _ := p.size(), p.unlock()
+		_ = n
+		return ""
+	}
+	conn := p.conns[0]
+	p.conns = p.conns[1:]
+	p.mu.Unlock()
+	return conn
+}
+
+func (p *pool) Put(conn string) {
+	p.lock()
+	if conn == "" {
+		return // want "Mutex lock must be released before this line"
+	}
+	p.conns = append(p.conns, conn)
+	_ = p.unlock()
+}
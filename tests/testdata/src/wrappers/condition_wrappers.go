@@ -0,0 +1,50 @@
+package wrappers
+
+import "sync"
+
+type gate struct {
+	mu    sync.Mutex
+	queue []string
+}
+
+// enter acquires the gate and reports whether there is pending work
+func (g *gate) enter() bool {
+	g.mu.Lock()
+	return len(g.queue) > 0 // want "Mutex lock must be released before this line"
+}
+
+func (g *gate) leave() {
+	g.mu.Unlock()
+}
+
+func (g *gate) pending() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.queue
+}
+
+func (g *gate) Process() {
+	if g.enter() {
+		g.pending() // want "Mutex lock is acquired on this line"
+	}
+	g.leave()
+}
+
+func (g *gate) Drain() int {
+	if !g.enter() {
+		return 0 // want "Mutex lock must be released before this line"
+	}
+	g.queue = nil
+	g.leave()
+	return 1
+}
+
+func (g *gate) Flush() {
+	switch g.enter() {
+	case true:
+		g.pending() // want "Mutex lock is acquired on this line"
+		g.queue = nil
+	}
+	g.leave()
+}
@@ -0,0 +1,86 @@
+package wrappers
+
+import (
+	"errors"
+	"sync"
+)
+
+var errSealed = errors.New("sealed")
+
+type vault struct {
+	mu     sync.Mutex
+	sealed bool
+	items  map[string]string
+}
+
+// open holds the lock only when it succeeds
+func (v *vault) open() error {
+	v.mu.Lock()
+	if v.sealed {
+		v.mu.Unlock()
+		return errSealed
+	}
+	return nil // want "Mutex lock must be released before this line"
+}
+
+func (v *vault) size() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.items)
+}
+
+// The lock isn't held on the error path
+func (v *vault) Put(key, value string) error {
+	if err := v.open(); err != nil {
+		return err
+	}
+	v.items[key] = value
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *vault) Get(key string) (string, error) {
+	err := v.open()
+	if err != nil {
+		return "", err
+	}
+	defer v.mu.Unlock()
+	return v.items[key], nil
+}
+
+func (v *vault) Len() (int, error) {
+	if err := v.open(); err != nil {
+		return 0, err
+	}
+	n := v.size() // want "Mutex lock is acquired on this line"
+	v.mu.Unlock()
+	return n, nil
+}
+
+func (v *vault) Delete(key string) error {
+	if err := v.open(); err != nil {
+		return err
+	}
+	if _, ok := v.items[key]; !ok {
+		return errors.New("not found") // want "Mutex lock must be released before this line"
+	}
+	delete(v.items, key)
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *vault) Clear() {
+	if v.open() != nil {
+		return
+	}
+	v.items = nil
+	v.mu.Unlock()
+}
+
+func (v *vault) Reset() bool {
+	if v.open() == nil {
+		v.items = make(map[string]string)
+		return true // want "Mutex lock must be released before this line"
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package wrappers
+
+import "errors"
+
+var errBusy = errors.New("busy")
+
+func (g *gate) Refill(items []string) {
+	if ok := g.enter(); ok {
+		g.pending() // want "Mutex lock is acquired on this line"
+		g.queue = append(g.queue, items...)
+	}
+	g.leave()
+}
+
+func (g *gate) Reset() error {
+	switch busy := g.enter() && len(g.queue) > 10; {
+	case busy:
+		return errBusy // want "Mutex lock must be released before this line"
+	}
+	g.queue = nil
+	g.leave()
+	return nil
+}
+
+func (g *gate) Size() int {
+	n := len(g.queue)
+	for ok := g.enter(); ok && n > 0; n-- {
+		g.pending() // want "Mutex lock is acquired on this line"
+	}
+	g.leave()
+	return n
+}
@@ -0,0 +1,49 @@
+package wrappers
+
+import "sync"
+
+type ledger struct {
+	mu      sync.Mutex
+	auditMu sync.Mutex
+	entries []string
+	audit   []string
+}
+
+func (l *ledger) lockAudit() {
+	l.auditMu.Lock()
+}
+
+func (l *ledger) unlockAudit() {
+	l.auditMu.Unlock()
+}
+
+func (l *ledger) auditLen() int {
+	l.auditMu.Lock()
+	defer l.auditMu.Unlock()
+	return len(l.audit)
+}
+
+// Locks l.mu directly and l.auditMu via the wrapper
+func (l *ledger) Append(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	l.lockAudit()
+	l.audit = append(l.audit, entry)
+	if l.auditLen() > 100 { // want "Mutex lock is acquired on this line"
+		l.audit = nil
+	}
+	l.unlockAudit()
+}
+
+func (l *ledger) Rotate() {
+	l.lockAudit()
+	l.audit = nil
+	l.unlockAudit()
+
+	l.mu.Lock()
+	l.entries = nil
+	l.auditLen()
+	l.mu.Unlock()
+}
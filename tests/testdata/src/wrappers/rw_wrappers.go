@@ -0,0 +1,67 @@
+package wrappers
+
+import "sync"
+
+type catalog struct {
+	mu    sync.RWMutex
+	items map[string]int
+}
+
+func (c *catalog) rlock() {
+	c.mu.RLock()
+}
+
+func (c *catalog) runlock() {
+	c.mu.RUnlock()
+}
+
+func (c *catalog) lock() {
+	c.mu.Lock()
+}
+
+func (c *catalog) unlock() {
+	c.mu.Unlock()
+}
+
+func (c *catalog) count() int {
+	c.rlock()
+	defer c.runlock()
+	return len(c.items)
+}
+
+// The read lock acquired via the wrapper is upgraded
+func (c *catalog) Ensure(key string) {
+	c.rlock()
+	defer c.runlock()
+
+	if _, ok := c.items[key]; !ok {
+		c.mu.Lock() // want "^Mutex write lock is acquired on this line while holding the read lock"
+		c.items[key] = 0
+		c.mu.Unlock()
+	}
+}
+
+// The callee acquires the read lock via the wrapper: a recursive read lock, not an upgrade
+func (c *catalog) Empty() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.count() == 0 // want "^Mutex lock is acquired on this line"
+}
+
+// The write lock is released with the reader wrapper
+func (c *catalog) Set(key string, value int) bool {
+	c.lock()
+	defer c.runlock()
+
+	_, existed := c.items[key]
+	c.items[key] = value
+	return !existed // want "Mutex lock must be released before this line"
+}
+
+func (c *catalog) Delete(key string) {
+	c.lock()
+	defer c.unlock()
+
+	delete(c.items, key)
+}
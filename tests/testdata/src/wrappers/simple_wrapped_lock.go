@@ -0,0 +1,90 @@
+package wrappers
+
+import (
+	"sync"
+)
+
+type wrapper struct {
+	m sync.Mutex
+
+	count int
+}
+
+func (w *wrapper) Acquire() {
+	w.m.Lock()
+}
+
+func (w *wrapper) Release() {
+	w.m.Unlock()
+}
+
+func (w *wrapper) Test() {
+	w.Acquire()
+	defer w.Release()
+
+	if w.count > 0 {
+		w.Acquire() // want "Mutex lock is acquired on this line"
+		w.count = 0
+		w.Release()
+	}
+
+}
+
+func (w *wrapper) TestNoErrors() {
+	w.doSomeWork()
+	w.doMoreWork()
+}
+
+func (w *wrapper) doSomeWork() {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.count = 1
+}
+
+func (w *wrapper) doMoreWork() {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.count = 2
+}
+
+type conn struct {
+	mu sync.Mutex
+}
+
+type connPool struct {
+	mu    sync.Mutex
+	conns []*conn
+}
+
+// The returned connection is locked for the caller: it's neither a wrapper locking the pool
+// nor missing an unlock
+func (p *connPool) reserve() *conn {
+	c := &conn{}
+	c.mu.Lock()
+	return c
+}
+
+// Returns leaving the new connection locked without handing it off still miss the unlock
+func (p *connPool) tryReserve(ok bool) *conn {
+	c := &conn{}
+	c.mu.Lock()
+	if !ok {
+		return nil // want "Mutex lock must be released before this line"
+	}
+	return c
+}
+
+func (p *connPool) register(c *conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.conns = append(p.conns, c)
+}
+
+func (p *connPool) Add() *conn {
+	c := p.reserve()
+	p.register(c)
+	c.mu.Unlock()
+	return c
+}
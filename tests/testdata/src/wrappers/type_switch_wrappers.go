@@ -0,0 +1,63 @@
+package wrappers
+
+import "sync"
+
+type event interface{}
+
+type (
+	addEvent   struct{ item string }
+	resetEvent struct{}
+	flushEvent struct{}
+)
+
+type dispatcher struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (d *dispatcher) lock() {
+	d.mu.Lock()
+}
+
+func (d *dispatcher) unlock() {
+	d.mu.Unlock()
+}
+
+func (d *dispatcher) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.items)
+}
+
+// Wrapper calls inside type switch cases open and close scopes
+func (d *dispatcher) Dispatch(e event) {
+	switch e := e.(type) {
+	case addEvent:
+		d.lock()
+		d.items = append(d.items, e.item)
+		d.count() // want "Mutex lock is acquired on this line"
+		d.unlock()
+	case resetEvent:
+		d.lock()
+		d.items = nil
+		d.unlock()
+		d.count()
+	}
+}
+
+func (d *dispatcher) Handle(e event) int {
+	switch e.(type) {
+	case flushEvent:
+		d.lock()
+		if len(d.items) == 0 {
+			return 0 // want "Mutex lock must be released before this line"
+		}
+		d.items = nil
+		d.unlock()
+	case resetEvent:
+		d.lock()
+		defer d.unlock()
+		d.items = nil
+	}
+	return 1
+}
@@ -0,0 +1,41 @@
+package wrappers
+
+import "sync"
+
+type ticket struct {
+	mu   sync.Mutex
+	next int
+}
+
+func (t *ticket) hold() {
+	t.mu.Lock()
+}
+
+func (t *ticket) release() {
+	t.mu.Unlock()
+}
+
+func (t *ticket) peek() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.next
+}
+
+// The lock inside the wrapper is reported along with the wrapper call
+func (t *ticket) Issue() int {
+	t.hold()
+	defer t.release()
+
+	t.next++
+	return t.peek() // want `(?s)Mutex lock is acquired on this line.*\(via ticket:hold\)\n\t[^\n]*wrapper_origins.go:11: Lock is acquired in wrapper ticket:hold: t.mu.Lock\(\)`
+}
+
+func (t *ticket) Skip(n int) int {
+	t.hold()
+	if n < 0 {
+		return 0 // want `(?s)Mutex lock must be released before this line.*\(via ticket:hold\)\n\t[^\n]*wrapper_origins.go:11: Lock is acquired in wrapper ticket:hold: t.mu.Lock\(\)`
+	}
+	t.next += n
+	t.release()
+	return t.next
+}
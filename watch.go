@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/palkan/mulint/mulint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+const loadMode = packages.LoadAllSyntax
+
+// watch runs the analysis on the packages matching the patterns, then re-runs it
+// for the changed packages (and the packages importing them) whenever files change.
+// Only the difference with the previous run is printed.
+func watch(patterns []string, interval time.Duration) int {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	w := newWatcher(patterns, os.Stdout)
+	if err := w.load(patterns...); err != nil {
+		fmt.Fprintf(os.Stderr, "mulint: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(w.out, "mulint: watching %d packages for changes\n", len(w.pkgs))
+
+	for {
+		time.Sleep(interval)
+		if err := w.poll(); err != nil {
+			fmt.Fprintf(os.Stderr, "mulint: %v\n", err)
+		}
+	}
+}
+
+// watcher keeps the findings of the last run per package, so that reloaded
+// packages can be diffed against them.
+type watcher struct {
+	patterns []string
+	out      io.Writer
+
+	pkgs     map[string]*packages.Package    // loaded packages by path
+	findings map[string]map[string]string    // package path -> finding key -> rendered finding
+	files    map[string]map[string]time.Time // package dir -> .go file -> modification time
+}
+
+func newWatcher(patterns []string, out io.Writer) *watcher {
+	return &watcher{
+		patterns: patterns,
+		out:      out,
+		pkgs:     make(map[string]*packages.Package),
+		findings: make(map[string]map[string]string),
+		files:    make(map[string]map[string]time.Time),
+	}
+}
+
+// poll reloads the packages whose files have been changed, added or removed since the last run.
+func (w *watcher) poll() error {
+	var changed []string
+	for dir, files := range w.files {
+		if !sameFiles(files, goFiles(dir)) {
+			changed = append(changed, dir)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// Fall back to reloading everything if the changed files don't belong to the loaded packages
+	paths := w.affected(changed)
+	if len(paths) == 0 {
+		return w.load(w.patterns...)
+	}
+	return w.load(paths...)
+}
+
+// affected returns the paths of the packages located in the changed directories
+// along with all the loaded packages importing them.
+func (w *watcher) affected(dirs []string) []string {
+	affected := make(map[string]bool)
+	for path, pkg := range w.pkgs {
+		for _, dir := range dirs {
+			if packageDir(pkg) == dir {
+				affected[path] = true
+			}
+		}
+	}
+
+	// Importers use the types of the changed packages
+	for grown := true; grown; {
+		grown = false
+		for path, pkg := range w.pkgs {
+			if affected[path] {
+				continue
+			}
+			for imported := range pkg.Imports {
+				if affected[imported] {
+					affected[path] = true
+					grown = true
+					break
+				}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(affected))
+	for path := range affected {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// load (re)loads and analyzes the packages, printing the new and fixed findings.
+func (w *watcher) load(patterns ...string) error {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode}, patterns...)
+	if err != nil {
+		return err
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{mulint.Mulint}, pkgs, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, act := range graph.Roots {
+		pkg := act.Package
+		w.pkgs[pkg.PkgPath] = pkg
+		if dir := packageDir(pkg); dir != "" {
+			w.files[dir] = goFiles(dir)
+		}
+
+		// Keep the previous findings while the package doesn't compile
+		if len(pkg.Errors) > 0 || act.Err != nil {
+			packages.PrintErrors([]*packages.Package{pkg})
+			if act.Err != nil && len(pkg.Errors) == 0 {
+				fmt.Fprintf(os.Stderr, "mulint: %s: %v\n", pkg.PkgPath, act.Err)
+			}
+			continue
+		}
+
+		findings := renderFindings(pkg, act.Diagnostics)
+		w.printDelta(w.findings[pkg.PkgPath], findings)
+		w.findings[pkg.PkgPath] = findings
+	}
+
+	return nil
+}
+
+// printDelta prints the findings that appeared ("+") or were fixed ("-").
+func (w *watcher) printDelta(previous, current map[string]string) {
+	var lines []string
+	for key, finding := range current {
+		if _, ok := previous[key]; !ok {
+			lines = append(lines, "+ "+finding)
+		}
+	}
+	for key, finding := range previous {
+		if _, ok := current[key]; !ok {
+			lines = append(lines, "- "+finding)
+		}
+	}
+	sort.Strings(lines)
+
+	for _, line := range lines {
+		fmt.Fprintln(w.out, line)
+	}
+}
+
+// renderFindings formats the diagnostics as "file:line:col: message" mapped by a key,
+// which doesn't depend on line numbers: editing the code above a finding doesn't report it again.
+func renderFindings(pkg *packages.Package, diagnostics []analysis.Diagnostic) map[string]string {
+	findings := make(map[string]string, len(diagnostics))
+
+	for _, d := range diagnostics {
+		position := pkg.Fset.Position(d.Pos)
+		message := strings.TrimRight(d.Message, "\n")
+		fqn := ""
+		for _, related := range d.Related {
+			if name, ok := strings.CutPrefix(related.Message, "in function "); ok {
+				fqn = name
+			}
+		}
+
+		headline, _, _ := strings.Cut(message, "\n")
+		key := position.Filename + "|" + fqn + "|" + headline
+		for i := 1; ; i++ {
+			if _, exists := findings[key]; !exists {
+				break
+			}
+			key = fmt.Sprintf("%s|%s|%s#%d", position.Filename, fqn, headline, i)
+		}
+
+		findings[key] = fmt.Sprintf("%s:%d:%d: %s", relativePath(position.Filename), position.Line, position.Column, message)
+	}
+
+	return findings
+}
+
+// packageDir returns the directory of the package source files.
+func packageDir(pkg *packages.Package) string {
+	if len(pkg.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+// goFiles returns the modification times of the Go files in the directory.
+func goFiles(dir string) map[string]time.Time {
+	files := make(map[string]time.Time)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files[filepath.Join(dir, entry.Name())] = info.ModTime()
+		}
+	}
+	return files
+}
+
+func sameFiles(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, mtime := range a {
+		if other, ok := b[name]; !ok || !other.Equal(mtime) {
+			return false
+		}
+	}
+	return true
+}
+
+// relativePath returns the path relative to the current working directory.
+func relativePath(filename string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return filename
+	}
+	if rel, err := filepath.Rel(cwd, filename); err == nil {
+		return rel
+	}
+	return filename
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const reentrantSource = `package app
+
+import "sync"
+
+type Queue struct {
+	mu sync.Mutex
+}
+
+func (q *Queue) Add() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.mu.Lock()
+}
+`
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.22\n")
+	source := filepath.Join(dir, "queue.go")
+	writeFile(t, source, reentrantSource)
+	t.Chdir(dir)
+
+	var out bytes.Buffer
+	w := newWatcher([]string{"./..."}, &out)
+	if err := w.load("./..."); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "+ queue.go:12:2: Mutex lock is acquired on this line") {
+		t.Fatalf("expected a new finding, got:\n%s", out.String())
+	}
+
+	// Shifting the code doesn't report the finding again
+	out.Reset()
+	writeFile(t, source, strings.Replace(reentrantSource, "type Queue", "// Queue is a queue\ntype Queue", 1))
+	if err := w.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no changes, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	writeFile(t, source, strings.Replace(reentrantSource, "\tq.mu.Lock()\n}", "}", 1))
+	if err := w.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "- queue.go:13:2: Mutex lock is acquired on this line") {
+		t.Fatalf("expected a fixed finding, got:\n%s", out.String())
+	}
+}
+
+// writeFile writes the file and bumps its modification time, so that quick
+// successive writes are detected as changes.
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	var mtime time.Time
+	if info, err := os.Stat(path); err == nil {
+		mtime = info.ModTime().Add(time.Second)
+	} else {
+		mtime = time.Now()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// expandWorkspace replaces the -workspace flag with the patterns matching
+// all the packages of the modules used by the current go.work file.
+// The packages are loaded in the workspace mode, so cross-module calls
+// are resolved to the workspace modules instead of their released versions.
+func expandWorkspace(args []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	found := false
+	for i, arg := range args {
+		if arg == "--" {
+			expanded = append(expanded, args[i:]...)
+			break
+		}
+		switch strings.TrimLeft(arg, "-") {
+		case "workspace", "workspace=true", "workspace=1":
+			found = true
+		case "workspace=false", "workspace=0":
+		default:
+			expanded = append(expanded, arg)
+		}
+	}
+	if !found {
+		return args, nil
+	}
+
+	patterns, err := workspacePatterns()
+	if err != nil {
+		return nil, err
+	}
+	return append(expanded, patterns...), nil
+}
+
+// workspacePatterns returns the "<module>/..." patterns of all the modules used by go.work.
+func workspacePatterns() ([]string, error) {
+	out, err := exec.Command("go", "env", "GOWORK").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate go.work: %w", err)
+	}
+	gowork := strings.TrimSpace(string(out))
+	if gowork == "" || gowork == "off" {
+		return nil, errors.New("-workspace requires a go.work file")
+	}
+
+	data, err := os.ReadFile(gowork)
+	if err != nil {
+		return nil, err
+	}
+	work, err := modfile.ParseWork(gowork, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cwd, _ := os.Getwd()
+	patterns := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(gowork), dir)
+		}
+		// Prefer relative patterns for shorter paths in the output
+		if rel, err := filepath.Rel(cwd, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = "./" + filepath.ToSlash(rel)
+		}
+		patterns = append(patterns, strings.TrimSuffix(dir, "/.")+"/...")
+	}
+	return patterns, nil
+}
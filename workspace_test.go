@@ -0,0 +1,31 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestExpandWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.work"), "go 1.22\n\nuse (\n\t./api\n\t./worker\n)\n")
+	writeFile(t, filepath.Join(dir, "api", "go.mod"), "module example.com/api\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "worker", "go.mod"), "module example.com/worker\n\ngo 1.22\n")
+	t.Chdir(dir)
+	t.Setenv("GOWORK", "")
+
+	args, err := expandWorkspace([]string{"-group", "-workspace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"-group", "./api/...", "./worker/..."}
+	if !slices.Equal(args, expected) {
+		t.Errorf("expected %v, got %v", expected, args)
+	}
+
+	args, _ = expandWorkspace([]string{"-group", "./..."})
+	if !slices.Equal(args, []string{"-group", "./..."}) {
+		t.Errorf("expected arguments to stay unchanged without -workspace, got %v", args)
+	}
+}